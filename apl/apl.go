@@ -4,8 +4,8 @@ package apl
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
 	"reflect"
+	"sync"
 
 	"github.com/ktye/iv/apl/scan"
 	// _ "github.com/ktye/iv/apl/funcs" // Register default funcs
@@ -14,37 +14,83 @@ import (
 // New starts a new interpreter.
 func New(w io.Writer) *Apl {
 	a := Apl{
-		stdout:     w,
-		env:        newEnv(),
-		Origin:     1,
-		PP:         0,
-		Fmt:        make(map[reflect.Type]string),
-		primitives: make(map[Primitive][]PrimitiveHandler),
-		operators:  make(map[string][]Operator),
-		symbols:    make(map[rune]string),
-		pkg:        make(map[string]*env),
+		stdout: &output{w: w},
+		env:    newEnv(),
+		Origin: 1,
+		PP:     0,
+		CT:     1e-14,
+		Fmt:    make(map[reflect.Type]string),
+		registry: &registry{
+			primitives: make(map[Primitive][]PrimitiveHandler),
+			operators:  make(map[string][]Operator),
+			inverses:   make(map[inverseKey]Function),
+			symbols:    make(map[rune]string),
+			pkg:        make(map[string]*env),
+		},
 	}
 	a.parser.a = &a
 	return &a
 }
 
 // Apl stores the interpreter state.
+//
+// The state consists of the registry, which is shared by all forks,
+// and the evaluation context: the current environment, Origin, PP, CT, Fmt
+// and the last error.
+// An Apl must not be used by multiple goroutines at the same time.
+// Concurrent evaluations each use their own Fork.
 type Apl struct {
 	scan.Scanner
 	parser
-	stdout     io.Writer
-	stdimg     ImageWriter
-	Tower      Tower
-	Origin     int
-	PP         int
-	Fmt        map[reflect.Type]string
-	env        *env
+	*registry
+	stdout    *output
+	stdimg    ImageWriter
+	Tower     Tower
+	Origin    int
+	PP        int
+	CT        float64
+	Parallel  int
+	Fmt       map[reflect.Type]string
+	env       *env
+	lastError *trapError
+	scaninit  bool
+	debug     bool
+}
+
+// registry contains the registered functions, operators and packages.
+// It is shared by forks of an interpreter and guarded by mu.
+type registry struct {
+	mu         sync.RWMutex
 	primitives map[Primitive][]PrimitiveHandler
 	operators  map[string][]Operator
+	inverses   map[inverseKey]Function
 	symbols    map[rune]string
 	pkg        map[string]*env
-	scaninit   bool
-	debug      bool
+	generation int64
+}
+
+// Fork returns a copy of the interpreter, that can evaluate concurrently with a.
+// It shares the registered functions, operators and packages, the variables of a
+// and the output device.
+// It has it's own evaluation context: the current environment, Origin, PP, CT, Fmt
+// and the output set by SetOutput.
+// Changes of the context are not visible to a.
+//
+// Fork must be called by the goroutine that uses a, and the fork is passed
+// to the goroutine that evaluates concurrently, e.g.:
+//	b := a.Fork()
+//	go f.Call(b, L, R)
+//
+// Variables and registrations are visible to all forks.
+// Accessing them is synchronized, but the order between goroutines is not defined.
+func (a *Apl) Fork() *Apl {
+	b := *a
+	b.parser = parser{a: &b}
+	b.Fmt = make(map[reflect.Type]string, len(a.Fmt))
+	for t, s := range a.Fmt {
+		b.Fmt[t] = s
+	}
+	return &b
 }
 
 // LoadPkg loads a package from a file.
@@ -61,7 +107,7 @@ func (a *Apl) LoadPkg(r io.Reader, file string, pkg string) (err error) {
 	if err != nil {
 		return err
 	}
-	a.pkg[pkg] = a.env
+	a.setPkg(pkg, a.env)
 	return nil
 }
 
@@ -74,7 +120,7 @@ func (a *Apl) Parse(line string) (Program, error) {
 	}
 
 	if err != nil {
-		return nil, err
+		return nil, syntaxError(err)
 	}
 
 	p, err := a.parse(tokens)
@@ -83,7 +129,7 @@ func (a *Apl) Parse(line string) (Program, error) {
 	}
 
 	if err != nil {
-		return nil, err
+		return nil, syntaxError(err)
 	} else {
 		return p, nil
 	}
@@ -102,9 +148,11 @@ func (a *Apl) Scan(line string) ([]scan.Token, error) {
 	// have been registered.
 	if a.scaninit == false {
 		m := make(map[rune]string)
+		a.mu.RLock()
 		for r, s := range a.symbols {
 			m[r] = s
 		}
+		a.mu.RUnlock()
 		a.SetSymbols(m)
 		a.scaninit = true
 	}
@@ -115,14 +163,20 @@ func (a *Apl) SetDebug(d bool) {
 	a.debug = d
 }
 
+// SetOutput sets the output device of the interpreter.
+// Forks that are created later inherit it, existing forks keep their output.
 func (a *Apl) SetOutput(w io.Writer) {
-	a.stdout = w
+	if o, ok := w.(*output); ok {
+		a.stdout = o
+		return
+	}
+	a.stdout = &output{w: w}
 }
 
+// GetOutput returns the output device.
+// Writes are synchronized with forks, that share the device.
+// The returned value can be passed to SetOutput to restore the device.
 func (a *Apl) GetOutput() io.Writer {
-	if a.stdout == nil {
-		return ioutil.Discard
-	}
 	return a.stdout
 }
 
@@ -130,6 +184,21 @@ func (a *Apl) SetImage(w ImageWriter) {
 	a.stdimg = w
 }
 
+// output serializes writes of forks that share the output device.
+type output struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.w == nil {
+		return len(p), nil // discard
+	}
+	return o.w.Write(p)
+}
+
 func newEnv() *env {
 	return &env{vars: map[string]Value{}}
 }
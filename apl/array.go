@@ -107,6 +107,13 @@ type Reshaper interface {
 	Reshape([]int) Value
 }
 
+// Appender is a uniform vector that can append values of it's type.
+// Append returns false, if a value has another type.
+// The result may share memory with the original array.
+type Appender interface {
+	Append([]Value) (Array, bool)
+}
+
 // ArrayMaker is an array that can allocate a new array of it's type.
 // An array that implements this interface can be assumed to be able to
 // create arrays of itself for shape with elements >= 0.
@@ -122,6 +129,7 @@ type ArraySetter interface {
 
 // MakeArray creates a new array.
 // It makes an array of the same type as the prototype, if it can.
+// This is the case for an ArrayMaker or a Uniform array with a Set method.
 // Otherwise it returns a mixed array.
 // The prototype may be nil.
 func MakeArray(prototype Array, shape []int) ArraySetter {
@@ -129,6 +137,10 @@ func MakeArray(prototype Array, shape []int) ArraySetter {
 	if prototype != nil {
 		if m, ok := prototype.(ArrayMaker); ok {
 			am = m
+		} else if u, ok := prototype.(Uniform); ok {
+			if s, ok := u.Make(shape).(ArraySetter); ok {
+				return s
+			}
 		}
 	}
 
@@ -144,7 +156,7 @@ func MakeArray(prototype Array, shape []int) ArraySetter {
 // ArrayBounds does bounds checking on an array given a flat index.
 func ArrayBounds(v Array, i int) error {
 	if i < 0 || i >= v.Size() {
-		return fmt.Errorf("index out of range")
+		return Errorf(IndexError, "index out of range")
 	}
 	return nil
 }
@@ -230,12 +242,20 @@ func (v MixedArray) At(i int) Value {
 
 func (v MixedArray) Set(i int, e Value) error {
 	if i < 0 || i >= len(v.Values) {
-		return fmt.Errorf("index out of range")
+		return Errorf(IndexError, "index out of range")
 	}
 	v.Values[i] = e
 	return nil
 }
 
+func (v MixedArray) Copy() Value {
+	r := MixedArray{Dims: CopyShape(v), Values: make([]Value, len(v.Values))}
+	for i, e := range v.Values {
+		r.Values[i] = Copy(e)
+	}
+	return r
+}
+
 func (v MixedArray) Shape() []int {
 	return v.Dims
 }
@@ -260,7 +280,12 @@ func (v MixedArray) Reshape(shape []int) Value {
 
 type EmptyArray struct{}
 
-func (e EmptyArray) String(a *Apl) string       { return "" }
+func (e EmptyArray) String(a *Apl) string {
+	if a.PP == -1 {
+		return "[]"
+	}
+	return ""
+}
 func (e EmptyArray) Eval(a *Apl) (Value, error) { return e, nil }
 func (e EmptyArray) At(i int) Value             { return nil }
 func (e EmptyArray) Shape() []int               { return nil }
@@ -305,7 +330,7 @@ func (ar IntArray) Shape() []int {
 
 func (ar IntArray) Set(i int, v Value) error {
 	if i < 0 || i >= len(ar.Ints) {
-		return fmt.Errorf("index out of range")
+		return Errorf(IndexError, "index out of range")
 	}
 	n, ok := v.(Int)
 	if ok {
@@ -327,6 +352,27 @@ func (s IntArray) Make(shape []int) Array {
 	}
 }
 
+func (ar IntArray) Copy() Value {
+	r := IntArray{Dims: CopyShape(ar), Ints: make([]int, len(ar.Ints))}
+	copy(r.Ints, ar.Ints)
+	return r
+}
+
+func (ar IntArray) Append(v []Value) (Array, bool) {
+	if len(ar.Dims) != 1 {
+		return ar, false
+	}
+	ints := ar.Ints
+	for _, e := range v {
+		n, ok := e.(Int)
+		if ok == false {
+			return ar, false
+		}
+		ints = append(ints, int(n))
+	}
+	return IntArray{Dims: []int{len(ints)}, Ints: ints}, true
+}
+
 func makeIntArray(v []Value) IntArray {
 	b := make([]int, len(v))
 	for i, e := range v {
@@ -26,6 +26,7 @@ type Assignment struct {
 	Identifier  string
 	Identifiers []string // Multiple identifiers for vector assignment
 	Indexes     Value    // Should be convertible to an Index vector
+	Path        Value    // Reach indexed assignment: (Path⊃X)←V
 	Modifier    Value
 }
 
@@ -33,6 +34,8 @@ func (as *Assignment) String(a *Apl) string {
 	s := ""
 	if as.Indexes != nil {
 		s = "indexed/selective "
+	} else if as.Path != nil {
+		s = "reach "
 	}
 	if as.Modifier != nil {
 		s += "modified "
@@ -76,6 +79,22 @@ func evalAssign(a *Apl, e expr, modifier Value) (Value, error) {
 		return &as, nil
 	}
 
+	// Reach indexed assignment: (Path⊃X)←V
+	// The path is evaluated and stored instead of an index array.
+	if fn, ok := e.(*function); ok && fn.left != nil {
+		if p, ok := fn.Function.(Primitive); ok && p == "⊃" {
+			if nv, ok := fn.right.(numVar); ok {
+				path, err := fn.left.Eval(a)
+				if err != nil {
+					return nil, err
+				}
+				as.Identifier = nv.name
+				as.Path = path
+				return &as, nil
+			}
+		}
+	}
+
 	// The identifier is the right-most argument in the expression.
 	// The selection function (if present) is the function left to the Identifier.
 	selection := false
@@ -96,11 +115,30 @@ search:
 	}
 
 	if selection {
-		if idx, err := e.Eval(a); err != nil {
+		// Selecting a missing key of a dict inserts it with an empty value, see Dict.Set.
+		// The keys are removed again, if the selection fails or for modified assignment,
+		// which requires existing keys.
+		v, _ := a.LookupOwned(as.Identifier)
+		d, _ := v.(*Dict)
+		n := 0
+		if d != nil {
+			n = len(d.K)
+		}
+		idx, err := e.Eval(a)
+		if d != nil && len(d.K) > n && (err != nil || modifier != nil) {
+			k := d.K[n]
+			for _, k := range d.K[n:] {
+				delete(d.M, k)
+			}
+			d.K = d.K[:n:n]
+			if err == nil {
+				err = Errorf(IndexError, "modified assignment: key does not exist: %s", k.String(a))
+			}
+		}
+		if err != nil {
 			return nil, err
-		} else {
-			as.Indexes = idx
 		}
+		as.Indexes = idx
 	}
 
 	return &as, nil
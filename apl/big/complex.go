@@ -30,6 +30,9 @@ func (c Complex) String(a *apl.Apl) string {
 		format = "%.6GJ%.6G"
 	}
 	s := fmt.Sprintf(format, c.re, c.im)
+	if a.PP == -1 {
+		s = strings.Replace(s, "e+", "e", -1)
+	}
 	if minus == false {
 		s = strings.Replace(s, "-", "¯", -1)
 	}
@@ -39,7 +42,7 @@ func (c Complex) String(a *apl.Apl) string {
 func ParseComplex(s string, prec uint) (apl.Number, bool) {
 	// If the number is in polar form, parse with numbers.Complex,
 	// neglecting additional precision.
-	if strings.Index(s, "a") != 0 {
+	if strings.Index(s, "a") != -1 {
 		z, ok := numbers.ParseComplex(s)
 		if ok == false {
 			return nil, false
@@ -79,7 +82,12 @@ func (c Complex) ToIndex() (int, bool) {
 	return Float{c.re}.ToIndex()
 }
 
+// floatToComplex converts a Float to a Complex.
+// The null value cannot be converted.
 func floatToComplex(f apl.Number) (apl.Number, bool) {
+	if f.(Float).IsNull() {
+		return f, false
+	}
 	z := f.(Float).cpy()
 	return Complex{z, new(big.Float)}, true
 }
@@ -94,6 +102,19 @@ func (c Complex) Equals(R apl.Value) (apl.Bool, bool) {
 	return apl.Bool(c.re.Cmp(z.re) == 0 && c.im.Cmp(z.im) == 0), true
 }
 
+// TolerantEquals compares two Complex numbers with the comparison tolerance ct.
+// The magnitude of the difference is compared with the larger magnitude.
+func (c Complex) TolerantEquals(R apl.Value, ct float64) (apl.Bool, bool) {
+	if eq, _ := c.Equals(R); eq {
+		return true, true
+	}
+	z := R.(Complex)
+	d := c.cpy()
+	d.re = d.re.Sub(d.re, z.re)
+	d.im = d.im.Sub(d.im, z.im)
+	return apl.Bool(d.abs().Cmp(tolerance(c.cpy().abs(), z.cpy().abs(), ct)) <= 0), true
+}
+
 func (c Complex) Add() (apl.Value, bool) {
 	z := c.cpy()
 	z.im = z.im.Neg(z.im)
@@ -15,7 +15,16 @@ type Float struct {
 	*big.Float
 }
 
+// nullFloat is the null value of a Float, written as 0n.
+var nullFloat = new(big.Float)
+
+func (f Float) IsNull() bool    { return f.Float == nullFloat }
+func (f Float) Null() apl.Value { return Float{nullFloat} }
+
 func (f Float) String(a *apl.Apl) string {
+	if f.IsNull() {
+		return apl.NullString(a, "0n")
+	}
 	format, minus := getformat(a, f)
 	if format == "" {
 		if a.PP < 0 {
@@ -30,6 +39,9 @@ func (f Float) String(a *apl.Apl) string {
 	if s == "-0" {
 		s = "0"
 	}
+	if a.PP == -1 {
+		s = strings.Replace(s, "e+", "e", 1)
+	}
 	if minus == false {
 		s = strings.Replace(s, "-", "¯", -1)
 	}
@@ -37,6 +49,9 @@ func (f Float) String(a *apl.Apl) string {
 }
 
 func ParseFloat(s string, prec uint) (apl.Number, bool) {
+	if s == "0n" {
+		return Float{nullFloat}, true
+	}
 	s = strings.Replace(s, "¯", "-", -1)
 	z, _, err := big.NewFloat(0).SetPrec(prec).Parse(s, 10)
 	if err != nil {
@@ -46,7 +61,7 @@ func ParseFloat(s string, prec uint) (apl.Number, bool) {
 }
 
 func (f Float) ToIndex() (int, bool) {
-	if f.IsInt() == false {
+	if f.IsNull() || f.IsInt() == false {
 		return 0, false
 	}
 	i, _ := f.Float.Int64()
@@ -66,6 +81,37 @@ func (f Float) Equals(R apl.Value) (apl.Bool, bool) {
 	return f.Float.Cmp(R.(Float).Float) == 0, true
 }
 
+// ToFloat converts a Float to a float64.
+// The null value is converted to NaN.
+func (f Float) ToFloat() (float64, bool) {
+	if f.IsNull() {
+		return math.NaN(), true
+	}
+	z, _ := f.Float.Float64()
+	return z, true
+}
+
+// TolerantEquals compares two Floats with the comparison tolerance ct:
+//	|L-R| ≤ ct × (|L|⌈|R|)
+func (f Float) TolerantEquals(R apl.Value, ct float64) (apl.Bool, bool) {
+	r := R.(Float).Float
+	if f.Float.Cmp(r) == 0 {
+		return true, true
+	}
+	d := f.cpy()
+	d = d.Abs(d.Sub(d, r))
+	return apl.Bool(d.Cmp(tolerance(f.Float, r, ct)) <= 0), true
+}
+
+// tolerance returns ct × (|l|⌈|r|).
+func tolerance(l, r *big.Float, ct float64) *big.Float {
+	m := new(big.Float).SetPrec(l.Prec()).Abs(l)
+	if a := new(big.Float).Abs(r); a.Cmp(m) > 0 {
+		m = m.Set(a)
+	}
+	return m.Mul(m, big.NewFloat(ct))
+}
+
 func (f Float) Less(R apl.Value) (apl.Bool, bool) {
 	return f.Float.Cmp(R.(Float).Float) < 0, true
 }
@@ -16,8 +16,18 @@ type Int struct {
 	*big.Int
 }
 
+// nullInt is the null value of an Int, written as 0N.
+// It is identified by the pointer, not by it's value.
+var nullInt = new(big.Int)
+
+func (i Int) IsNull() bool    { return i.Int == nullInt }
+func (i Int) Null() apl.Value { return Int{nullInt} }
+
 func (i Int) String(a *apl.Apl) string {
 	// TODO formats
+	if i.IsNull() {
+		return apl.NullString(a, "0N")
+	}
 	s := i.Int.String()
 	if s[0] == '-' {
 		return "¯" + s[1:]
@@ -36,7 +46,7 @@ func ParseInt(s string) (apl.Number, bool) {
 }
 
 func (i Int) ToIndex() (int, bool) {
-	if i.Int.IsInt64() == false {
+	if i.IsNull() || i.Int.IsInt64() == false {
 		return 0, false
 	}
 	n64 := i.Int.Int64()
@@ -47,6 +57,9 @@ func (i Int) ToIndex() (int, bool) {
 }
 
 func intToRat(i apl.Number) (apl.Number, bool) {
+	if i.(Int).IsNull() {
+		return Rat{nullRat}, true
+	}
 	r := new(big.Rat)
 	r = r.SetInt(i.(Int).Int)
 	return Rat{r}, true
@@ -17,7 +17,16 @@ type Rat struct {
 	*big.Rat
 }
 
+// nullRat is the null value of a Rat, written as 0N.
+var nullRat = new(big.Rat)
+
+func (r Rat) IsNull() bool    { return r.Rat == nullRat }
+func (r Rat) Null() apl.Value { return Rat{nullRat} }
+
 func (r Rat) String(a *apl.Apl) string {
+	if r.IsNull() {
+		return apl.NullString(a, "0N")
+	}
 	format, minus := getformat(a, r)
 	if format == "" {
 		s := r.Rat.String()
@@ -39,13 +48,23 @@ func (r Rat) String(a *apl.Apl) string {
 }
 
 func (r Rat) ToIndex() (int, bool) {
-	if r.Rat.IsInt() == false {
+	if r.IsNull() || r.Rat.IsInt() == false {
 		return 0, false
 	}
 	i := r.Rat.Num()
 	return Int{i}.ToIndex()
 }
 
+// ToFloat converts a Rat to the nearest float64.
+// The null value is converted to NaN.
+func (r Rat) ToFloat() (float64, bool) {
+	if r.IsNull() {
+		return math.NaN(), true
+	}
+	f, _ := r.Rat.Float64()
+	return f, true
+}
+
 func ParseRat(s string) (apl.Number, bool) {
 	s = strings.Replace(s, "¯", "-", -1)
 	s = strings.Replace(s, "r", "/", 1)
@@ -41,6 +41,9 @@ func SetBigTower(a *apl.Apl) {
 				}
 				return Int{big.NewInt(0)}
 			} else if n, ok := n.(apl.Int); ok {
+				if n.IsNull() {
+					return Int{nullInt}
+				}
 				return Int{big.NewInt(int64(n))}
 			}
 			return n
@@ -74,6 +77,9 @@ func SetPreciseTower(a *apl.Apl, prec uint) {
 				}
 				return Float{big.NewFloat(0).SetPrec(prec)}
 			} else if n, ok := n.(apl.Int); ok {
+				if n.IsNull() {
+					return Float{nullFloat}
+				}
 				f := Float{big.NewFloat(float64(n)).SetPrec(prec)}
 				return f
 			}
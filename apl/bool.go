@@ -2,6 +2,7 @@ package apl
 
 import (
 	"fmt"
+	"math/bits"
 )
 
 type Bool bool
@@ -35,9 +36,99 @@ func (i Bool) ToIndex() (int, bool) {
 }
 
 // BoolArray is a uniform array of type bool.
+// The values are packed into 64 bit words: element i is bit i%64 of Bits[i/64].
+// Bits beyond the size of the array are always 0, such that functions
+// on whole words, e.g. a population count, need not to mask the last word.
 type BoolArray struct {
-	Dims  []int
-	Bools []bool
+	Dims []int
+	Bits []uint64
+}
+
+// NewBoolArray returns a BoolArray with the given shape.
+// If v is not nil, it contains the values, otherwise all values are 0.
+func NewBoolArray(shape []int, v []bool) BoolArray {
+	b := BoolArray{Dims: shape, Bits: make([]uint64, Words(prod(shape)))}
+	for i, x := range v {
+		if x {
+			b.Bits[i>>6] |= 1 << uint(i&63)
+		}
+	}
+	return b
+}
+
+// Words returns the number of 64 bit words that store n bits.
+func Words(n int) int {
+	return (n + 63) >> 6
+}
+
+// Bool returns element i.
+func (b BoolArray) Bool(i int) bool {
+	return b.Bits[i>>6]&(1<<uint(i&63)) != 0
+}
+
+// SetBool sets element i.
+func (b BoolArray) SetBool(i int, v bool) {
+	if v {
+		b.Bits[i>>6] |= 1 << uint(i&63)
+	} else {
+		b.Bits[i>>6] &^= 1 << uint(i&63)
+	}
+}
+
+// Bools returns the values unpacked, one bool per element.
+func (b BoolArray) Bools() []bool {
+	v := make([]bool, b.Size())
+	for i := range v {
+		v[i] = b.Bool(i)
+	}
+	return v
+}
+
+// Count returns the number of 1s.
+func (b BoolArray) Count() int {
+	n := 0
+	for _, w := range b.Bits {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// CountRange returns the number of 1s in the elements from i to j-1.
+func (b BoolArray) CountRange(i, j int) int {
+	if i >= j {
+		return 0
+	}
+	lo, hi := i>>6, (j-1)>>6
+	first := ^uint64(0) << uint(i&63)
+	last := ^uint64(0) >> uint(63-(j-1)&63)
+	if lo == hi {
+		return bits.OnesCount64(b.Bits[lo] & first & last)
+	}
+	n := bits.OnesCount64(b.Bits[lo]&first) + bits.OnesCount64(b.Bits[hi]&last)
+	for _, w := range b.Bits[lo+1 : hi] {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Where returns the indexes of all 1s in increasing order.
+func (b BoolArray) Where() []int {
+	idx := make([]int, 0, b.Count())
+	for k, w := range b.Bits {
+		for w != 0 {
+			idx = append(idx, k<<6+bits.TrailingZeros64(w))
+			w &= w - 1
+		}
+	}
+	return idx
+}
+
+// ClearTail sets the bits in the last word beyond the size of the array to 0.
+// It must be called after word operations that may set them, e.g. a negation.
+func (b BoolArray) ClearTail() {
+	if n := b.Size() & 63; n != 0 && len(b.Bits) > 0 {
+		b.Bits[len(b.Bits)-1] &= 1<<uint(n) - 1
+	}
 }
 
 func (b BoolArray) String(a *Apl) string {
@@ -45,7 +136,7 @@ func (b BoolArray) String(a *Apl) string {
 }
 
 func (b BoolArray) At(i int) Value {
-	return Bool(b.Bools[i])
+	return Bool(b.Bool(i))
 }
 
 func (b BoolArray) Shape() []int {
@@ -53,7 +144,7 @@ func (b BoolArray) Shape() []int {
 }
 
 func (b BoolArray) Size() int {
-	return len(b.Bools)
+	return prod(b.Dims)
 }
 
 func (b BoolArray) Zero() Value {
@@ -61,44 +152,58 @@ func (b BoolArray) Zero() Value {
 }
 
 func (b BoolArray) Set(i int, v Value) error {
-	if i < 0 || i > len(b.Bools) {
-		return fmt.Errorf("index out of range")
+	if i < 0 || i >= b.Size() {
+		return Errorf(IndexError, "index out of range")
 	}
 	if c, ok := v.(Bool); ok {
-		b.Bools[i] = bool(c)
+		b.SetBool(i, bool(c))
 		return nil
 	}
 	return fmt.Errorf("cannot assign %T to BoolArray", v)
 }
 
 func (s BoolArray) Make(shape []int) Array {
-	return BoolArray{
-		Dims:  shape,
-		Bools: make([]bool, prod(shape)),
-	}
+	return NewBoolArray(shape, nil)
+}
+
+func (b BoolArray) Copy() Value {
+	r := BoolArray{Dims: CopyShape(b), Bits: make([]uint64, len(b.Bits))}
+	copy(r.Bits, b.Bits)
+	return r
 }
 
 func makeBoolArray(v []Value) BoolArray {
-	b := make([]bool, len(v))
+	b := NewBoolArray([]int{len(v)}, nil)
 	for i, e := range v {
-		b[i] = bool(e.(Bool))
-	}
-	return BoolArray{
-		Dims:  []int{len(v)},
-		Bools: b,
+		if e.(Bool) {
+			b.SetBool(i, true)
+		}
 	}
+	return b
 }
 
 func (b BoolArray) Reshape(shape []int) Value {
-	res := BoolArray{
-		Dims:  shape,
-		Bools: make([]bool, prod(shape)),
+	res := NewBoolArray(shape, nil)
+	n := b.Size()
+	if n == 0 {
+		return res
+	}
+	size := res.Size()
+	if n&63 == 0 {
+		// Whole words are repeated.
+		for i := range res.Bits {
+			res.Bits[i] = b.Bits[i%len(b.Bits)]
+		}
+		res.ClearTail()
+		return res
 	}
 	k := 0
-	for i := range res.Bools {
-		res.Bools[i] = b.Bools[k]
+	for i := 0; i < size; i++ {
+		if b.Bool(k) {
+			res.SetBool(i, true)
+		}
 		k++
-		if k == len(b.Bools) {
+		if k == n {
 			k = 0
 		}
 	}
@@ -43,7 +43,9 @@ func (c Channel) Close() {
 
 // scope return a channel and copies values from R[0].
 // It is called by scope assignment: ⎕←R.
+// The values are formatted on a fork of a.
 func (R Channel) Scope(a *Apl) Channel {
+	a = a.Fork()
 	c := NewChannel()
 	go func(r Channel) {
 		defer close(c[0])
@@ -79,7 +81,9 @@ func (R Channel) Scope(a *Apl) Channel {
 // L (may be nil) is used as a left value for f.
 // If L is also a channel, a value is read each time, before applying f.
 // If filter is true, values are skipped if f returns an EmptyArray.
+// The function is called on a fork of a, which evaluates concurrently.
 func (R Channel) Apply(a *Apl, f Function, L Value, filter bool) Channel {
+	a = a.Fork()
 	lv := L
 	l, lc := L.(Channel)
 
@@ -144,9 +148,11 @@ func LineReader(rc io.ReadCloser) Channel {
 }
 
 // NewChannelReader converts a channel to an io.Reader.
+// The values are formatted on a fork of a,
+// as the reader may be used by another goroutine.
 func NewChannelReader(a *Apl, c Channel) *ChannelReader {
 	return &ChannelReader{
-		a: a,
+		a: a.Fork(),
 		c: c,
 	}
 }
@@ -181,7 +187,7 @@ func (r *ChannelReader) Read(p []byte) (n int, err error) {
 				} else {
 					r.buf.WriteRune('\n')
 				}
-				r.buf.WriteString(v.String(r.a)) // TODO: this could be a race when formatting.
+				r.buf.WriteString(v.String(r.a))
 			}
 		}
 	}
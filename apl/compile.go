@@ -0,0 +1,203 @@
+package apl
+
+import (
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// Compile returns a compiled version of the program, which is evaluated with Eval
+// or EvalProgram in the same way as the original.
+//
+// Calls to primitive functions and variable lookups are converted to closures.
+// Lambda expressions are compiled, including their bodies, and stay compiled
+// when they are assigned to a function variable.
+// Other expressions are evaluated by the interpreter, with compiled arguments.
+//
+// A call to a primitive function caches the handlers for the types of the last arguments.
+// Handlers, whose domain rejects the types, are not tested again, see TypeDomain.
+// The cache is invalidated, if a primitive is registered.
+//
+// The program is not modified and can still be evaluated by the interpreter.
+//
+// BenchmarkCompile in apl/primitives compares both for lambdas that are called for each element:
+// the compiled programs need 15-25% less time for each (¨), and 40% less for a recursive lambda (∇).
+func (a *Apl) Compile(p Program) Program {
+	c := make(Program, len(p))
+	for i, e := range p {
+		c[i] = compile(e)
+	}
+	return c
+}
+
+// compiled is an expression that is evaluated by a closure.
+// It keeps the original expression for printing.
+type compiled struct {
+	e expr
+	f func(*Apl) (Value, error)
+}
+
+func (c compiled) Eval(a *Apl) (Value, error) { return c.f(a) }
+func (c compiled) String(a *Apl) string       { return c.e.String(a) }
+
+// compile returns the compiled version of the expression.
+// Expressions, that are used as assignment targets or in selective assignment
+// keep their original form, as they are inspected by the assignment.
+func compile(e expr) expr {
+	switch x := e.(type) {
+	case *function:
+		return compileFunction(x)
+	case *derived:
+		return compileDerived(x)
+	case *lambda:
+		return compileLambda(x)
+	case numVar:
+		return compileVar(x)
+	case array:
+		c := make(array, len(x))
+		for i := range x {
+			c[i] = compile(x[i])
+		}
+		return c
+	case list:
+		c := make(list, len(x))
+		for i := range x {
+			if x[i] != nil {
+				c[i] = compile(x[i])
+			}
+		}
+		return c
+	}
+	return e
+}
+
+// compileFunction compiles a function call.
+// Calls of derived functions, lambdas and function variables remain a *function,
+// as assignments and tail calls are detected by the expression type.
+func compileFunction(f *function) expr {
+	if f.selection {
+		return f
+	}
+	var left expr
+	if f.left != nil {
+		left = compile(f.left)
+	}
+	right := compile(f.right)
+
+	switch fn := f.Function.(type) {
+	case Primitive:
+		return compilePrimitive(f, fn, left, right)
+	case *derived:
+		if fn.op == "←" {
+			return &function{Function: fn, left: f.left, right: right}
+		}
+		return &function{Function: compileDerived(fn), left: left, right: right}
+	case *lambda:
+		return &function{Function: compileLambda(fn), left: left, right: right}
+	}
+	return &function{Function: f.Function, left: left, right: right}
+}
+
+// compilePrimitive compiles the call of a primitive function.
+func compilePrimitive(f *function, p Primitive, left, right expr) expr {
+	var cache atomic.Value
+	return compiled{e: f, f: func(a *Apl) (Value, error) {
+		var l Value
+		r, err := right.Eval(a)
+		if err != nil {
+			return nil, err
+		}
+		if left != nil {
+			l, err = left.Eval(a)
+			if err != nil {
+				return nil, err
+			}
+		}
+		var lt reflect.Type
+		if l != nil {
+			lt = reflect.TypeOf(l)
+		}
+		rt := reflect.TypeOf(r)
+		d, _ := cache.Load().(*dispatch)
+		if d == nil || d.registry != a.registry || d.generation != atomic.LoadInt64(&a.generation) || d.l != lt || d.r != rt {
+			d = newDispatch(a, p, lt, rt)
+			cache.Store(d)
+		}
+		for _, h := range d.handlers {
+			if l, r, ok := h.To(a, l, r); ok {
+				return h.Call(a, l, r)
+			}
+		}
+		return p.Call(a, l, r) // The interpreter returns the error.
+	}}
+}
+
+// dispatch contains the handlers of a primitive function,
+// that may accept arguments of the types l and r.
+type dispatch struct {
+	registry   *registry
+	generation int64
+	l, r       reflect.Type
+	handlers   []PrimitiveHandler
+}
+
+func newDispatch(a *Apl, p Primitive, l, r reflect.Type) *dispatch {
+	d := dispatch{registry: a.registry, generation: atomic.LoadInt64(&a.generation), l: l, r: r}
+	for _, h := range a.handlers(p) {
+		if t, ok := h.(TypeDomain); ok && t.Rejects(l, r) {
+			continue
+		}
+		d.handlers = append(d.handlers, h)
+	}
+	return &d
+}
+
+// compileDerived compiles the operands of a derived function.
+func compileDerived(d *derived) *derived {
+	if d.op == "←" {
+		return d
+	}
+	c := derived{op: d.op, sel: d.sel}
+	if d.lo != nil {
+		c.lo = compile(d.lo)
+	}
+	if d.ro != nil {
+		c.ro = compile(d.ro)
+	}
+	return &c
+}
+
+// compileLambda returns a new lambda with a compiled body.
+func compileLambda(λ *lambda) *lambda {
+	if λ.body == nil {
+		return λ
+	}
+	body := make(guardList, len(λ.body))
+	for i, g := range λ.body {
+		c := guardExpr{e: compile(g.e), trap: g.trap}
+		if g.cond != nil {
+			c.cond = compile(g.cond)
+		}
+		body[i] = &c
+	}
+	return &lambda{body: body, tokens: λ.tokens}
+}
+
+// compileVar compiles a variable lookup.
+// Plain names are looked up in the environment directly.
+// System variables and package or object members use Lookup.
+func compileVar(v numVar) expr {
+	if strings.HasPrefix(v.name, "⎕") || v.name == "⍬" || strings.Contains(v.name, "→") {
+		return v
+	}
+	return compiled{e: v, f: func(a *Apl) (Value, error) {
+		for e := a.env; e != nil; e = e.parent {
+			if x, ok := e.get(v.name); ok && x != nil {
+				return x, nil
+			} else if ok {
+				break
+			}
+		}
+		return Identifier(v.name), nil
+	}}
+}
@@ -0,0 +1,242 @@
+package apl
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// ParseCsv reads a table in csv format from r.
+// Comma is the field delimiter, e.g. ',', ';' or '\t'. Fields may be quoted, see encoding/csv.
+// If header is true, the first record contains the keys, otherwise they are taken from the prototype.
+//
+// The type of each column is inferred:
+// A column is numeric, if all non-empty cells can be parsed by the numeric tower.
+// Numeric columns are unified, all other columns are strings.
+// Empty cells are missing values and are set to the null value of the column type.
+//
+// The prototype may be a Table or a Dict, that prescribes the type of a column
+// by a scalar value or an array of that type, e.g. `Qty`Px`Name#(0;0.5;"";)
+// A Table prototype requires the same columns, a Dict may only specify some of them.
+//
+// If the names of the first columns in the header are enclosed in brackets, e.g. [Sym],Qty,Px
+// they are the key columns and a KeyTable is returned, as written by KeyTable.Csv.
+func (a *Apl) ParseCsv(prototype Value, r io.Reader, comma rune, header bool) (Value, error) {
+	t, nk, err := a.parseCsv(prototype, r, comma, header)
+	if err != nil {
+		return nil, err
+	} else if nk == 0 {
+		return t, nil
+	}
+	k, v := Table{Dict: &Dict{M: make(map[Value]Value)}, Rows: t.Rows}, Table{Dict: &Dict{M: make(map[Value]Value)}, Rows: t.Rows}
+	for i, c := range t.K {
+		d := v.Dict
+		if i < nk {
+			d = k.Dict
+		}
+		d.K = append(d.K, c)
+		d.M[c] = t.M[c]
+	}
+	kt, err := a.NewKeyTable(k, v)
+	if err != nil {
+		return nil, fmt.Errorf("parse csv: %s", err)
+	}
+	return kt, nil
+}
+
+// parseCsv returns the table and the number of key columns.
+func (a *Apl) parseCsv(prototype Value, r io.Reader, comma rune, header bool) (Table, int, error) {
+	var keys []Value
+	var pk []Value
+	nk := 0
+	types := make(map[Value]Value)
+	switch p := prototype.(type) {
+	case nil:
+	case Table:
+		pk = p.K
+	case *Dict:
+		pk = p.K
+	default:
+		return Table{}, 0, fmt.Errorf("parse csv: prototype must be a table or a dict: %T", prototype)
+	}
+	if o, ok := prototype.(Object); ok {
+		for _, k := range pk {
+			types[k] = csvType(o.At(a, k))
+		}
+	}
+
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	records, err := cr.ReadAll()
+	if err != nil {
+		return Table{}, 0, fmt.Errorf("parse csv: %s", err)
+	}
+	if header {
+		if len(records) == 0 {
+			return Table{}, 0, fmt.Errorf("parse csv: header is missing")
+		}
+		keys = make([]Value, len(records[0]))
+		for i, s := range records[0] {
+			if nk == i && len(s) > 2 && s[0] == '[' && s[len(s)-1] == ']' {
+				s = s[1 : len(s)-1]
+				nk++
+			}
+			keys[i] = String(s)
+		}
+		records = records[1:]
+	} else if pk == nil {
+		return Table{}, 0, fmt.Errorf("parse csv: keys are missing: no header and no prototype")
+	} else {
+		keys = pk
+	}
+
+	d := Dict{M: make(map[Value]Value)}
+	for _, k := range keys {
+		if _, ok := d.M[k]; ok {
+			return Table{}, 0, fmt.Errorf("parse csv: duplicate key: %s", k.String(a))
+		}
+		d.K = append(d.K, k)
+		d.M[k] = nil
+	}
+	for _, k := range pk {
+		if _, ok := d.M[k]; ok == false {
+			return Table{}, 0, fmt.Errorf("parse csv: column is missing: %s", k.String(a))
+		}
+	}
+	if _, ok := prototype.(Table); ok && len(pk) != len(keys) {
+		return Table{}, 0, fmt.Errorf("parse csv: table has %d columns, prototype has %d", len(keys), len(pk))
+	}
+	if len(records) > 0 && len(records[0]) != len(keys) {
+		return Table{}, 0, fmt.Errorf("parse csv: records have %d fields, there are %d keys", len(records[0]), len(keys))
+	}
+
+	cells := make([]string, len(records))
+	for i, k := range d.K {
+		if len(records) == 0 {
+			d.M[k] = EmptyArray{}
+			continue
+		}
+		for n := range records {
+			cells[n] = records[n][i]
+		}
+		v, err := a.csvColumn(cells, types[k])
+		if err != nil {
+			return Table{}, 0, fmt.Errorf("parse csv: column %s: %s", k.String(a), err)
+		}
+		d.M[k] = v
+	}
+	return Table{Dict: &d, Rows: len(records)}, nk, nil
+}
+
+// csvType returns the scalar type prototype of a column.
+// It returns nil for an empty array, which leaves the type to be inferred.
+func csvType(v Value) Value {
+	if ar, ok := v.(Array); ok {
+		if ar.Size() == 0 {
+			return nil
+		}
+		return ar.At(0)
+	}
+	return v
+}
+
+// csvColumn converts the cells of a column to a uniform array.
+// If the prototype is nil, the type is inferred.
+func (a *Apl) csvColumn(cells []string, prototype Value) (Value, error) {
+	m := MixedArray{Dims: []int{len(cells)}, Values: make([]Value, len(cells))}
+	switch p := prototype.(type) {
+	case nil:
+		return a.csvInfer(cells, m), nil
+	case String:
+		return csvStrings(cells), nil
+	case Number:
+		for i, s := range cells {
+			if s == "" {
+				n, ok := p.(Nullable)
+				if ok == false {
+					return nil, fmt.Errorf("row %d: empty cell for type %T", i+1, p)
+				}
+				m.Values[i] = n.Null()
+				continue
+			}
+			n, err := a.Tower.Parse(strings.TrimSpace(s))
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %s", i+1, err)
+			}
+			v, ok := a.csvConvert(n.Number, p)
+			if ok == false {
+				return nil, fmt.Errorf("row %d: cannot convert %s to %T", i+1, s, p)
+			}
+			m.Values[i] = v
+		}
+		if u, ok := a.Unify(m, true); ok {
+			return u, nil
+		}
+		return nil, fmt.Errorf("column is not uniform")
+	}
+	return nil, fmt.Errorf("unsupported column type: %T", prototype)
+}
+
+// csvInfer returns a uniform numeric array, if all non-empty cells are numbers
+// of compatible types, or a string array otherwise.
+func (a *Apl) csvInfer(cells []string, m MixedArray) Value {
+	var null Value
+	empty := false
+	for i, s := range cells {
+		if s == "" {
+			empty = true
+			continue
+		}
+		n, err := a.Tower.Parse(strings.TrimSpace(s))
+		if err != nil {
+			return csvStrings(cells)
+		}
+		m.Values[i] = n.Number
+		if nv, ok := n.Number.(Nullable); ok && null == nil {
+			null = nv.Null()
+		}
+	}
+	if empty {
+		if null == nil {
+			return csvStrings(cells)
+		}
+		for i, v := range m.Values {
+			if v == nil {
+				m.Values[i] = null
+			}
+		}
+	}
+	if u, ok := a.Unify(m, true); ok {
+		return u
+	}
+	return csvStrings(cells)
+}
+
+func csvStrings(cells []string) StringArray {
+	s := make([]string, len(cells))
+	copy(s, cells)
+	return StringArray{Dims: []int{len(s)}, Strings: s}
+}
+
+// csvConvert converts the number n to the type of the prototype p.
+// It fails, if n has a higher type than p.
+func (a *Apl) csvConvert(n Number, p Number) (Value, bool) {
+	pt := reflect.TypeOf(p)
+	if reflect.TypeOf(n) == pt {
+		return n, true
+	}
+	switch p.(type) {
+	case Bool:
+		return a.Tower.ToBool(n)
+	case Int:
+		i, ok := n.ToIndex()
+		return Int(i), ok
+	}
+	u, q, err := a.Tower.SameType(n, p)
+	if err != nil || reflect.TypeOf(q) != pt {
+		return nil, false
+	}
+	return u, true
+}
@@ -0,0 +1,417 @@
+package apl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/ktye/iv/apl/scan"
+)
+
+// The data format is written by ¯1⍕V and read by P⍎S, see ParseArray, ParseDict and ParseTable.
+// It is close to the display format:
+//	Numbers are written with full precision by the numeric tower:
+//		1 ¯2 1.5 1e¯21 1J2 1b 2019.02.07T13.25.01.000000000 1h30m0s 1r3
+//	Missing values are 0N for integers, 0n for floats and 0Nt for times, see Nullable.
+//	Strings are quoted in go syntax: "alpha\tbeta"
+//	A vector separates it's values by blanks: 1 2 3
+//	An array of higher rank terminates each row with a newline
+//	and each higher axis with an additional newline.
+//	If an axis has less than 2 elements, or if the array is nested within a List or Dict,
+//	it is written with nested brackets, one level for each axis:
+//		[5] [] [[1 2 3]] [[1 2][3 4]]
+//	Empty arrays are read as ⍬.
+//	A List encloses it's items in parenthesis, each is terminated by a semicolon:
+//		(1;"a";(2 3;[4];);)
+//	A Dict is written with one line for each key, followed by a colon and the value:
+//		"alpha": 1 2 3
+//		"beta":  (1;2;)
+//	A Table is written with a header line containing the keys and one line for each row.
+//	Values in a table that are arrays are written in bracket form, lists in parenthesis:
+//		"Name"  "Qty" "Px"
+//		"Peter" 3     [1.5 2]
+//	Images are written as their integer array.
+//	A Dict or a Table that is nested within a List, Dict or Table is written in braces on a single line.
+//	A Dict terminates each value with a semicolon.
+//	A Table terminates the keys with a bar and each row with a semicolon:
+//		{"alpha": 1 2 3; "beta": {"x": 1;};}
+//		{"Name" "Qty"| "Peter" 3; "Paul" 4;}
+
+// dataString formats a value that is nested within a List or a Dict.
+// For PP=-1, arrays of higher rank are written in bracket form to keep them on a single line,
+// dicts and tables in braces.
+func dataString(a *Apl, v Value) string {
+	if a.PP == -1 {
+		switch x := v.(type) {
+		case List:
+			return x.String(a)
+		case *Dict:
+			return dataDict(a, x)
+		case Table:
+			return dataTable(a, x)
+		}
+		if ar, ok := v.(Array); ok && len(ar.Shape()) > 1 {
+			return bracketArray(a, ar)
+		}
+	}
+	return v.String(a)
+}
+
+// dataItem formats a value as a single item, e.g. a table cell.
+// Arrays are written in bracket form, Lists in parenthesis and dicts and tables in braces.
+func dataItem(a *Apl, v Value) string {
+	switch x := v.(type) {
+	case List:
+		return x.String(a)
+	case *Dict:
+		return dataDict(a, x)
+	case Table:
+		return dataTable(a, x)
+	case Array:
+		return bracketArray(a, x)
+	}
+	return v.String(a)
+}
+
+// dataDict writes a nested dict in braces: {"alpha": 1 2 3; "beta": (1;2;);}
+func dataDict(a *Apl, d *Dict) string {
+	var b strings.Builder
+	b.WriteRune('{')
+	for i, k := range d.K {
+		if i > 0 {
+			b.WriteRune(' ')
+		}
+		fmt.Fprintf(&b, "%s: %s;", k.String(a), dataString(a, d.M[k]))
+	}
+	b.WriteRune('}')
+	return b.String()
+}
+
+// dataTable writes a nested table in braces: {"Name" "Qty"| "Peter" 3; "Paul" 4;}
+func dataTable(a *Apl, t Table) string {
+	var b strings.Builder
+	b.WriteRune('{')
+	for i, k := range t.K {
+		if i > 0 {
+			b.WriteRune(' ')
+		}
+		b.WriteString(k.String(a))
+	}
+	b.WriteRune('|')
+	for n := 0; n < t.Rows; n++ {
+		for _, k := range t.K {
+			b.WriteRune(' ')
+			b.WriteString(dataItem(a, t.M[k].(Array).At(n)))
+		}
+		b.WriteRune(';')
+	}
+	b.WriteRune('}')
+	return b.String()
+}
+
+// dataParser reads values in the data format.
+type dataParser struct {
+	a *Apl
+	r *strings.Reader
+}
+
+func newDataParser(a *Apl, s string) *dataParser {
+	return &dataParser{a: a, r: strings.NewReader(s)}
+}
+
+// peek returns the next rune without consuming it, or -1 at the end of input.
+func (p *dataParser) peek() rune {
+	r, _, err := p.r.ReadRune()
+	if err != nil {
+		return -1
+	}
+	p.r.UnreadRune()
+	return r
+}
+
+// blanks skips white space, but not newlines.
+func (p *dataParser) blanks() {
+	for {
+		if r := p.peek(); r == '\n' || unicode.IsSpace(r) == false {
+			return
+		}
+		p.r.ReadRune()
+	}
+}
+
+// value parses one or more items until a newline, the end of input, or a rune in stop.
+// Multiple items are returned as a vector.
+func (p *dataParser) value(stop string) (Value, error) {
+	var values []Value
+	for {
+		p.blanks()
+		if r := p.peek(); r == -1 || r == '\n' || strings.ContainsRune(stop, r) {
+			break
+		}
+		v, err := p.item()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	switch len(values) {
+	case 0:
+		return EmptyArray{}, nil
+	case 1:
+		return values[0], nil
+	}
+	return p.unify(MixedArray{Dims: []int{len(values)}, Values: values}), nil
+}
+
+// item parses a single scalar, a List, an array in bracket form, or a dict or table in braces.
+func (p *dataParser) item() (Value, error) {
+	switch r := p.peek(); r {
+	case -1:
+		return nil, io.ErrUnexpectedEOF
+	case '(':
+		return p.list()
+	case '{':
+		return p.object()
+	case '[':
+		return p.bracket()
+	case '"':
+		s, err := scan.ReadString(p.r)
+		if err != nil {
+			return nil, err
+		}
+		return String(s), nil
+	default:
+		s, err := scan.ScanNumber(p.r)
+		if err != nil {
+			return nil, err
+		} else if s == "" {
+			return nil, fmt.Errorf("unexpected %q", r)
+		}
+		n, err := p.a.Tower.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		return n.Number, nil
+	}
+}
+
+// list parses a List: (item;item;)
+func (p *dataParser) list() (Value, error) {
+	p.r.ReadRune()
+	l := List{}
+	for {
+		p.blanks()
+		if p.peek() == ')' {
+			p.r.ReadRune()
+			return l, nil
+		}
+		v, err := p.value(";)")
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, v)
+		if r, _, err := p.r.ReadRune(); err != nil {
+			return nil, fmt.Errorf("list is not terminated")
+		} else if r == ')' {
+			return l, nil
+		} else if r != ';' {
+			return nil, fmt.Errorf("list: unexpected %q", r)
+		}
+	}
+}
+
+// object parses a dict or a table in braces.
+// The separator after the first key decides: a colon starts a dict, a bar a table.
+func (p *dataParser) object() (Value, error) {
+	p.r.ReadRune()
+	var keys []Value
+	for {
+		p.blanks()
+		switch p.peek() {
+		case -1, '\n':
+			return nil, fmt.Errorf("{ is not terminated")
+		case '}':
+			if keys != nil {
+				return nil, fmt.Errorf("expected : or | after keys")
+			}
+			p.r.ReadRune()
+			return &Dict{M: make(map[Value]Value)}, nil
+		case ':':
+			if len(keys) != 1 {
+				return nil, fmt.Errorf("dict: expected a single key before :")
+			}
+			p.r.ReadRune()
+			return p.dict(keys[0])
+		case '|':
+			p.r.ReadRune()
+			return p.table(keys)
+		}
+		k, err := p.key(keys)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+}
+
+// key parses a scalar key, that is not contained in keys.
+func (p *dataParser) key(keys []Value) (Value, error) {
+	k, err := p.item()
+	if err != nil {
+		return nil, err
+	} else if _, ok := k.(Array); ok {
+		return nil, fmt.Errorf("key must be a scalar: %s", k.String(p.a))
+	}
+	for _, x := range keys {
+		if x == k {
+			return nil, fmt.Errorf("duplicate key: %s", k.String(p.a))
+		}
+	}
+	return k, nil
+}
+
+// dict parses the values of a nested dict after the first key and it's colon.
+func (p *dataParser) dict(k Value) (Value, error) {
+	d := Dict{M: make(map[Value]Value)}
+	for {
+		v, err := p.value(";}")
+		if err != nil {
+			return nil, err
+		}
+		if r, _, err := p.r.ReadRune(); err != nil || r != ';' {
+			return nil, fmt.Errorf("dict: value of %s is not terminated", k.String(p.a))
+		}
+		d.K = append(d.K, k)
+		d.M[k] = v
+
+		p.blanks()
+		if p.peek() == '}' {
+			p.r.ReadRune()
+			return &d, nil
+		}
+		if k, err = p.key(d.K); err != nil {
+			return nil, fmt.Errorf("dict: %s", err)
+		}
+		p.blanks()
+		if r, _, err := p.r.ReadRune(); err != nil || r != ':' {
+			return nil, fmt.Errorf("dict: expected : after key %s", k.String(p.a))
+		}
+	}
+}
+
+// table parses the rows of a nested table after the keys and the bar.
+func (p *dataParser) table(keys []Value) (Value, error) {
+	cols := make([][]Value, len(keys))
+	rows := 0
+	for {
+		p.blanks()
+		if p.peek() == '}' {
+			p.r.ReadRune()
+			return p.columns(keys, cols, rows), nil
+		}
+		for i := range cols {
+			p.blanks()
+			if r := p.peek(); r == ';' || r == '}' || r == '\n' || r == -1 {
+				return nil, fmt.Errorf("table: row %d has %d values instead of %d", rows+1, i, len(cols))
+			}
+			v, err := p.item()
+			if err != nil {
+				return nil, fmt.Errorf("table: row %d: %s", rows+1, err)
+			}
+			cols[i] = append(cols[i], v)
+		}
+		p.blanks()
+		if r, _, err := p.r.ReadRune(); err != nil || r != ';' {
+			return nil, fmt.Errorf("table: row %d has more than %d values", rows+1, len(cols))
+		}
+		rows++
+	}
+}
+
+// columns returns a table with the values of each column, which are unified.
+func (p *dataParser) columns(keys []Value, cols [][]Value, rows int) Table {
+	d := Dict{K: keys, M: make(map[Value]Value)}
+	for i, k := range keys {
+		if rows == 0 {
+			d.M[k] = EmptyArray{}
+		} else {
+			d.M[k] = p.unify(MixedArray{Dims: []int{rows}, Values: cols[i]})
+		}
+	}
+	return Table{Dict: &d, Rows: rows}
+}
+
+// bracket parses an array in bracket form.
+func (p *dataParser) bracket() (Value, error) {
+	var b strings.Builder
+	depth := 0
+	quoted, escaped := false, false
+	for {
+		r, _, err := p.r.ReadRune()
+		if err != nil {
+			return nil, fmt.Errorf("unmatched [")
+		}
+		b.WriteRune(r)
+		if quoted {
+			if escaped {
+				escaped = false
+			} else if r == '\\' {
+				escaped = true
+			} else if r == '"' {
+				quoted = false
+			}
+			continue
+		}
+		if r == '"' {
+			quoted = true
+		} else if r == '[' {
+			depth++
+		} else if r == ']' {
+			depth--
+			if depth == 0 {
+				break
+			}
+		}
+	}
+	v, err := p.a.ScanRankArray(strings.NewReader(b.String()), -1)
+	if err == io.EOF {
+		return EmptyArray{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return p.unify(v.(Array)), nil
+}
+
+// unify converts the array to a uniform type, if all values have the same type.
+func (p *dataParser) unify(ar Array) Array {
+	if u, ok := p.a.Unify(ar, false); ok {
+		return u
+	}
+	return ar
+}
+
+// line terminates a line. Only blanks may follow.
+func (p *dataParser) line() error {
+	p.blanks()
+	if r, _, err := p.r.ReadRune(); err == nil && r != '\n' {
+		p.r.UnreadRune()
+		return fmt.Errorf("unexpected %q", r)
+	}
+	return nil
+}
+
+// emptyLines skips empty lines and returns false at the end of input.
+func (p *dataParser) emptyLines() bool {
+	for {
+		p.blanks()
+		switch p.peek() {
+		case -1:
+			return false
+		case '\n':
+			p.r.ReadRune()
+		default:
+			return true
+		}
+	}
+}
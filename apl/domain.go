@@ -1,5 +1,7 @@
 package apl
 
+import "reflect"
+
 // Domain represents the application domain of a function or operator.
 // Calling To converts the left and right input arguments and returns true,
 // if the types or values are compatible with the function or operator.
@@ -14,3 +16,15 @@ type Domain interface {
 	To(*Apl, Value, Value) (Value, Value, bool)
 	String(*Apl) string
 }
+
+// TypeDomain is implemented by a Domain, that can tell from the types of the arguments alone,
+// that To fails.
+// Rejects returns true, if To returns false for all values of the types L and R.
+// L is nil for a monadic call.
+// It may always return false, if it cannot decide without the values.
+//
+// Compiled programs use it to skip primitive handlers, see Compile.
+type TypeDomain interface {
+	Domain
+	Rejects(L, R reflect.Type) bool
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/ktye/iv/apl"
+import (
+	"reflect"
+
+	"github.com/ktye/iv/apl"
+)
 
 // TODO: do we have to test against function or identifiers here?
 
@@ -34,6 +38,9 @@ func (v array) To(a *apl.Apl, V apl.Value) (apl.Value, bool) {
 	}
 	return propagate(a, ga, v.child)
 }
+func (v array) rejects(t reflect.Type) bool {
+	return v.conv == false && (implements(t, arrayType) == false || rejects(v.child, t))
+}
 func (v array) String(a *apl.Apl) string {
 	name := "array"
 	if v.conv {
@@ -219,3 +226,28 @@ func (ia indexarray) String(a *apl.Apl) string {
 	}
 	return name + " " + ia.child.String(a)
 }
+
+// IsBoolArray accepts only a BoolArray.
+func IsBoolArray(child SingleDomain) SingleDomain {
+	return boolarray{child}
+}
+
+type boolarray struct {
+	child SingleDomain
+}
+
+func (b boolarray) To(a *apl.Apl, V apl.Value) (apl.Value, bool) {
+	if _, ok := V.(apl.BoolArray); ok == false {
+		return V, false
+	}
+	return propagate(a, V, b.child)
+}
+func (b boolarray) rejects(t reflect.Type) bool {
+	return t != boolArrayType || rejects(b.child, t)
+}
+func (b boolarray) String(a *apl.Apl) string {
+	if b.child == nil {
+		return "boolarray"
+	}
+	return "boolarray " + b.child.String(a)
+}
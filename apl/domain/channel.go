@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/ktye/iv/apl"
+import (
+	"reflect"
+
+	"github.com/ktye/iv/apl"
+)
 
 // IsChannel tests if the value is a channel.
 func IsChannel(child SingleDomain) SingleDomain {
@@ -18,6 +22,10 @@ func (c channel) To(a *apl.Apl, V apl.Value) (apl.Value, bool) {
 	return V, false
 }
 
+func (c channel) rejects(t reflect.Type) bool {
+	return t != channelType || rejects(c.child, t)
+}
+
 func (c channel) String(a *apl.Apl) string {
 	name := "channel"
 	if c.child == nil {
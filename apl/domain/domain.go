@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/ktye/iv/apl"
 )
@@ -11,6 +12,39 @@ type SingleDomain interface {
 	String(*apl.Apl) string
 }
 
+// typeDomain is implemented by a SingleDomain, that can tell from the type of a value alone,
+// that To fails. It is the single argument version of apl.TypeDomain.
+type typeDomain interface {
+	rejects(t reflect.Type) bool
+}
+
+// rejects returns true, if the domain fails for all values of type t.
+// A nil domain accepts all values.
+func rejects(d SingleDomain, t reflect.Type) bool {
+	if r, ok := d.(typeDomain); ok {
+		return r.rejects(t)
+	}
+	return false
+}
+
+// implements returns true, if values of type t implement the interface type i.
+// The type t is nil for a nil value.
+func implements(t, i reflect.Type) bool {
+	return t != nil && t.Implements(i)
+}
+
+var (
+	arrayType     = reflect.TypeOf((*apl.Array)(nil)).Elem()
+	numberType    = reflect.TypeOf((*apl.Number)(nil)).Elem()
+	objectType    = reflect.TypeOf((*apl.Object)(nil)).Elem()
+	functionType  = reflect.TypeOf((*apl.Function)(nil)).Elem()
+	tableType     = reflect.TypeOf(apl.Table{})
+	keyTableType  = reflect.TypeOf(apl.KeyTable{})
+	channelType   = reflect.TypeOf(apl.Channel{})
+	boolArrayType = reflect.TypeOf(apl.BoolArray{})
+	stringType    = reflect.TypeOf(apl.String(""))
+)
+
 // propagate is used by a SingleDomain function for successive values to propagate
 // testing to the child.
 func propagate(a *apl.Apl, v apl.Value, child SingleDomain) (apl.Value, bool) {
@@ -46,6 +80,9 @@ func (b both) To(a *apl.Apl, L, R apl.Value) (apl.Value, apl.Value, bool) {
 	}
 	return l, r, true
 }
+func (b both) Rejects(L, R reflect.Type) bool {
+	return rejects(b.same, L) || rejects(b.same, R)
+}
 func (b both) String(a *apl.Apl) string {
 	if b.same == nil {
 		return "any"
@@ -73,6 +110,9 @@ func (b any) To(a *apl.Apl, L, R apl.Value) (apl.Value, apl.Value, bool) {
 	}
 	return L, R, false
 }
+func (b any) Rejects(L, R reflect.Type) bool {
+	return rejects(b.child, L) && rejects(b.child, R)
+}
 func (b any) String(a *apl.Apl) string {
 	return "any " + b.child.String(a)
 }
@@ -103,6 +143,9 @@ func (s split) To(a *apl.Apl, L, R apl.Value) (apl.Value, apl.Value, bool) {
 	}
 	return l, r, true
 }
+func (s split) Rejects(L, R reflect.Type) bool {
+	return rejects(s.left, L) || rejects(s.right, R)
+}
 func (s split) String(a *apl.Apl) string {
 	// TODO: if we use domain for both function and operators,
 	// for operators, L and R should print as LO and RO.
@@ -141,6 +184,9 @@ func (m monadic) To(a *apl.Apl, L, R apl.Value) (apl.Value, apl.Value, bool) {
 	return L, R, false
 }
 func (m monadic) IsDyadic() bool { return false }
+func (m monadic) Rejects(L, R reflect.Type) bool {
+	return L != nil || rejects(m.right, R)
+}
 func (m monadic) String(a *apl.Apl) string {
 	if m.right == nil {
 		return "R any"
@@ -169,6 +215,13 @@ func (d dyadic) To(a *apl.Apl, L, R apl.Value) (apl.Value, apl.Value, bool) {
 	return d.child.To(a, L, R)
 }
 func (d dyadic) IsDyadic() bool { return true }
+func (d dyadic) Rejects(L, R reflect.Type) bool {
+	if L == nil {
+		return true
+	}
+	t, ok := d.child.(apl.TypeDomain)
+	return ok && t.Rejects(L, R)
+}
 func (d dyadic) String(a *apl.Apl) string {
 	if d.child == nil {
 		return "L any, R any"
@@ -219,6 +272,13 @@ func (n or) To(a *apl.Apl, V apl.Value) (apl.Value, bool) {
 	return V, false
 }
 
+func (n or) rejects(t reflect.Type) bool {
+	if n.child1 == nil || n.child2 == nil {
+		return true
+	}
+	return rejects(n.child1, t) && rejects(n.child2, t)
+}
+
 func (n or) String(a *apl.Apl) string {
 	return "(" + n.child1.String(a) + " or " + n.child2.String(a) + ")"
 }
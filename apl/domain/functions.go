@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"reflect"
+
 	"github.com/ktye/iv/apl"
 )
 
@@ -22,6 +24,9 @@ func (f function) To(a *apl.Apl, V apl.Value) (apl.Value, bool) {
 	}
 	return V, false
 }
+func (f function) rejects(t reflect.Type) bool {
+	return implements(t, functionType) == false || rejects(f.child, t)
+}
 func (f function) String(a *apl.Apl) string {
 	if f.child == nil {
 		return "function"
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/ktye/iv/apl"
+import (
+	"reflect"
+
+	"github.com/ktye/iv/apl"
+)
 
 // ToNumber accepts scalars and single size arrays.
 // and converts them to scalars if they contain one of the types:
@@ -41,6 +45,9 @@ func (n number) To(a *apl.Apl, V apl.Value) (apl.Value, bool) {
 	}
 	return V, false
 }
+func (n number) rejects(t reflect.Type) bool {
+	return n.convert == false && (implements(t, numberType) == false || rejects(n.child, t))
+}
 func (n number) String(a *apl.Apl) string {
 	name := "number"
 	if n.convert {
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/ktye/iv/apl"
+import (
+	"reflect"
+
+	"github.com/ktye/iv/apl"
+)
 
 // IsObject accepts objects
 func IsObject(child SingleDomain) SingleDomain {
@@ -15,6 +19,9 @@ func (s objtype) To(a *apl.Apl, V apl.Value) (apl.Value, bool) {
 	}
 	return V, false
 }
+func (s objtype) rejects(t reflect.Type) bool {
+	return implements(t, objectType) == false || rejects(s.child, t)
+}
 func (s objtype) String(a *apl.Apl) string {
 	if s.child == nil {
 		return "object"
@@ -35,9 +42,35 @@ func (s table) To(a *apl.Apl, V apl.Value) (apl.Value, bool) {
 	}
 	return V, false
 }
+func (s table) rejects(t reflect.Type) bool {
+	return t != tableType || rejects(s.child, t)
+}
 func (s table) String(a *apl.Apl) string {
 	if s.child == nil {
 		return "table"
 	}
 	return "table" + " " + s.child.String(a)
 }
+
+// IsKeyTable accepts keyed tables
+func IsKeyTable(child SingleDomain) SingleDomain {
+	return keytable{child}
+}
+
+type keytable struct{ child SingleDomain }
+
+func (s keytable) To(a *apl.Apl, V apl.Value) (apl.Value, bool) {
+	if _, ok := V.(apl.KeyTable); ok {
+		return propagate(a, V, s.child)
+	}
+	return V, false
+}
+func (s keytable) rejects(t reflect.Type) bool {
+	return t != keyTableType || rejects(s.child, t)
+}
+func (s keytable) String(a *apl.Apl) string {
+	if s.child == nil {
+		return "keyed table"
+	}
+	return "keyed table" + " " + s.child.String(a)
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/ktye/iv/apl"
+import (
+	"reflect"
+
+	"github.com/ktye/iv/apl"
+)
 
 // ToScalar accepts scalars and converts single element arrays to scalars.
 func ToScalar(child SingleDomain) SingleDomain {
@@ -30,6 +34,9 @@ func (s scalar) To(a *apl.Apl, V apl.Value) (apl.Value, bool) {
 	}
 	return propagate(a, v, s.child)
 }
+func (s scalar) rejects(t reflect.Type) bool {
+	return s.convert == false && (implements(t, arrayType) || rejects(s.child, t))
+}
 func (s scalar) String(a *apl.Apl) string {
 	name := "scalar"
 	if s.convert {
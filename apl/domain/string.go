@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"reflect"
+
 	"github.com/ktye/iv/apl"
 )
 
@@ -20,6 +22,9 @@ func (s stringtype) To(a *apl.Apl, V apl.Value) (apl.Value, bool) {
 	}
 	return V, false
 }
+func (s stringtype) rejects(t reflect.Type) bool {
+	return t != stringType || rejects(s.child, t)
+}
 func (s stringtype) String(a *apl.Apl) string {
 	if s.child == nil {
 		return "string"
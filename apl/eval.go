@@ -29,7 +29,8 @@ func (a *Apl) Eval(p Program) (err error) {
 
 	var val Value
 	for _, expr := range p {
-		val, err = expr.Eval(a)
+		var shy bool
+		val, shy, err = evalShy(a, expr)
 		if err != nil {
 			return err
 		}
@@ -37,7 +38,7 @@ func (a *Apl) Eval(p Program) (err error) {
 		if a.debug {
 			fmt.Fprintf(a.stdout, "%T\n", val)
 		}
-		if isAssignment(expr) == false {
+		if isAssignment(expr) == false && shy == false {
 			switch v := val.(type) {
 			case Channel:
 				for e := range v[0] {
@@ -136,3 +137,13 @@ func isAssignment(e expr) bool {
 	}
 	return false
 }
+
+// evalShy evaluates the expression and reports if the value is shy and not printed.
+// Only a function call can return a shy value, see shyCaller.
+func evalShy(a *Apl, e expr) (Value, bool, error) {
+	if f, ok := e.(*function); ok {
+		return f.evalShy(a)
+	}
+	v, err := e.Eval(a)
+	return v, false, err
+}
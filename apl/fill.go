@@ -0,0 +1,101 @@
+package apl
+
+// Fill returns the fill element of V.
+// The fill element is used to extend an array, e.g. by overtake or expand,
+// and it is the argument of a fill function, if a function is applied to each item
+// of an empty array.
+//
+// Uniform arrays fill with the zero value of their type, even if they are empty.
+// Other arrays and lists fill with the prototype of their first item.
+// The fill element of a scalar is it's prototype.
+func (a *Apl) Fill(V Value) Value {
+	switch v := V.(type) {
+	case EmptyArray:
+		return Int(0)
+	case Table:
+		return a.Prototype(v)
+	case Uniform:
+		return v.Zero()
+	case Array:
+		if v.Size() == 0 {
+			return Int(0)
+		}
+		return a.Prototype(v.At(0))
+	}
+	return a.Prototype(V)
+}
+
+// Prototype returns V with each scalar replaced by it's fill value:
+//	Bool, Int and other numbers: 0 of the same type, a zero duration for a Time
+//	String: the empty string
+//	Array: an array of the same shape and type, filled with the fill element
+//	List: a list of the prototypes of it's items
+//	Dict: a dict with the same keys and the prototypes of it's values
+//	Table: a table with the same columns and no rows
+// Other values are returned unchanged.
+func (a *Apl) Prototype(V Value) Value {
+	switch v := V.(type) {
+	case Bool:
+		return Bool(false)
+	case Int:
+		return Int(0)
+	case String:
+		return String("")
+	case Number:
+		if mk := a.Tower.Uniform; mk != nil {
+			if u, ok := mk([]Value{v}); ok {
+				if z, ok := u.(Uniform); ok {
+					return z.Zero()
+				}
+			}
+		}
+		return a.Tower.Import(Int(0))
+	case EmptyArray:
+		return v
+	case List:
+		l := make(List, len(v))
+		for i := range l {
+			l[i] = a.Prototype(v[i])
+		}
+		return l
+	case Table:
+		d := Dict{K: make([]Value, len(v.K)), M: make(map[Value]Value)}
+		for i, k := range v.K {
+			d.K[i] = k
+			if col, ok := v.M[k].(Array); ok {
+				d.M[k] = MakeArray(col, []int{0})
+			} else {
+				d.M[k] = v.M[k]
+			}
+		}
+		return Table{Dict: &d, Rows: 0}
+	case *Dict:
+		d := Dict{K: make([]Value, len(v.K)), M: make(map[Value]Value)}
+		for i, k := range v.K {
+			d.K[i] = k
+			d.M[k] = a.Prototype(v.M[k])
+		}
+		return &d
+	case Array:
+		return a.MakeFilled(a.Fill(v), CopyShape(v))
+	}
+	return V
+}
+
+// MakeFilled returns an array of the given shape with all elements set to v.
+// The array is uniform, if v has a uniform array type.
+// This is also true for an empty shape containing a 0, which is used to create
+// typed empty arrays.
+// If the shape has no dimensions, v is returned.
+func (a *Apl) MakeFilled(v Value, shape []int) Value {
+	if len(shape) == 0 {
+		return v
+	}
+	m := MixedArray{Dims: []int{1}, Values: []Value{v}}
+	if u, ok := a.Unify(m, false); ok {
+		if rs, ok := u.(Reshaper); ok {
+			return rs.Reshape(shape)
+		}
+	}
+	return m.Reshape(shape)
+}
@@ -54,6 +54,7 @@ func (a *Apl) SetPP(R Value) error {
 // ArrayString can be used by an array implementation.
 // It formats an n-dimensional array using a tabwriter for PP>=-1.
 // Each dimension is terminated by k newlines, where k is the dimension index.
+// For PP==-1, arrays with an axis of length 0 or 1 are written with nested brackets.
 // For PP==-2, it uses a single line json notation with nested brackets and
 // for PP==-3, it formats in a single line matlab syntax (rank <= 2).
 func ArrayString(a *Apl, v Array) string {
@@ -65,7 +66,15 @@ func ArrayString(a *Apl, v Array) string {
 	shape := v.Shape()
 	if len(shape) == 0 {
 		return ""
-	} else if len(shape) == 1 {
+	}
+	if a.PP == -1 {
+		for _, n := range shape {
+			if n < 2 {
+				return bracketArray(a, v)
+			}
+		}
+	}
+	if len(shape) == 1 {
 		s := make([]string, shape[0])
 		for i := 0; i < shape[0]; i++ {
 			s[i] = v.At(i).String(a)
@@ -140,6 +149,35 @@ func jsonArray(a *Apl, v Array) string {
 	return vector(sa)
 }
 
+// bracketArray formats an array with nested brackets, one level for each axis.
+// It is used by the data format (PP=-1) for arrays that cannot be written as a table.
+func bracketArray(a *Apl, v Array) string {
+	shape := v.Shape()
+	var b strings.Builder
+	off := 0
+	var axis func(k int)
+	axis = func(k int) {
+		b.WriteRune('[')
+		for i := 0; i < shape[k]; i++ {
+			if k < len(shape)-1 {
+				axis(k + 1)
+				continue
+			}
+			if i > 0 {
+				b.WriteRune(' ')
+			}
+			b.WriteString(v.At(off).String(a))
+			off++
+		}
+		b.WriteRune(']')
+	}
+	if len(shape) == 0 {
+		return "[]"
+	}
+	axis(0)
+	return b.String()
+}
+
 // matArray is used for PP=-3. It only supported for rank 1 and 2.
 func matArray(a *Apl, v Array) string {
 	sa := stringArray(a, v)
@@ -164,16 +202,37 @@ func matArray(a *Apl, v Array) string {
 
 // ParseArray parses a rectangular n-dimensional array from a string representation.
 // The result will have the same type as the prototype, or an error is returned.
-// If the prototype is nil, a mixed array is returned.
+// If the prototype is nil, the array is uniform if possible, or a mixed array.
+// A single value is returned as a scalar and empty input as the empty array.
+// If the prototype is an Image, the result is converted to an Image.
+// A List is parsed, if the string starts with a parenthesis.
 // The function can parse arrays that have been formatted with ¯1⍕, ¯2⍕ and ¯3⍕.
 // Json arrays (¯2⍕) can only be parsed, if they don't contain complex numbers.
 func (a *Apl) ParseArray(prototype Value, s string) (Value, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "(") {
+		p := newDataParser(a, s)
+		l, err := p.list()
+		if err != nil {
+			return nil, fmt.Errorf("parse array: %s", err)
+		} else if p.emptyLines() {
+			return nil, fmt.Errorf("parse array: data after list")
+		}
+		return l, nil
+	}
 	v, err := a.ScanRankArray(strings.NewReader(s), -1)
-	if err != nil {
+	if err == io.EOF {
+		return EmptyArray{}, nil
+	} else if err != nil {
 		return nil, fmt.Errorf("parse array: %s", err)
 	}
+	if m := v.(MixedArray); len(m.Values) == 1 && strings.HasPrefix(s, "[") == false {
+		return m.Values[0], nil
+	}
 
-	if prototype != nil {
+	if _, ok := prototype.(Image); ok {
+		return imageFromArray(v.(Array))
+	} else if prototype != nil {
 		if u, ok := prototype.(Uniform); ok {
 			res, ok := a.Unify(v.(Array), true)
 			if ok == false {
@@ -185,6 +244,9 @@ func (a *Apl) ParseArray(prototype Value, s string) (Value, error) {
 			return res, nil
 		}
 	}
+	if u, ok := a.Unify(v.(Array), false); ok {
+		return u, nil
+	}
 	return v, nil
 }
 
@@ -2,6 +2,7 @@ package apl
 
 import (
 	"fmt"
+	"reflect"
 )
 
 // Function is any type that can be called, given it's left and right arguments.
@@ -11,6 +12,22 @@ type Function interface {
 	Call(*Apl, Value, Value) (Value, error)
 }
 
+// shyCaller is a function that reports if it's result is shy.
+// A shy result is the value of an assignment in a lambda function, which is not printed.
+// It is implemented by *lambda, fnVar, *derived and *opFunction.
+type shyCaller interface {
+	callShy(*Apl, Value, Value) (Value, bool, error)
+}
+
+// callShy calls f and reports if the result is shy.
+func callShy(a *Apl, f Function, l, r Value) (Value, bool, error) {
+	if s, ok := f.(shyCaller); ok {
+		return s.callShy(a, l, r)
+	}
+	v, err := f.Call(a, l, r)
+	return v, false, err
+}
+
 // function wraps a Function with it's arguments.
 type function struct {
 	Function
@@ -20,15 +37,25 @@ type function struct {
 
 // Eval calls the function with it's surrounding arugments.
 func (f *function) Eval(a *Apl) (Value, error) {
+	v, _, err := f.evalShy(a)
+	return v, err
+}
+
+// evalShy calls the function and reports if the result is shy, see shyCaller.
+func (f *function) evalShy(a *Apl) (Value, bool, error) {
 	var err error
 	var l, r Value
 
 	// The right argument must be evaluated first.
 	// Otherwise this A←1⋄A+(A←2) evaluates to 3,
 	// but it should evaluate to 4.
-	r, err = f.right.Eval(a)
-	if err != nil {
-		return nil, err
+	// The variable in a selective assignment is owned, as selection may insert dict keys.
+	if nv, ok := f.right.(numVar); ok && f.selection {
+		if r, _ = a.LookupOwned(nv.name); r == nil {
+			r = Identifier(nv.name)
+		}
+	} else if r, err = f.right.Eval(a); err != nil {
+		return nil, false, err
 	}
 	if f.left != nil {
 
@@ -39,7 +66,7 @@ func (f *function) Eval(a *Apl) (Value, error) {
 		} else {
 			l, err = f.left.Eval(a)
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
 		}
 	}
@@ -47,14 +74,16 @@ func (f *function) Eval(a *Apl) (Value, error) {
 	// Special case: the last function in a selective assignment uses Select instead of Call.
 	if _, ok := f.right.(numVar); ok && f.selection {
 		if d, ok := f.Function.(*derived); ok == true {
-			return d.Select(a, l, r)
+			v, err := d.Select(a, l, r)
+			return v, false, err
 		} else if p, ok := f.Function.(Primitive); ok == false {
-			return nil, fmt.Errorf("cannot use %T in selective assignment", f.Function)
+			return nil, false, fmt.Errorf("cannot use %T in selective assignment", f.Function)
 		} else {
-			return p.Select(a, l, r)
+			v, err := p.Select(a, l, r)
+			return v, false, err
 		}
 	}
-	return f.Function.Call(a, l, r)
+	return callShy(a, f.Function, l, r)
 }
 
 func (f *function) String(a *Apl) string {
@@ -110,8 +139,8 @@ func (p Primitive) String(a *Apl) string {
 // they are tested in reverse registration order, until the first one takes the
 // responsibility.
 func (p Primitive) Call(a *Apl, L, R Value) (Value, error) {
-	if handles := a.primitives[p]; handles == nil {
-		return nil, fmt.Errorf("primitive function %s does not exist", p)
+	if handles := a.handlers(p); handles == nil {
+		return nil, Errorf(ValueError, "primitive function %s does not exist", p)
 	} else {
 		for _, h := range handles {
 			if l, r, ok := h.To(a, L, R); ok {
@@ -129,8 +158,8 @@ func (p Primitive) Call(a *Apl, L, R Value) (Value, error) {
 // It is used as a selection function in selective assignment.
 // While the Call on these primitives would return selected values, Select returns the indexes of the values.
 func (p Primitive) Select(a *Apl, L, R Value) (IntArray, error) {
-	if handles := a.primitives[p]; handles == nil {
-		return IntArray{}, fmt.Errorf("primitive function %s does not exist", p)
+	if handles := a.handlers(p); handles == nil {
+		return IntArray{}, Errorf(ValueError, "primitive function %s does not exist", p)
 	} else {
 		for _, h := range handles {
 			if l, r, ok := h.To(a, L, R); ok {
@@ -173,6 +202,10 @@ func (h pHandler) To(a *Apl, L, R Value) (Value, Value, bool) {
 func (h pHandler) String(a *Apl) string {
 	return h.d.String(a)
 }
+func (h pHandler) Rejects(L, R reflect.Type) bool {
+	t, ok := h.d.(TypeDomain)
+	return ok && t.Rejects(L, R)
+}
 func (h pHandler) Doc() string {
 	return h.s
 }
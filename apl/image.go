@@ -46,7 +46,8 @@ func (i Image) At(k int) Value {
 		p = idx[0]
 		idx = idx[1:]
 	}
-	return colorValue(i.Im[p].At(idx[0], idx[1]))
+	r := i.Im[p].Bounds()
+	return colorValue(i.Im[p].At(r.Min.X+idx[1], r.Min.Y+idx[0]))
 }
 func (i Image) Shape() []int {
 	return i.Dims
@@ -54,6 +55,18 @@ func (i Image) Shape() []int {
 func (i Image) Size() int {
 	return prod(i.Dims)
 }
+
+// Copy returns a copy of the image.
+// The frames are copied to new RGBA images.
+func (i Image) Copy() Value {
+	r := Image{Im: make([]image.Image, len(i.Im)), Delay: i.Delay, Dims: CopyShape(i)}
+	for k, m := range i.Im {
+		c := image.NewRGBA(m.Bounds())
+		draw.Draw(c, c.Bounds(), m, m.Bounds().Min, draw.Src)
+		r.Im[k] = c
+	}
+	return r
+}
 func (i Image) Set(k int, v Value) error {
 	num, ok := v.(Number)
 	if ok == false {
@@ -69,7 +82,6 @@ func (i Image) Set(k int, v Value) error {
 		n := i.Dims[1] * i.Dims[2]
 		p = k / n
 		k -= p * n
-	} else {
 		shape = shape[1:]
 	}
 	y := k / shape[1]
@@ -92,7 +104,7 @@ func (i Image) toIntArray() IntArray {
 		r := m.Bounds()
 		for i := r.Min.Y; i < r.Max.Y; i++ {
 			for k := r.Min.X; k < r.Max.X; k++ {
-				ints[off] = int(colorValue(m.At(i, k)))
+				ints[off] = int(colorValue(m.At(k, i)))
 				off++
 			}
 		}
@@ -109,5 +121,43 @@ func colorValue(c color.Color) Int {
 }
 func toColor(i int) color.Color {
 	u := uint32(i)
-	return color.RGBA{uint8(u >> 24), uint8(u&0xFF0000) >> 16, uint8(u&0xFF00) >> 8, uint8(u & 0xFF)}
+	return color.RGBA{uint8(u >> 24), uint8((u & 0xFF0000) >> 16), uint8((u & 0xFF00) >> 8), uint8(u & 0xFF)}
+}
+
+// imageFromArray creates an Image from a numeric array of shape HEIGHT WIDTH
+// or FRAMES HEIGHT WIDTH with values in rrggbbaa format.
+func imageFromArray(v Array) (Image, error) {
+	shape := v.Shape()
+	if len(shape) != 2 && len(shape) != 3 {
+		return Image{}, fmt.Errorf("image: array must have rank 2 or 3: %v", shape)
+	}
+	frames := 1
+	if len(shape) == 3 {
+		frames = shape[0]
+	}
+	h, w := shape[len(shape)-2], shape[len(shape)-1]
+	if frames == 0 || h == 0 || w == 0 {
+		return Image{}, fmt.Errorf("image: image must not be empty")
+	}
+	im := Image{Im: make([]image.Image, frames), Dims: CopyShape(v)}
+	off := 0
+	for p := range im.Im {
+		m := image.NewRGBA(image.Rect(0, 0, w, h))
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				n, ok := v.At(off).(Number)
+				if ok == false {
+					return Image{}, fmt.Errorf("image: value must be a number: %T", v.At(off))
+				}
+				c, ok := n.ToIndex()
+				if ok == false {
+					return Image{}, fmt.Errorf("image: value must be an integer: %T", n)
+				}
+				m.Set(x, y, toColor(c))
+				off++
+			}
+		}
+		im.Im[p] = m
+	}
+	return im, nil
 }
@@ -11,6 +11,12 @@ import (
 // Int is the Integer type. It is used for numbers an indexes.
 type Int int
 
+// NullInt is the missing value of an Int, written as 0N.
+const NullInt = Int(-1 << (strconv.IntSize - 1))
+
+func (i Int) IsNull() bool { return i == NullInt }
+func (i Int) Null() Value  { return NullInt }
+
 func (i Int) ToIndex() (int, bool) {
 	return int(i), true
 }
@@ -19,6 +25,9 @@ func (i Int) ToIndex() (int, bool) {
 // The format string is passed to fmt and - is replaced by ¯,
 // except if the first rune is -.
 func (i Int) String(a *Apl) string {
+	if i == NullInt {
+		return NullString(a, "0N")
+	}
 	format := a.Fmt[reflect.TypeOf(i)]
 	minus := false
 	if len(format) > 1 && format[0] == '-' {
@@ -44,7 +53,11 @@ func (i Int) String(a *Apl) string {
 
 // ParseInt parses an integer. It replaces ¯ with -, then uses ParseInt.
 // Decimal, Octal (0x..) and Hexadecimal (0..) formats are supported.
+// 0N is parsed as NullInt.
 func ParseInt(s string) (Number, bool) {
+	if s == "0N" {
+		return NullInt, true
+	}
 	s = strings.Replace(s, "¯", "-", -1)
 	if n, err := strconv.ParseInt(s, 0, 64); err == nil {
 		return Int(n), true
@@ -56,18 +69,38 @@ func (i Int) Less(R Value) (Bool, bool) {
 	return Bool(i < R.(Int)), true
 }
 
+// AddInt, SubInt and MulInt return false, if the result overflows or if it is NullInt.
+// The numeric tower continues with the next type, which promotes the arguments to floats.
+func AddInt(x, y int) (int, bool) {
+	z := x + y
+	return z, (z > x) == (y > 0) && z != int(NullInt)
+}
+func SubInt(x, y int) (int, bool) {
+	z := x - y
+	return z, (z < x) == (y > 0) && z != int(NullInt)
+}
+func MulInt(x, y int) (int, bool) {
+	if x == 0 || y == 0 {
+		return 0, true
+	}
+	z := x * y
+	return z, z/y == x && z != int(NullInt)
+}
+
 func (i Int) Add() (Value, bool) {
 	return i, true
 }
 func (i Int) Add2(R Value) (Value, bool) {
-	return i + R.(Int), true
+	z, ok := AddInt(int(i), int(R.(Int)))
+	return Int(z), ok
 }
 
 func (i Int) Sub() (Value, bool) {
 	return -i, true
 }
 func (i Int) Sub2(R Value) (Value, bool) {
-	return i - R.(Int), true
+	z, ok := SubInt(int(i), int(R.(Int)))
+	return Int(z), ok
 }
 
 func (i Int) Mul() (Value, bool) {
@@ -79,7 +112,8 @@ func (i Int) Mul() (Value, bool) {
 	return Int(0), true
 }
 func (i Int) Mul2(R Value) (Value, bool) {
-	return i * R.(Int), true
+	z, ok := MulInt(int(i), int(R.(Int)))
+	return Int(z), ok
 }
 
 func (i Int) Div() (Value, bool) {
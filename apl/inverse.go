@@ -0,0 +1,174 @@
+package apl
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Inverter is implemented by functions that know their inverse.
+// It is used by the power operator with a negative right operand: f⍣¯1.
+//
+// The inverse is ambivalent.
+// Called monadically it returns X such that (f X) ≡ R.
+// Called dyadically the left argument is bound: it returns X such that (L f X) ≡ R.
+type Inverter interface {
+	Inverse(*Apl) (Function, error)
+}
+
+// inverseKey identifies a registered inverse by the function value
+// and the symbol of the monadic operator that derives the function from it.
+type inverseKey struct {
+	f  Function
+	op string
+}
+
+// RegisterInverse attaches the inverse inv to the function f.
+// F is identified by it's value, which must be comparable:
+// a Primitive or e.g. a go function value.
+// If op is not empty, inv is the inverse of the function derived from f by the monadic operator op.
+// The operator ⍨ uses it for the inverse of the commuted function, which solves for the left argument.
+func (a *Apl) RegisterInverse(f Function, op string, inv Function) error {
+	if f == nil || reflect.TypeOf(f).Comparable() == false {
+		return fmt.Errorf("register inverse: function is not comparable: %T", f)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.inverses[inverseKey{f, op}] = inv
+	return nil
+}
+
+// Inverse returns the inverse of the function f.
+// It returns an error, if the inverse is not known.
+func (a *Apl) Inverse(f Function) (Function, error) {
+	if inv, ok := f.(Inverter); ok {
+		return inv.Inverse(a)
+	}
+	return a.RegisteredInverse(f, "")
+}
+
+// RegisteredInverse returns the inverse that is registered for f and the operator op, see RegisterInverse.
+func (a *Apl) RegisteredInverse(f Function, op string) (Function, error) {
+	if f != nil && reflect.TypeOf(f).Comparable() {
+		if inv := a.inverse(inverseKey{f, op}); inv != nil {
+			return inv, nil
+		}
+	}
+	if v, ok := f.(Value); ok {
+		return nil, fmt.Errorf("domain error: function has no inverse: %s%s", v.String(a), op)
+	}
+	return nil, fmt.Errorf("domain error: function has no inverse: %T%s", f, op)
+}
+
+// Inverse returns the inverse of the function stored in the variable.
+func (f fnVar) Inverse(a *Apl) (Function, error) {
+	x := a.Lookup(string(f))
+	fn, ok := x.(Function)
+	if ok == false || fn == nil {
+		return nil, fmt.Errorf("domain error: function has no inverse: %s", string(f))
+	}
+	return a.Inverse(fn)
+}
+
+// Inverse returns the inverse of a derived function.
+// The operands are evaluated and the inverse is requested from the derived function
+// that is returned by the operator.
+func (d *derived) Inverse(a *Apl) (Function, error) {
+	ops, ok := a.lookupOperator(d.op)
+	if ok == false || len(ops) == 0 || ops[0] == nil {
+		return nil, Errorf(ValueError, "operator %s does not exist", d.op)
+	}
+	if d.op == "←" {
+		return nil, fmt.Errorf("domain error: function has no inverse: %s", d.String(a))
+	}
+
+	var lo, ro Value
+	var err error
+	if ops[0].DyadicOp() {
+		ro, err = d.ro.Eval(a)
+		if err != nil {
+			return nil, err
+		}
+	}
+	lo, err = d.lo.Eval(a)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		if LO, RO, ok := op.To(a, lo, ro); ok {
+			f := op.Derived(a, LO, RO)
+			if inv, ok := f.(Inverter); ok {
+				return inv.Inverse(a)
+			}
+			break
+		}
+	}
+	return nil, fmt.Errorf("domain error: function has no inverse: %s", d.String(a))
+}
+
+// Inverse returns the inverse of a function train.
+func (t train) Inverse(a *Apl) (Function, error) {
+	f, err := t.function(a)
+	if err != nil {
+		return nil, err
+	}
+	return a.Inverse(f)
+}
+
+// Inverse of an atop: X ←→ L h⍣¯1 g⍣¯1 R.
+func (t atop) Inverse(a *Apl) (Function, error) {
+	g, ok := t[0].(Function)
+	if ok == false {
+		return nil, fmt.Errorf("atop: expected function g: %T", t[0])
+	}
+	h, ok := t[1].(Function)
+	if ok == false {
+		return nil, fmt.Errorf("atop: expected function h: %T", t[1])
+	}
+	gi, err := a.Inverse(g)
+	if err != nil {
+		return nil, err
+	}
+	hi, err := a.Inverse(h)
+	if err != nil {
+		return nil, err
+	}
+	return ToFunction(func(a *Apl, L, R Value) (Value, error) {
+		v, err := gi.Call(a, nil, R)
+		if err != nil {
+			return nil, err
+		}
+		return hi.Call(a, L, v)
+	}), nil
+}
+
+// Inverse of a fork.
+// Only forks with a constant left tine (A g h) can be inverted: X ←→ L h⍣¯1 A g⍣¯1 R.
+func (fk fork) Inverse(a *Apl) (Function, error) {
+	if _, ok := fk[0].(Function); ok {
+		return nil, fmt.Errorf("domain error: function has no inverse: %s", fk.String(a))
+	}
+	g, ok := fk[1].(Function)
+	if ok == false {
+		return nil, fmt.Errorf("fork: expected function g: %T", fk[1])
+	}
+	h, ok := fk[2].(Function)
+	if ok == false {
+		return nil, fmt.Errorf("fork: expected function h: %T", fk[2])
+	}
+	gi, err := a.Inverse(g)
+	if err != nil {
+		return nil, err
+	}
+	hi, err := a.Inverse(h)
+	if err != nil {
+		return nil, err
+	}
+	A := fk[0]
+	return ToFunction(func(a *Apl, L, R Value) (Value, error) {
+		v, err := gi.Call(a, A, R)
+		if err != nil {
+			return nil, err
+		}
+		return hi.Call(a, L, v)
+	}), nil
+}
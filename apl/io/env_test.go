@@ -0,0 +1,43 @@
+package io
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/numbers"
+	"github.com/ktye/iv/apl/operators"
+	"github.com/ktye/iv/apl/primitives"
+)
+
+// TestEnvAssign tests modified indexed assignment to environment variables.
+func TestEnvAssign(t *testing.T) {
+	a := apl.New(nil)
+	numbers.Register(a)
+	primitives.Register(a)
+	operators.Register(a)
+	Register(a, "")
+
+	os.Setenv("IVTEST", "a")
+	defer os.Unsetenv("IVTEST")
+	os.Unsetenv("IVTEST_MISSING")
+	for _, tc := range []struct {
+		in, exp string
+		fail    bool
+	}{
+		{"E←io→e 0⋄E[`IVTEST]{⍵}←`b", "b", false},
+		{"E←io→e 0⋄E[`IVTEST]+←1", "b", true},
+		{"E←io→e 0⋄E[`IVTEST_MISSING]{⍵}←`c", "b", true},
+	} {
+		err := a.ParseAndEval(tc.in)
+		if tc.fail != (err != nil) {
+			t.Fatalf("%s: unexpected error: %v", tc.in, err)
+		}
+		if got := os.Getenv("IVTEST"); got != tc.exp {
+			t.Fatalf("%s: IVTEST is %q, expected %q", tc.in, got, tc.exp)
+		}
+	}
+	if _, ok := os.LookupEnv("IVTEST_MISSING"); ok {
+		t.Fatal("IVTEST_MISSING has been created")
+	}
+}
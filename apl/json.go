@@ -0,0 +1,238 @@
+package apl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseJson parses a json value, e.g. data that has been written with ¯2⍕V.
+//	Objects are converted to a *Dict with String keys in the order of the input.
+//	Arrays of scalars are uniform arrays if possible, or numeric arrays. Other arrays are Lists.
+//	Nested arrays of the same shape are converted to an array of higher rank, e.g. a matrix.
+//	An empty array is ⍬.
+//	Numbers are parsed by the numeric tower, true and false are Bools.
+//	Null is a missing value of the type of the other values in the same array or table column,
+//	or the integer null 0N. Empty input is ⍬.
+// If tables is true, an array of objects that all have the same keys is converted to a Table.
+func (a *Apl) ParseJson(s string, tables bool) (Value, error) {
+	p := jsonParser{a: a, d: json.NewDecoder(strings.NewReader(s)), n: int64(len(s)), tables: tables}
+	p.d.UseNumber()
+	v, err := p.value()
+	if err == io.EOF {
+		return EmptyArray{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("parse json: %s", err)
+	}
+	if _, err := p.d.Token(); err != io.EOF {
+		return nil, fmt.Errorf("parse json: data after value")
+	}
+	return v, nil
+}
+
+type jsonParser struct {
+	a      *Apl
+	d      *json.Decoder
+	n      int64 // length of the input
+	depth  int   // nesting level of arrays and objects
+	tables bool
+}
+
+// token returns the next token.
+// The end of the input within an array or object is io.ErrUnexpectedEOF.
+// Depending on the go version, the decoder returns io.EOF or a syntax error.
+func (p *jsonParser) token() (json.Token, error) {
+	t, err := p.d.Token()
+	if err == nil || p.depth == 0 {
+		return t, err
+	}
+	if se, ok := err.(*json.SyntaxError); err == io.EOF || (ok && se.Offset >= p.n) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return t, err
+}
+
+func (p *jsonParser) value() (Value, error) {
+	t, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := t.(type) {
+	case json.Delim:
+		p.depth++
+		defer func() { p.depth-- }()
+		if v == '{' {
+			return p.object()
+		} else if v == '[' {
+			return p.array()
+		}
+		return nil, fmt.Errorf("unexpected %s", v)
+	case bool:
+		return Bool(v), nil
+	case json.Number:
+		n, err := p.a.Tower.Parse(strings.Replace(string(v), "-", "¯", -1))
+		if err != nil {
+			return nil, err
+		}
+		return n.Number, nil
+	case string:
+		return String(v), nil
+	case nil:
+		return NullInt, nil
+	}
+	return nil, fmt.Errorf("unknown token: %v", t)
+}
+
+func (p *jsonParser) object() (Value, error) {
+	d := Dict{M: make(map[Value]Value)}
+	for p.d.More() {
+		t, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		k := String(t.(string))
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := d.M[k]; ok == false {
+			d.K = append(d.K, k)
+		}
+		d.M[k] = v
+	}
+	if _, err := p.token(); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (p *jsonParser) array() (Value, error) {
+	var l List
+	for p.d.More() {
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, v)
+	}
+	if _, err := p.token(); err != nil {
+		return nil, err
+	}
+	if len(l) == 0 {
+		return EmptyArray{}, nil
+	}
+
+	if p.tables {
+		if t, ok := p.table(l); ok {
+			return t, nil
+		}
+	}
+	typedNulls(l)
+	if m, ok := p.rectangular(l); ok {
+		return m, nil
+	}
+	for _, v := range l {
+		switch v.(type) {
+		case Array, Object:
+			return l, nil
+		}
+	}
+	m := MixedArray{Dims: []int{len(l)}, Values: l}
+	if u, ok := p.a.Unify(m, true); ok {
+		return u, nil
+	}
+	for _, v := range l {
+		if _, ok := v.(Number); ok == false {
+			return l, nil
+		}
+	}
+	return m, nil
+}
+
+// rectangular converts a list of arrays with the same shape to a uniform array of higher rank.
+func (p *jsonParser) rectangular(l List) (Array, bool) {
+	var shape []int
+	var values []Value
+	for i, v := range l {
+		ar, ok := v.(Array)
+		if _, isl := v.(List); ok == false || isl {
+			return nil, false
+		}
+		s := ar.Shape()
+		if i == 0 {
+			if len(s) == 0 {
+				return nil, false
+			}
+			shape = s
+		} else if len(s) != len(shape) {
+			return nil, false
+		}
+		for k := range s {
+			if s[k] != shape[k] {
+				return nil, false
+			}
+		}
+		for k := 0; k < ar.Size(); k++ {
+			values = append(values, ar.At(k))
+		}
+	}
+	m := MixedArray{Dims: append([]int{len(l)}, shape...), Values: values}
+	if u, ok := p.a.Unify(m, true); ok {
+		return u, true
+	}
+	return nil, false
+}
+
+// table converts a list of dicts with the same keys to a table.
+func (p *jsonParser) table(l List) (Table, bool) {
+	d0, ok := l[0].(*Dict)
+	if ok == false || len(d0.K) == 0 {
+		return Table{}, false
+	}
+	for _, v := range l[1:] {
+		d, ok := v.(*Dict)
+		if ok == false || len(d.K) != len(d0.K) {
+			return Table{}, false
+		}
+		for i := range d.K {
+			if d.K[i] != d0.K[i] {
+				return Table{}, false
+			}
+		}
+	}
+	d := Dict{K: d0.K, M: make(map[Value]Value)}
+	for _, k := range d.K {
+		m := MixedArray{Dims: []int{len(l)}, Values: make([]Value, len(l))}
+		for i := range l {
+			m.Values[i] = l[i].(*Dict).M[k]
+		}
+		typedNulls(m.Values)
+		if u, ok := p.a.Unify(m, true); ok {
+			d.M[k] = u
+		} else {
+			d.M[k] = m
+		}
+	}
+	return Table{Dict: &d, Rows: len(l)}, true
+}
+
+// typedNulls replaces json nulls with the null value of the type
+// of the first value that is not null.
+func typedNulls(l []Value) {
+	var null Value
+	for _, v := range l {
+		if n, ok := v.(Nullable); ok && n.IsNull() == false {
+			null = n.Null()
+			break
+		}
+	}
+	if null == nil {
+		return
+	}
+	for i, v := range l {
+		if v == NullInt {
+			l[i] = null
+		}
+	}
+}
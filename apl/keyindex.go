@@ -0,0 +1,145 @@
+package apl
+
+// KeyIndex assigns ids to the rows of tables by the values of their key columns.
+// Rows with equal keys have the same id, also if they are from different tables.
+// The ids are dense and assigned in the order in which new keys are added.
+//
+// Each key column maps it's values to column ids.
+// For multiple key columns, the id of the previous columns is combined
+// with the column id of the next one, similar to grouping rows with the key operator.
+//
+// Integers and strings are hashed directly, other numbers that are integers are hashed as integers.
+// Other values are hashed by their string representation in the data format (PP ¯1).
+type KeyIndex struct {
+	keys  []Value
+	cols  []columnIndex
+	pairs []map[[2]int]int
+	n     int
+}
+
+// columnIndex maps the values of a key column to column ids.
+type columnIndex struct {
+	ints  map[int]int
+	strs  map[string]int
+	other map[string]int
+}
+
+// NewKeyIndex returns an empty index for the key columns.
+func NewKeyIndex(keys []Value) *KeyIndex {
+	x := KeyIndex{keys: keys, cols: make([]columnIndex, len(keys)), pairs: make([]map[[2]int]int, len(keys))}
+	for i := range x.cols {
+		x.cols[i] = columnIndex{ints: make(map[int]int), strs: make(map[string]int), other: make(map[string]int)}
+		x.pairs[i] = make(map[[2]int]int)
+	}
+	return &x
+}
+
+// Len returns the number of ids.
+func (x *KeyIndex) Len() int { return x.n }
+
+// Ids returns the id of each row of t, or -1 if a key value is missing.
+// If add is true, new keys are added to the index.
+// Otherwise the id of a key that is not in the index is also -1.
+// The key columns must exist in t.
+func (x *KeyIndex) Ids(a *Apl, t Table, add bool) []int {
+	pp := a.PP
+	a.PP = -1
+	defer func() { a.PP = pp }()
+
+	ids := make([]int, t.Rows)
+	col := make([]int, t.Rows)
+	for c, k := range x.keys {
+		x.cols[c].ids(a, t.M[k].(Array), col, add)
+		last := c == len(x.keys)-1
+		for i := range ids {
+			if col[i] < 0 || (c > 0 && ids[i] < 0) {
+				ids[i] = -1
+				continue
+			}
+			p := [2]int{col[i], 0}
+			if c > 0 {
+				p[1] = ids[i]
+			}
+			id, ok := x.pairs[c][p]
+			if ok == false && add {
+				id = len(x.pairs[c])
+				if last {
+					id = x.n
+					x.n++
+				}
+				x.pairs[c][p] = id
+			} else if ok == false {
+				id = -1
+			}
+			ids[i] = id
+		}
+	}
+	return ids
+}
+
+// ids sets the column id of each value of c in z.
+func (ci columnIndex) ids(a *Apl, c Array, z []int, add bool) {
+	lookup := func(m map[int]int, k int) int {
+		id, ok := m[k]
+		if ok == false {
+			if add == false {
+				return -1
+			}
+			id = ci.size()
+			m[k] = id
+		}
+		return id
+	}
+	slookup := func(m map[string]int, k string) int {
+		id, ok := m[k]
+		if ok == false {
+			if add == false {
+				return -1
+			}
+			id = ci.size()
+			m[k] = id
+		}
+		return id
+	}
+
+	switch v := c.(type) {
+	case IntArray:
+		for i := range z {
+			if n := v.Ints[i]; Int(n) == NullInt {
+				z[i] = -1
+			} else {
+				z[i] = lookup(ci.ints, n)
+			}
+		}
+		return
+	case StringArray:
+		for i := range z {
+			if s := v.Strings[i]; s == "" {
+				z[i] = -1
+			} else {
+				z[i] = slookup(ci.strs, s)
+			}
+		}
+		return
+	}
+	for i := range z {
+		v := c.At(i)
+		if IsNull(v) {
+			z[i] = -1
+			continue
+		} else if s, ok := v.(String); ok {
+			z[i] = slookup(ci.strs, string(s))
+			continue
+		} else if n, ok := v.(Number); ok {
+			if k, ok := n.ToIndex(); ok {
+				z[i] = lookup(ci.ints, k)
+				continue
+			}
+		}
+		z[i] = slookup(ci.other, v.String(a))
+	}
+}
+
+func (ci columnIndex) size() int {
+	return len(ci.ints) + len(ci.strs) + len(ci.other)
+}
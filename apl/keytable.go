@@ -0,0 +1,310 @@
+package apl
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+)
+
+// A KeyTable is a keyed table, similar to q.
+// It is a dictionary from a key table K to a value table V with the same number of rows.
+// Each row of K is unique and contains no missing values.
+//
+// A keyed table is created from two tables by K#V, e.g.
+//	KT←T[;`Sym`Date]#T[;`Qty`Px]
+// #KT returns the key table.
+// Indexing by keys looks up the value rows:
+//	KT[`a]          ⍝ single key column: a dict of the values
+//	KT[`a`b]        ⍝ a vector of keys returns a table
+//	I←(`a;20200102;)⋄KT[I]  ⍝ multiple key columns: a list of keys
+//	KT[#KT]         ⍝ a table with a key row in each row, returns the value table
+// Missing keys return rows with null values.
+// The keyed table is unkeyed by catenating the key and value tables: (#KT),KT[#KT]
+//
+// Catenation with a table, a dict or another keyed table is an upsert:
+//	KT,←T
+// Rows of R with keys that exist in KT replace their values, rows with new keys are appended.
+type KeyTable struct {
+	K Table
+	V Table
+	x *keyIndex
+}
+
+// keyIndex is the index of the keys of a keyed table.
+// It is shared with the keyed tables returned by Upsert.
+// The id of a key is it's row. The table with as many rows as the index
+// is the latest version, only this one may add keys.
+// Owned is true, if the columns of the latest version have been allocated by Upsert
+// and new rows can be appended in place.
+type keyIndex struct {
+	sync.Mutex
+	*KeyIndex
+	owned bool
+}
+
+// NewKeyTable returns a keyed table from the key table k and the value table v.
+// Both must have the same number of rows and must not share columns.
+// The key table needs at least one column and its rows must be unique without missing values.
+func (a *Apl) NewKeyTable(k, v Table) (KeyTable, error) {
+	if len(k.K) == 0 {
+		return KeyTable{}, fmt.Errorf("keyed table: key table has no columns")
+	} else if k.Rows != v.Rows {
+		return KeyTable{}, fmt.Errorf("keyed table: key table has %d rows, value table %d", k.Rows, v.Rows)
+	}
+	for _, c := range k.K {
+		if _, ok := v.M[c]; ok {
+			return KeyTable{}, fmt.Errorf("keyed table: column is key and value: %s", c.String(a))
+		}
+	}
+
+	x := NewKeyIndex(k.K)
+	for i, id := range x.Ids(a, k, true) {
+		if id < 0 {
+			return KeyTable{}, fmt.Errorf("keyed table: missing key value in row %d", i+a.Origin)
+		} else if id != i {
+			return KeyTable{}, fmt.Errorf("keyed table: duplicate key in row %d", i+a.Origin)
+		}
+	}
+	return KeyTable{K: k, V: v, x: &keyIndex{KeyIndex: x}}, nil
+}
+
+// index returns the locked key index of t.
+func (t KeyTable) index(a *Apl) *keyIndex {
+	x := t.x
+	if x == nil {
+		x = &keyIndex{KeyIndex: NewKeyIndex(t.K.K)}
+		x.Ids(a, t.K, true)
+	}
+	x.Lock()
+	return x
+}
+
+// Lookup returns the row of each key in k, or -1 if it does not exist.
+// The key columns must exist in k.
+func (t KeyTable) Lookup(a *Apl, k Table) []int {
+	x := t.index(a)
+	defer x.Unlock()
+	ids := x.Ids(a, k, false)
+	for i, id := range ids {
+		if id >= t.K.Rows {
+			ids[i] = -1
+		}
+	}
+	return ids
+}
+
+// Upsert returns the keyed table updated with the rows of r.
+// R must contain all key columns and no other columns than t.
+// Rows with keys that exist in t replace the given values, rows with new keys are appended.
+// Value columns that are not given in r are null for new rows.
+//
+// The keys of r are added to the index of t, if t is the latest version.
+// Then the columns of new rows are appended in place, if they are uniform (see Appender).
+// Otherwise the index is rebuilt and columns are copied.
+func (t KeyTable) Upsert(a *Apl, r Table) (KeyTable, error) {
+	x := t.index(a)
+	defer x.Unlock()
+	own := x.Len() == t.K.Rows
+	if own == false {
+		x = &keyIndex{KeyIndex: NewKeyIndex(t.K.K)}
+		x.Ids(a, t.K, true)
+	}
+
+	idx := x.Ids(a, r, true)
+	for i, j := range idx {
+		if j < 0 {
+			return KeyTable{}, fmt.Errorf("upsert: missing key value in row %d", i+a.Origin)
+		}
+	}
+	rows := x.Len()
+
+	res := KeyTable{
+		K: Table{Dict: &Dict{M: make(map[Value]Value)}, Rows: rows},
+		V: Table{Dict: &Dict{M: make(map[Value]Value)}, Rows: rows},
+		x: x,
+	}
+	for _, s := range []struct {
+		src, dst Table
+		key      bool
+	}{{t.K, res.K, true}, {t.V, res.V, false}} {
+		for _, k := range s.src.K {
+			rc, _ := r.M[k].(Array)
+			s.dst.K = append(s.dst.K, k)
+			s.dst.M[k] = upsertColumn(a, s.src.M[k].(Array), rc, idx, rows, own && x.owned, s.key)
+		}
+	}
+	if rows > t.K.Rows {
+		x.owned = true
+	}
+	return res, nil
+}
+
+// upsertColumn returns the column c with the values of rc at the rows idx.
+// Rows beyond the size of c are appended, and are null if rc is nil.
+// If inplace is true, c may be appended in place.
+// Updated columns are copied, key columns are never updated.
+func upsertColumn(a *Apl, c, rc Array, idx []int, rows int, inplace, key bool) Array {
+	n := c.Size()
+	tail := make([]Value, rows-n)
+	update := false
+	if rc != nil {
+		for i, j := range idx {
+			if j < n {
+				update = key == false
+			} else {
+				tail[j-n] = rc.At(i)
+			}
+		}
+	} else {
+		null := a.Fill(c)
+		if v, ok := null.(Nullable); ok {
+			null = v.Null()
+		}
+		for i := range tail {
+			tail[i] = null
+		}
+	}
+
+	u := c
+	if update {
+		u = nil
+		if s, ok := Copy(c).(ArraySetter); ok && c.Size() > 0 {
+			u = s
+			for i, j := range idx {
+				if j < n && s.Set(j, rc.At(i)) != nil {
+					u = nil
+					break
+				}
+			}
+		}
+	}
+	if u != nil && len(tail) == 0 {
+		return u
+	} else if ap, ok := u.(Appender); ok && (inplace || update) {
+		if v, ok := ap.Append(tail); ok {
+			return v
+		}
+	}
+
+	m := MixedArray{Dims: []int{rows}, Values: make([]Value, rows)}
+	for i := 0; i < n; i++ {
+		m.Values[i] = c.At(i)
+	}
+	copy(m.Values[n:], tail)
+	if update {
+		for i, j := range idx {
+			if j < n {
+				m.Values[j] = rc.At(i)
+			}
+		}
+	}
+	if v, ok := a.Unify(m, true); ok {
+		return v
+	}
+	return m
+}
+
+// String formats a keyed table with a tabwriter, the key columns
+// are separated from the values by a vertical bar.
+// In json format (PP ¯2) and matlab format (PP ¯3), it is written as a dict
+// with the keys "key" and "value" that contain both tables.
+func (t KeyTable) String(a *Apl) string {
+	if a.PP == -2 || a.PP == -3 {
+		d := Dict{
+			K: []Value{String("key"), String("value")},
+			M: map[Value]Value{String("key"): t.K, String("value"): t.V},
+		}
+		return d.String(a)
+	}
+	var b bytes.Buffer
+	if err := t.WriteFormatted(a, nil, &b); err != nil {
+		return ""
+	}
+	return string(b.Bytes())
+}
+
+// Copy returns a deep copy of the keyed table.
+// The key index is shared.
+func (t KeyTable) Copy() Value {
+	return KeyTable{K: t.K.Copy().(Table), V: t.V.Copy().(Table), x: t.x}
+}
+
+// Table returns a table with the key columns followed by the value columns.
+func (t KeyTable) Table() Table {
+	d := Dict{M: make(map[Value]Value)}
+	for _, s := range []Table{t.K, t.V} {
+		for _, k := range s.K {
+			d.K = append(d.K, k)
+			d.M[k] = s.M[k]
+		}
+	}
+	return Table{Dict: &d, Rows: t.K.Rows}
+}
+
+// WriteFormatted writes the keyed table with a tabwriter.
+// The format of the values is given by L in the same way as for Table.Csv.
+func (t KeyTable) WriteFormatted(a *Apl, L Object, w io.Writer) error {
+	u := t.Table()
+	f := u.newFormatter(a, L)
+	defer f.Close()
+
+	tw := tabwriter.NewWriter(w, 1, 0, 1, ' ', 0)
+	if err := u.write(a, f, keySeparator{wsTable{tw}, len(t.K.K)}); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
+
+// Csv writes the keyed table in csv format, see Table.Csv.
+// The key columns come first and their names are enclosed in brackets
+// in the header, e.g. [Sym],Qty,Px
+// ParseCsv returns a keyed table for such a header.
+func (t KeyTable) Csv(a *Apl, L Object, w io.Writer) error {
+	u := t.Table()
+	f := u.newFormatter(a, L)
+	defer f.Close()
+
+	cw := csv.NewWriter(w)
+	if err := u.write(a, f, &keyHeader{csvTable{cw}, len(t.K.K), true}); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// keySeparator inserts a vertical bar after the first n columns of each row.
+type keySeparator struct {
+	rowWriter
+	n int
+}
+
+func (k keySeparator) writeRow(records []string) error {
+	r := make([]string, 0, len(records)+1)
+	r = append(r, records[:k.n]...)
+	r = append(r, "|")
+	r = append(r, records[k.n:]...)
+	return k.rowWriter.writeRow(r)
+}
+
+// keyHeader encloses the first n names of the header row in brackets.
+type keyHeader struct {
+	csvTable
+	n      int
+	header bool
+}
+
+func (k *keyHeader) writeRow(records []string) error {
+	if k.header {
+		k.header = false
+		r := make([]string, len(records))
+		copy(r, records)
+		for i := 0; i < k.n; i++ {
+			r[i] = "[" + r[i] + "]"
+		}
+		records = r
+	}
+	return k.csvTable.writeRow(records)
+}
@@ -3,19 +3,100 @@ package apl
 import (
 	"fmt"
 	"strings"
+	"sync"
+
+	"github.com/ktye/iv/apl/scan"
 )
 
 // Env is the environment of the current lambda function.
 // It contains local variables and a pointer to the parent environment.
+// The variables are guarded by mu, as forks of the interpreter share environments.
+// Owned marks variables, who's values are not shared and can be modified in place, see Copier.
 type env struct {
 	parent *env
+	mu     sync.RWMutex
 	vars   map[string]Value
+	owned  map[string]bool
+}
+
+// get returns the value of a variable in e, but not in it's parents.
+// The value is shared from now on.
+func (e *env) get(name string) (Value, bool) {
+	e.mu.RLock()
+	v, ok := e.vars[name]
+	owned := e.owned[name]
+	e.mu.RUnlock()
+	if owned == false {
+		return v, ok
+	}
+
+	// The value must be read and marked as shared at once,
+	// otherwise own may still modify it in place.
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	v, ok = e.vars[name]
+	e.mark(name, false)
+	return v, ok
+}
+
+// own returns the value of a variable in e for modification in place.
+// A shared value is replaced by a copy.
+func (e *env) own(name string) (Value, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	v, ok := e.vars[name]
+	if ok && e.owned[name] == false {
+		v = Copy(v)
+		e.vars[name] = v
+		e.mark(name, true)
+	}
+	return v, ok
+}
+
+// set stores a shared value.
+func (e *env) set(name string, v Value) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.vars[name] = v
+	e.mark(name, false)
+}
+
+// setOwned stores a value that is not shared.
+func (e *env) setOwned(name string, v Value) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.vars[name] = v
+	e.mark(name, true)
+}
+
+func (e *env) mark(name string, owned bool) {
+	if owned == false {
+		delete(e.owned, name)
+		return
+	}
+	if e.owned == nil {
+		e.owned = make(map[string]bool)
+	}
+	e.owned[name] = true
+}
+
+// names returns the names of the variables in e.
+func (e *env) names() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	l := make([]string, 0, len(e.vars))
+	for n := range e.vars {
+		l = append(l, n)
+	}
+	return l
 }
 
 // lambda is a function expression in braces {...}.
 // It is also known under the term dynamic function or dfn.
+// Tokens are only kept for the body of a user defined operator, see lambdaOp.
 type lambda struct {
-	body guardList
+	body   guardList
+	tokens []scan.Token
 }
 
 func (λ *lambda) String(a *Apl) string {
@@ -30,8 +111,19 @@ func (λ *lambda) Eval(a *Apl) (Value, error) {
 }
 
 func (λ *lambda) Call(a *Apl, l, r Value) (Value, error) {
+	v, _, err := λ.call(a, nil, nil, l, r)
+	return v, err
+}
+
+func (λ *lambda) callShy(a *Apl, l, r Value) (Value, bool, error) {
+	return λ.call(a, nil, nil, l, r)
+}
+
+// call calls the lambda function and reports if the result is shy.
+// If it is the body of a user defined operator, lo and ro are the operands ⍺⍺ and ⍵⍵.
+func (λ *lambda) call(a *Apl, lo, ro, l, r Value) (Value, bool, error) {
 	if λ.body == nil {
-		return EmptyArray{}, nil
+		return EmptyArray{}, false, nil
 	}
 
 	e := env{
@@ -42,27 +134,33 @@ func (λ *lambda) Call(a *Apl, l, r Value) (Value, error) {
 	a.env = &e
 	defer func() { a.env = save }()
 
-	e.vars["∇"] = λ
+	e.set("∇", λ)
+	if lo != nil {
+		e.set("⍺⍺", lo)
+	}
+	if ro != nil {
+		e.set("⍵⍵", ro)
+	}
 tail:
-	e.vars["⍺"] = l
-	e.vars["⍵"] = r
+	e.set("⍺", l)
+	e.set("⍵", r)
 
-	if v, err := λ.body.Eval(a); err != nil {
-		return nil, err
+	if v, shy, err := λ.body.evalShy(a); err != nil {
+		return nil, false, err
 	} else if t, ok := v.(*tail); ok {
 		r, err = t.right.Eval(a)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 		if t.left != nil {
 			l, err = t.left.Eval(a)
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
 		}
 		goto tail
 	} else {
-		return v, nil
+		return v, shy, nil
 	}
 }
 
@@ -78,57 +176,95 @@ func (l guardList) String(a *Apl) string {
 	return strings.Join(v, "⋄")
 }
 
-// Eval evaluates the guardList.
+func (l guardList) Eval(a *Apl) (Value, error) {
+	v, _, err := l.evalShy(a)
+	return v, err
+}
+
+// evalShy evaluates the guardList.
 // It checks the condition of each guardExpr.
 // Expressions are only evaluated, if the condition returns true or
 // is nil.
-// The function returns after the first evaluated expression, if it is
-// not an assignment.
+// Statements are evaluated in sequence:
+//	- a guarded expression returns, if the condition is true and it is not an assignment
+//	- the last expression returns it's value
+//	- assignments and all other unguarded expressions continue with the next statement
+// If the result is the value of an assignment, it is shy and not printed:
+//	{X←⍵}3
+// Shyness is returned with the value, see shyCaller.
 //
-// TODO: Extensions to dfns:
-//	- in a guarded expr, continue if there is an assignment
-//	- in a nonguarded expr, always continue
-func (l guardList) Eval(a *Apl) (Value, error) {
+// An error guard (numbers::expr) is armed when it is reached.
+// If any following expression fails with an error number in the list,
+// or any error for 0, the guard's expression is evaluated and returned instead.
+// Within the scope of an error guard, tail calls are not optimized.
+func (l guardList) evalShy(a *Apl) (Value, bool, error) {
 	if len(l) == 0 {
-		return EmptyArray{}, nil
+		return EmptyArray{}, false, nil
 	}
 	var ret Value = EmptyArray{}
+	var traps []errorTrap
+	shy := false
 	for i, g := range l {
-		isa := isAssignment(g.e)
-		if g.cond == nil && i < len(l)-1 && isa == false {
-			return nil, fmt.Errorf("λ contains non-reachable code")
+		if g.trap {
+			t, err := g.arm(a)
+			if err != nil {
+				return nil, false, err
+			}
+			traps = append(traps, t)
+			continue
 		}
 
-		if f, ok := g.e.(Function); ok && isa == false {
-			if fn, ok := f.(*function); ok {
-				if _, ok := fn.Function.(self); ok {
-					return &tail{fn.left, fn.right}, nil
-				}
+		isa := isAssignment(g.e)
+		result := isa == false && (g.cond != nil || i == len(l)-1)
+
+		if t := g.tail(); t != nil && result && traps == nil {
+			if ok, err := g.test(a); err != nil {
+				return nil, false, err
+			} else if ok {
+				return t, false, nil
 			}
+			continue
 		}
 
-		if v, err := g.Eval(a); err != nil {
-			return nil, err
+		var v Value
+		var err error
+		if traps == nil {
+			v, err = g.Eval(a)
+		} else {
+			v, err = g.protect(a)
+		}
+		if err != nil {
+			e := wrapError(a, err, g)
+			if v, ok, err := catch(a, traps, e); ok {
+				return v, false, err
+			}
+			return nil, false, e
 		} else if v != nil {
-			ret = v
-			if isa == false {
-				return ret, nil
+			if result {
+				return v, false, nil
+			} else if isa {
+				ret = v
+				shy = isa
 			}
 		}
 	}
-	return ret, nil
+	return ret, shy, nil
 }
 
 // guardExpr contains a guarded expression.
 // It's expressions is evaluated if the condition returns true or is nil.
+// If trap is set, it is an error guard and cond contains the error numbers.
 type guardExpr struct {
 	cond expr
 	e    expr
+	trap bool
 }
 
 func (g *guardExpr) String(a *Apl) string {
 	if g.cond == nil {
 		return g.e.String(a)
+	} else if g.trap {
+		return g.cond.String(a) + "::" + g.e.String(a)
 	} else {
 		return g.cond.String(a) + ":" + g.e.String(a)
 	}
@@ -140,13 +276,34 @@ func (g *guardExpr) String(a *Apl) string {
 // If the condition is nil or returns true, the expression is evaluated,
 // otherwise nil is returned and no error.
 func (g *guardExpr) Eval(a *Apl) (Value, error) {
+	if ok, err := g.test(a); err != nil {
+		return nil, err
+	} else if ok == false {
+		return nil, nil
+	}
+	return g.e.Eval(a)
+}
+
+// tail returns a tail call, if the expression is a call to ∇.
+func (g *guardExpr) tail() *tail {
+	if fn, ok := g.e.(*function); ok {
+		if _, ok := fn.Function.(self); ok {
+			return &tail{fn.left, fn.right}
+		}
+	}
+	return nil
+}
+
+// test evaluates the condition of a guarded expression.
+// It returns true, if the condition is nil.
+func (g *guardExpr) test(a *Apl) (bool, error) {
 	if g.cond == nil {
-		return g.e.Eval(a)
+		return true, nil
 	}
 
 	v, err := g.cond.Eval(a)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 	b, isbool := v.(Bool)
 	if isbool == false {
@@ -158,14 +315,9 @@ func (g *guardExpr) Eval(a *Apl) (Value, error) {
 		}
 	}
 	if isbool == false {
-		return nil, fmt.Errorf("λ condition does not return a bool: %s", b.String(a))
-	}
-
-	if b == false {
-		return nil, nil
-	} else {
-		return g.e.Eval(a)
+		return false, fmt.Errorf("λ condition does not return a bool: %s", v.String(a))
 	}
+	return bool(b), nil
 }
 
 // Self is both an expression and a Value self-pointing to a lambda function.
@@ -183,7 +335,7 @@ func (s self) Call(a *Apl, L, R Value) (Value, error) {
 	if a.env.parent == nil {
 		return nil, fmt.Errorf("cannot call ∇ outside lambda")
 	}
-	v, ok := a.env.vars["∇"]
+	v, ok := a.env.get("∇")
 	if ok == false {
 		return nil, fmt.Errorf("∇ has not been registered") // should not happen
 	}
@@ -202,3 +354,132 @@ type tail struct {
 func (t tail) String(a *Apl) string {
 	return fmt.Sprintf("tail{%s %s}", t.left.String(a), t.right.String(a))
 }
+
+// lambdaOp is a user defined operator.
+// It is created by assigning a lambda expression to an operator name,
+// which starts with an underscore: _name for a monadic and _name_ for a dyadic operator.
+// Within the lambda expression, the operands are ⍺⍺ and ⍵⍵.
+// Operands may be functions or arrays.
+//	_twice←{⍺⍺ ⍺⍺ ⍵}
+//	-_twice 3
+//	_add_←{⍺⍺+⍵⍵+⍵}
+//	(1 _add_ 2) 3
+// The parser decides by the name if a variable is a function or an array.
+// The lambda is parsed with ⍺⍺ and ⍵⍵ as functions.
+// For array operands, it is parsed again with the operand as an array, see body.
+//
+// An operator that is defined within a lambda expression is local to it's environment.
+// Otherwise it is registered for the interpreter and all it's forks.
+type lambdaOp struct {
+	name   string
+	λ      *lambda
+	dyadic bool
+	parsed *sync.Map // lambdas parsed for array operands by the names of the operands
+}
+
+// assignOperator registers a lambda expression as a user defined operator.
+// A previous definition with the same name is replaced.
+// Within a lambda expression, the operator is stored in the local environment.
+func (a *Apl) assignOperator(name string, v Value, dyadic bool) error {
+	λ, ok := v.(*lambda)
+	if ok == false {
+		return fmt.Errorf("only lambda expressions can be assigned to operator %s: %T", name, v)
+	}
+	op := lambdaOp{name: name, λ: λ, dyadic: dyadic, parsed: &sync.Map{}}
+	if a.env.parent != nil {
+		a.env.set(name, op)
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.operators, name)
+	return a.registerOperator(name, op)
+}
+
+// lookupOperator returns the handlers of an operator.
+// User defined operators that are local to a lambda expression are found first.
+func (a *Apl) lookupOperator(s string) ([]Operator, bool) {
+	if isop, _ := scan.IsOperatorName(s); isop {
+		if op, ok := a.Lookup(s).(lambdaOp); ok {
+			return []Operator{op}, true
+		}
+	}
+	return a.operator(s)
+}
+
+func (op lambdaOp) To(a *Apl, LO, RO Value) (Value, Value, bool) {
+	return LO, RO, true
+}
+
+func (op lambdaOp) String(a *Apl) string {
+	if op.dyadic {
+		return "LO and RO are any values"
+	}
+	return "LO is any value"
+}
+
+func (op lambdaOp) DyadicOp() bool { return op.dyadic }
+
+func (op lambdaOp) Derived(a *Apl, LO, RO Value) Function {
+	return &opFunction{op: op, lo: LO, ro: RO}
+}
+
+// opFunction is the function derived from a user defined operator and it's operands.
+type opFunction struct {
+	op     lambdaOp
+	lo, ro Value
+}
+
+func (f *opFunction) String(a *Apl) string {
+	return f.op.name
+}
+
+func (f *opFunction) Call(a *Apl, L, R Value) (Value, error) {
+	v, _, err := f.callShy(a, L, R)
+	return v, err
+}
+
+func (f *opFunction) callShy(a *Apl, L, R Value) (Value, bool, error) {
+	λ, err := f.op.body(a, f.lo, f.ro)
+	if err != nil {
+		return nil, false, err
+	}
+	return λ.call(a, f.lo, f.ro, L, R)
+}
+
+// body returns the lambda expression for the operands.
+// If an operand is an array, the lambda is parsed again with the operand name as an array.
+func (op lambdaOp) body(a *Apl, LO, RO Value) (*lambda, error) {
+	var names []string
+	if _, ok := LO.(Function); ok == false {
+		names = append(names, "⍺⍺")
+	}
+	if _, ok := RO.(Function); op.dyadic && ok == false {
+		names = append(names, "⍵⍵")
+	}
+	if names == nil || op.λ.tokens == nil {
+		return op.λ, nil
+	}
+	key := strings.Join(names, "")
+	if λ, ok := op.parsed.Load(key); ok {
+		return λ.(*lambda), nil
+	}
+	nouns := make(map[string]bool)
+	for _, s := range names {
+		nouns[s] = true
+	}
+	p := &parser{a: a, tokens: append([]scan.Token(nil), op.λ.tokens...), nouns: nouns}
+	it, err := p.parseLambda()
+	if err != nil {
+		return nil, syntaxError(fmt.Errorf("operator %s with array operands: %w", op.name, err))
+	}
+	λ := it.e.(*lambda)
+	op.parsed.Store(key, λ)
+	return λ, nil
+}
+
+func (op lambdaOp) Select(a *Apl, L, LO, RO, R Value) (IntArray, error) {
+	return IntArray{}, fmt.Errorf("user defined operator %s cannot be used in selective assignment", op.name)
+}
+
+func (op lambdaOp) Doc() string { return "user defined operator " + op.name }
@@ -16,7 +16,7 @@ func (l List) String(a *Apl) string {
 	var buf strings.Builder
 	buf.WriteRune('(')
 	for i := range l {
-		buf.WriteString(l[i].String(a))
+		buf.WriteString(dataString(a, l[i]))
 		buf.WriteRune(';')
 	}
 	buf.WriteRune(')')
@@ -35,6 +35,15 @@ func (l List) Size() int {
 	return len(l)
 }
 
+// Copy returns a deep copy of the list.
+func (l List) Copy() Value {
+	r := make(List, len(l))
+	for i, v := range l {
+		r[i] = Copy(v)
+	}
+	return r
+}
+
 func (l List) GetDeep(idx []int) (Value, error) {
 	return l.getset(idx, nil)
 }
@@ -50,7 +59,7 @@ func (l List) getset(idx []int, v Value) (Value, error) {
 	}
 	for i, k := range idx {
 		if k < 0 || k >= len(l) {
-			return nil, fmt.Errorf("index out of range")
+			return nil, Errorf(IndexError, "index out of range")
 		}
 		if i == len(idx)-1 {
 			if v != nil {
@@ -57,6 +57,9 @@ func (c Complex) String(a *apl.Apl) string {
 		}
 		s = fmt.Sprintf(format, a, b)
 	}
+	if a.PP == -1 {
+		s = strings.Replace(s, "e+", "e", -1)
+	}
 	if minus == false {
 		s = strings.Replace(s, "-", "¯", -1)
 	}
@@ -129,6 +132,16 @@ func (c Complex) ToIndex() (int, bool) {
 	return 0, false
 }
 
+// TolerantEquals compares two Complex numbers with the comparison tolerance ct.
+// The magnitude of the difference is compared with the larger magnitude.
+func (c Complex) TolerantEquals(R apl.Value, ct float64) (apl.Bool, bool) {
+	l, r := complex128(c), complex128(R.(Complex))
+	if l == r {
+		return true, true
+	}
+	return apl.Bool(cmplx.Abs(l-r) <= ct*math.Max(cmplx.Abs(l), cmplx.Abs(r))), true
+}
+
 func (c Complex) Add() (apl.Value, bool) {
 	return Complex(cmplx.Conj(complex128(c))), true
 }
@@ -34,7 +34,7 @@ func (f ComplexArray) Zero() apl.Value {
 
 func (f ComplexArray) Set(i int, v apl.Value) error {
 	if i < 0 || i > len(f.Cmplx) {
-		return fmt.Errorf("index out of range")
+		return apl.Errorf(apl.IndexError, "index out of range")
 	}
 	if c, ok := v.(Complex); ok {
 		f.Cmplx[i] = complex128(c)
@@ -50,6 +50,27 @@ func (f ComplexArray) Make(shape []int) apl.Array {
 	}
 }
 
+func (f ComplexArray) Copy() apl.Value {
+	r := ComplexArray{Dims: apl.CopyShape(f), Cmplx: make([]complex128, len(f.Cmplx))}
+	copy(r.Cmplx, f.Cmplx)
+	return r
+}
+
+func (f ComplexArray) Append(v []apl.Value) (apl.Array, bool) {
+	if len(f.Dims) != 1 {
+		return f, false
+	}
+	cmplx := f.Cmplx
+	for _, e := range v {
+		c, ok := e.(Complex)
+		if ok == false {
+			return f, false
+		}
+		cmplx = append(cmplx, complex128(c))
+	}
+	return ComplexArray{Dims: []int{len(cmplx)}, Cmplx: cmplx}, true
+}
+
 func makeComplexArray(v []apl.Value) ComplexArray {
 	f := make([]complex128, len(v))
 	for i, e := range v {
@@ -12,10 +12,18 @@ import (
 
 type Float float64
 
+// NaN is the null value of a Float, written as 0n.
+func (n Float) IsNull() bool    { return math.IsNaN(float64(n)) }
+func (n Float) Null() apl.Value { return Float(math.NaN()) }
+
 // String formats a Float as a string.
 // The format string is passed to fmt and - is replaced by ¯,
 // except if the first rune is -.
+// For PP=-1 the exponent is written without a plus sign: 1e21.
 func (n Float) String(a *apl.Apl) string {
+	if n.IsNull() {
+		return apl.NullString(a, "0n")
+	}
 	format, minus := getformat(a, n)
 	if format == "" {
 		switch prec := a.PP; {
@@ -30,6 +38,9 @@ func (n Float) String(a *apl.Apl) string {
 		}
 	}
 	s := fmt.Sprintf(format, float64(n))
+	if a.PP == -1 {
+		s = strings.Replace(s, "e+", "e", 1)
+	}
 	if minus == false {
 		s = strings.Replace(s, "-", "¯", -1)
 	}
@@ -38,7 +49,11 @@ func (n Float) String(a *apl.Apl) string {
 
 // ParseFloat parses a Float. It replaces ¯ with -, then uses ParseFloat.
 // A trailing . is stripped, so that "2." is parsed as a float.
+// 0n is parsed as the null value NaN.
 func ParseFloat(s string) (apl.Number, bool) {
+	if s == "0n" {
+		return Float(math.NaN()), true
+	}
 	s = strings.Replace(s, "¯", "-", -1)
 	if n, err := strconv.ParseFloat(strings.TrimSuffix(s, "."), 64); err == nil {
 		return Float(n), true
@@ -63,6 +78,21 @@ func (f Float) Less(R apl.Value) (apl.Bool, bool) {
 	return apl.Bool(f < R.(Float)), true
 }
 
+// ToFloat converts a Float to a float64.
+func (f Float) ToFloat() (float64, bool) {
+	return float64(f), true
+}
+
+// TolerantEquals compares two Floats with the comparison tolerance ct:
+//	|L-R| ≤ ct × (|L|⌈|R|)
+func (f Float) TolerantEquals(R apl.Value, ct float64) (apl.Bool, bool) {
+	l, r := float64(f), float64(R.(Float))
+	if l == r {
+		return true, true
+	}
+	return apl.Bool(math.Abs(l-r) <= ct*math.Max(math.Abs(l), math.Abs(r))), true
+}
+
 func (f Float) Add() (apl.Value, bool) {
 	return f, true
 }
@@ -34,7 +34,7 @@ func (f FloatArray) Zero() apl.Value {
 
 func (f FloatArray) Set(i int, v apl.Value) error {
 	if i < 0 || i > len(f.Floats) {
-		return fmt.Errorf("index out of range")
+		return apl.Errorf(apl.IndexError, "index out of range")
 	}
 	if c, ok := v.(Float); ok {
 		f.Floats[i] = float64(c)
@@ -50,6 +50,27 @@ func (f FloatArray) Make(shape []int) apl.Array {
 	}
 }
 
+func (f FloatArray) Copy() apl.Value {
+	r := FloatArray{Dims: apl.CopyShape(f), Floats: make([]float64, len(f.Floats))}
+	copy(r.Floats, f.Floats)
+	return r
+}
+
+func (f FloatArray) Append(v []apl.Value) (apl.Array, bool) {
+	if len(f.Dims) != 1 {
+		return f, false
+	}
+	floats := f.Floats
+	for _, e := range v {
+		c, ok := e.(Float)
+		if ok == false {
+			return f, false
+		}
+		floats = append(floats, float64(c))
+	}
+	return FloatArray{Dims: []int{len(floats)}, Floats: floats}, true
+}
+
 func makeFloatArray(v []apl.Value) FloatArray {
 	f := make([]float64, len(v))
 	for i, e := range v {
@@ -25,6 +25,8 @@ func TestParse(t *testing.T) {
 		{"¯.3", Float(-0.3)},
 		{"2014.04.02", Time(time.Date(2014, 4, 2, 0, 0, 0, 0, time.UTC))},
 		{"2014.04.02T09.37.22", Time(time.Date(2014, 4, 2, 9, 37, 22, 0, time.UTC))},
+		{"2014-04-02", Time(time.Date(2014, 4, 2, 0, 0, 0, 0, time.UTC))},
+		{"2014-04-02T09:37:22.5", Time(time.Date(2014, 4, 2, 9, 37, 22, 5e8, time.UTC))},
 		{"10s", Time(y0.Add(10 * time.Second))},
 	}
 
@@ -3,6 +3,8 @@
 package numbers
 
 import (
+	"math"
+	"math/cmplx"
 	"reflect"
 	"time"
 
@@ -28,6 +30,10 @@ func newTower() apl.Tower {
 		Parse: ParseComplex,
 		Uptype: func(n apl.Number) (apl.Number, bool) {
 			// Uptype converts a number to seconds, if the imag part is 0
+			// A NaN is converted to the null time.
+			if cmplx.IsNaN(complex128(n.(Complex))) {
+				return Time(nullTime), true
+			}
 			if imag(complex128(n.(Complex))) != 0 {
 				return nil, false
 			}
@@ -49,6 +55,9 @@ func newTower() apl.Tower {
 				}
 				return Float(0)
 			} else if n, ok := n.(apl.Int); ok {
+				if n.IsNull() {
+					return Float(math.NaN())
+				}
 				return Float(n)
 			}
 			return n
@@ -81,10 +90,7 @@ func makeBoolArray(v []apl.Value) apl.BoolArray {
 	for i, e := range v {
 		f[i] = bool(e.(apl.Bool))
 	}
-	return apl.BoolArray{
-		Dims:  []int{len(v)},
-		Bools: f,
-	}
+	return apl.NewBoolArray([]int{len(v)}, f)
 }
 func makeIndexArray(v []apl.Value) apl.IntArray {
 	f := make([]int, len(v))
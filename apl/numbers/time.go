@@ -1,6 +1,7 @@
 package numbers
 
 import (
+	"math"
 	"strings"
 	"time"
 
@@ -9,11 +10,18 @@ import (
 
 var y0, y1k time.Time
 
+// nullTime is the null value of a Time, written as 0Nt.
+// It is the smallest time stamp that can be represented by int64 nanoseconds since 1970.
+var nullTime = time.Unix(0, math.MinInt64).UTC()
+
 func init() {
 	y1k, _ = time.Parse("2006.01.02", "1000.01.01")
 	y0, _ = time.Parse("15h04", "00h00")
 }
 
+func (t Time) IsNull() bool    { return time.Time(t).Equal(nullTime) }
+func (t Time) Null() apl.Value { return Time(nullTime) }
+
 // Time holds both a time stamp and a duration in a single number type.
 // Durations are identified as time stamps before year 1000 (y1k).
 // The parser accepts both, durations and time stamps.
@@ -22,6 +30,9 @@ func init() {
 type Time time.Time
 
 func ParseTime(s string) (apl.Number, bool) {
+	if s == "0Nt" {
+		return Time(nullTime), true
+	}
 	s = strings.Replace(s, "¯", "-", -1)
 	for _, layout := range layouts {
 		if t, err := time.Parse(layout, s); err == nil {
@@ -37,21 +48,39 @@ func ParseTime(s string) (apl.Number, bool) {
 
 // We cannot separate with colons.
 // Mon Jan 2 15:04:05 -0700 MST 2006
+// The ISO formats with dashes and colons cannot be scanned in APL expressions,
+// but they are accepted by data parsers, e.g. for csv files.
 var layouts = []string{
 	"2006.01.02",
 	"2006.01.02T15.04",
 	"2006.01.02T15.04",
 	"2006.01.02T15.04.05", // This accepts also fractional seconds.
+	"2006-01-02",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
 }
 
+// String formats a time stamp with millisecond precision, or a duration.
+// For PP=-1, time stamps are written with nanoseconds and durations
+// with ¯ and u instead of µ, so that they can be parsed again.
 func (t Time) String(a *apl.Apl) string {
+	if t.IsNull() {
+		return apl.NullString(a, "0Nt")
+	}
 	if t1 := time.Time(t); t1.Before(y1k) {
-		return t1.Sub(y0).String()
+		s := t1.Sub(y0).String()
+		if a.PP == -1 {
+			s = strings.Replace(strings.Replace(s, "-", "¯", 1), "µ", "u", 1)
+		}
+		return s
 	}
 
 	format, _ := getformat(a, t)
 	if format == "" {
 		format = "2006.01.02T15.04.05.000"
+		if a.PP == -1 {
+			format = "2006.01.02T15.04.05.000000000"
+		}
 	}
 	return time.Time(t).Format(format)
 }
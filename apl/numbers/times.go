@@ -35,7 +35,7 @@ func (t TimeArray) Zero() apl.Value {
 
 func (t TimeArray) Set(i int, v apl.Value) error {
 	if i < 0 || i > len(t.Times) {
-		return fmt.Errorf("index out of range")
+		return apl.Errorf(apl.IndexError, "index out of range")
 	}
 	if c, ok := v.(Time); ok {
 		t.Times[i] = time.Time(c)
@@ -67,6 +67,27 @@ func (t TimeArray) Make(shape []int) apl.Array {
 	}
 }
 
+func (t TimeArray) Copy() apl.Value {
+	r := TimeArray{Dims: apl.CopyShape(t), Times: make([]time.Time, len(t.Times))}
+	copy(r.Times, t.Times)
+	return r
+}
+
+func (t TimeArray) Append(v []apl.Value) (apl.Array, bool) {
+	if len(t.Dims) != 1 {
+		return t, false
+	}
+	times := t.Times
+	for _, e := range v {
+		c, ok := e.(Time)
+		if ok == false {
+			return t, false
+		}
+		times = append(times, time.Time(c))
+	}
+	return TimeArray{Dims: []int{len(times)}, Times: times}, true
+}
+
 func makeTimeArray(v []apl.Value) TimeArray {
 	t := make([]time.Time, len(v))
 	for i, e := range v {
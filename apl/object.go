@@ -60,6 +60,20 @@ func (d *Dict) At(a *Apl, key Value) Value {
 	return d.M[key]
 }
 
+// Copy returns a deep copy of the dict.
+// Keys are scalars and are not copied.
+func (d *Dict) Copy() Value {
+	r := Dict{K: make([]Value, len(d.K))}
+	copy(r.K, d.K)
+	if d.M != nil {
+		r.M = make(map[Value]Value, len(d.M))
+		for k, v := range d.M {
+			r.M[k] = Copy(v)
+		}
+	}
+	return &r
+}
+
 // Set updates the value for the given key, or creates a new one,
 // if the key does not exist.
 // Keys must be valid variable names.
@@ -83,7 +97,7 @@ func (d *Dict) String(a *Apl) string {
 	var buf strings.Builder
 	tw := tabwriter.NewWriter(&buf, 1, 0, 1, ' ', 0)
 	for _, k := range d.K {
-		fmt.Fprintf(tw, "%s:\t%s\n", k.String(a), d.M[k].String(a))
+		fmt.Fprintf(tw, "%s:\t%s\n", k.String(a), dataString(a, d.M[k]))
 	}
 	tw.Flush()
 	s := buf.String()
@@ -131,12 +145,54 @@ func (d *Dict) matString(a *Apl) string {
 	return b.String()
 }
 
+// ParseDict parses a dict in the data format, that is written by ¯1⍕D.
+// Each line contains a key, followed by a colon and the value.
+// If the prototype is a dict, the result must have the same keys.
 func (a *Apl) ParseDict(prototype Value, s string) (*Dict, error) {
+	var pd *Dict
 	if prototype != nil {
-		_, ok := prototype.(*Dict)
+		d, ok := prototype.(*Dict)
 		if ok == false {
 			return nil, fmt.Errorf("ParseDict: prototype is not a dict: %T", prototype)
 		}
+		pd = d
+	}
+
+	d := Dict{M: make(map[Value]Value)}
+	p := newDataParser(a, s)
+	for p.emptyLines() {
+		k, err := p.item()
+		if err != nil {
+			return nil, fmt.Errorf("parse dict: %s", err)
+		} else if _, ok := k.(Array); ok {
+			return nil, fmt.Errorf("parse dict: key must be a scalar: %s", k.String(a))
+		} else if _, ok := d.M[k]; ok {
+			return nil, fmt.Errorf("parse dict: duplicate key: %s", k.String(a))
+		}
+		p.blanks()
+		if r, _, err := p.r.ReadRune(); err != nil || r != ':' {
+			return nil, fmt.Errorf("parse dict: expected : after key %s", k.String(a))
+		}
+		v, err := p.value("")
+		if err != nil {
+			return nil, fmt.Errorf("parse dict: %s", err)
+		}
+		if err := p.line(); err != nil {
+			return nil, fmt.Errorf("parse dict: %s", err)
+		}
+		d.K = append(d.K, k)
+		d.M[k] = v
+	}
+
+	if pd != nil {
+		if len(pd.K) != len(d.K) {
+			return nil, fmt.Errorf("parse dict: dict has %d keys, prototype has %d", len(d.K), len(pd.K))
+		}
+		for _, k := range pd.K {
+			if _, ok := d.M[k]; ok == false {
+				return nil, fmt.Errorf("parse dict: key is missing: %s", k.String(a))
+			}
+		}
 	}
-	return nil, fmt.Errorf("TODO ParseDict")
+	return &d, nil
 }
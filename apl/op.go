@@ -43,7 +43,7 @@ func (d *derived) Eval(a *Apl) (Value, error) {
 }
 
 func (d *derived) String(a *Apl) string {
-	ops, ok := a.operators[d.op]
+	ops, ok := a.lookupOperator(d.op)
 	if ok == false {
 		return "<unknown operator>"
 	}
@@ -68,9 +68,15 @@ func (d *derived) String(a *Apl) string {
 // registration order until a handler accepts to build a derived function, which
 // is then called with l and r.
 func (d *derived) Call(a *Apl, l, r Value) (Value, error) {
-	ops, ok := a.operators[d.op]
+	v, _, err := d.callShy(a, l, r)
+	return v, err
+}
+
+// callShy calls the derived function and reports if the result is shy.
+func (d *derived) callShy(a *Apl, l, r Value) (Value, bool, error) {
+	ops, ok := a.lookupOperator(d.op)
 	if ok == false || len(ops) == 0 || ops[0] == nil {
-		return nil, fmt.Errorf("operator %s does not exist", d.op)
+		return nil, false, Errorf(ValueError, "operator %s does not exist", d.op)
 	}
 
 	// Evaluate the operands.
@@ -79,7 +85,7 @@ func (d *derived) Call(a *Apl, l, r Value) (Value, error) {
 	if ops[0].DyadicOp() { // All registerd operators have the same arity.
 		ro, err = d.ro.Eval(a)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 	}
 
@@ -90,38 +96,39 @@ func (d *derived) Call(a *Apl, l, r Value) (Value, error) {
 		if l == nil {
 			lo, err = evalAssign(a, d.lo, nil)
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
 		} else {
 			as, ok := l.(assignment)
 			if ok == false {
-				return nil, fmt.Errorf("modified assignment: expected assignment target expr on the left: %T", l)
+				return nil, false, fmt.Errorf("modified assignment: expected assignment target expr on the left: %T", l)
 			}
 			lo, err = evalAssign(a, as, d.lo)
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
 			l = nil
 		}
 	} else {
 		lo, err = d.lo.Eval(a)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 	}
 
 	for _, op := range ops {
 		if LO, RO, ok := op.To(a, lo, ro); ok {
-			return op.Derived(a, LO, RO).Call(a, l, r)
+			// Only the results of user defined operators may be shy.
+			return callShy(a, op.Derived(a, LO, RO), l, r)
 		}
 	}
-	return nil, fmt.Errorf("cannot handle operator %T %s %T", lo, d.op, ro)
+	return nil, false, fmt.Errorf("cannot handle operator %T %s %T", lo, d.op, ro)
 }
 
 func (d *derived) Select(a *Apl, L, R Value) (Value, error) {
-	ops, ok := a.operators[d.op]
+	ops, ok := a.lookupOperator(d.op)
 	if ok == false || len(ops) == 0 || ops[0] == nil {
-		return nil, fmt.Errorf("operator %s does not exist", d.op)
+		return nil, Errorf(ValueError, "operator %s does not exist", d.op)
 	}
 
 	if ops[0].DyadicOp() && d.op != "⍂" {
@@ -33,6 +33,10 @@ func assign(a *apl.Apl, f, g apl.Value) apl.Function {
 			return assignVector(a, as.Identifiers, R, as.Modifier)
 		}
 
+		if as.Path != nil {
+			return R, assignReach(a, as.Identifier, as.Path, as.Modifier, R)
+		}
+
 		// Special case: channel scope: ⎕←C
 		if c, ok := R.(apl.Channel); ok && as.Identifier == "⎕" {
 			return c.Scope(a), nil
@@ -55,9 +59,9 @@ func assignVector(a *apl.Apl, names []string, R apl.Value, mod apl.Value) (apl.V
 
 	var scalar apl.Value
 	if s := ar.Shape(); len(s) != 1 {
-		return nil, fmt.Errorf("vector assignment: rank of right argument must be 1")
+		return nil, apl.Errorf(apl.RankError, "vector assignment: rank of right argument must be 1")
 	} else if s[0] != 1 && s[0] != len(names) {
-		return nil, fmt.Errorf("vector assignment is non-conformant")
+		return nil, apl.Errorf(apl.LengthError, "vector assignment is non-conformant")
 	} else if s[0] == 1 {
 		if ar.Size() < 1 {
 			return nil, fmt.Errorf("vector assignment: collapsed dimension")
@@ -93,7 +97,12 @@ func assignScalar(a *apl.Apl, name string, indexes apl.Value, mod apl.Value, R a
 		return a.Assign(name, R)
 	}
 
-	w, env := a.LookupEnv(name)
+	// Only indexed assignment modifies the value in place.
+	lookup := a.LookupEnv
+	if indexes != nil {
+		lookup = a.LookupOwned
+	}
+	w, env := lookup(name)
 	if w == nil {
 		return fmt.Errorf("modified/indexed assignment to non-existing variable %s", name)
 	}
@@ -108,6 +117,7 @@ func assignScalar(a *apl.Apl, name string, indexes apl.Value, mod apl.Value, R a
 	}
 
 	// Modified assignment without indexing.
+	// The result may share memory with R or other values.
 	if indexes == nil {
 		if v, err := f.Call(a, w, R); err != nil {
 			return err
@@ -116,6 +126,8 @@ func assignScalar(a *apl.Apl, name string, indexes apl.Value, mod apl.Value, R a
 		}
 	}
 
+	// R is stored inside the variable's value, which owns it, see apl.Copier.
+	R = apl.Copy(R)
 	idx, ok := indexes.(apl.IntArray)
 	if ok == false {
 		to := ToIndexArray(nil)
@@ -128,6 +140,14 @@ func assignScalar(a *apl.Apl, name string, indexes apl.Value, mod apl.Value, R a
 		}
 	}
 
+	if t, ok := w.(apl.Table); ok {
+		if t, err := assignTable(a, t, idx, f, R); err != nil {
+			return err
+		} else {
+			return a.AssignOwned(name, t, env)
+		}
+	}
+
 	if obj, ok := w.(apl.Object); ok {
 		return assignObject(a, obj, idx, f, R)
 	}
@@ -202,36 +222,8 @@ func assignScalar(a *apl.Apl, name string, indexes apl.Value, mod apl.Value, R a
 		}
 	} else {
 
-		// Shapes must conform. Single element axis are collapsed.
-		collapse := func(s []int) []int {
-			n := 0
-			for _, i := range s {
-				if i == 1 {
-					n++
-				}
-			}
-			if n == 0 {
-				return s
-			}
-			r := make([]int, len(s)-n)
-			k := 0
-			for _, i := range s {
-				if i != 1 {
-					r[k] = i
-					k++
-				}
-			}
-			return r
-		}
-		ds := collapse(idx.Shape())
-		ss := collapse(src.Shape())
-		if len(ds) != len(ss) {
-			return fmt.Errorf("indexed assignment: arrays have different rank: %d != %d", len(ds), len(ss))
-		}
-		for i := range ds {
-			if ss[i] != ds[i] {
-				return fmt.Errorf("indexed assignment: arrays are not conforming: %v != %v", ss, ds)
-			}
+		if err := conform(idx.Shape(), src.Shape()); err != nil {
+			return err
 		}
 		for i, d := range idx.Ints {
 			if err := apl.ArrayBounds(src, i); err != nil {
@@ -242,14 +234,83 @@ func assignScalar(a *apl.Apl, name string, indexes apl.Value, mod apl.Value, R a
 			}
 		}
 	}
-	return a.AssignEnv(name, ar, env)
+	return a.AssignOwned(name, ar, env)
+}
+
+// conform tests if the shape of the source array conforms to the destination.
+// Single element axis are collapsed.
+func conform(dst, src []int) error {
+	collapse := func(s []int) []int {
+		n := 0
+		for _, i := range s {
+			if i == 1 {
+				n++
+			}
+		}
+		if n == 0 {
+			return s
+		}
+		r := make([]int, len(s)-n)
+		k := 0
+		for _, i := range s {
+			if i != 1 {
+				r[k] = i
+				k++
+			}
+		}
+		return r
+	}
+	ds := collapse(dst)
+	ss := collapse(src)
+	if len(ds) != len(ss) {
+		return apl.Errorf(apl.RankError, "indexed assignment: arrays have different rank: %d != %d", len(ds), len(ss))
+	}
+	for i := range ds {
+		if ss[i] != ds[i] {
+			return apl.Errorf(apl.LengthError, "indexed assignment: arrays are not conforming: %v != %v", ss, ds)
+		}
+	}
+	return nil
+}
+
+// assignReach does a reach indexed assignment: (Path⊃X)←R.
+// The path may lead through Lists, Objects, Tables and Arrays.
+// Mod may be a dyadic modifying function.
+func assignReach(a *apl.Apl, name string, path apl.Value, mod apl.Value, R apl.Value) error {
+	w, env := a.LookupOwned(name)
+	if w == nil {
+		return fmt.Errorf("reach assignment to non-existing variable %s", name)
+	}
+	R = apl.Copy(R)
+
+	var f apl.Function
+	if mod != nil {
+		if fn, ok := mod.(apl.Function); ok == false {
+			return fmt.Errorf("modified assignment needs a function: %T", mod)
+		} else {
+			f = fn
+		}
+	}
+
+	v, err := a.Reach(path, w, func(x apl.Value) (apl.Value, error) {
+		if f == nil {
+			return R, nil
+		} else if x == nil {
+			return nil, apl.Errorf(apl.IndexError, "modified reach assignment: value does not exist")
+		}
+		return f.Call(a, x, R)
+	})
+	if err != nil {
+		return err
+	}
+	return a.AssignOwned(name, v, env)
 }
 
 // assignObject assigns R to index keys of a object.
+// For modified assignment, f is called with the current value of the key and R.
+// A key that is repeated in the index is modified repeatedly, e.g. D[`a`a]+←1 adds 2.
+// The object's Set method is called once for each key.
 func assignObject(a *apl.Apl, obj apl.Object, idx apl.IntArray, f apl.Function, R apl.Value) error {
-	if f != nil {
-		return fmt.Errorf("TODO: object: modified indexed assignment")
-	}
 	vectorize := false
 	ar, ok := R.(apl.Array)
 	if ok == true {
@@ -257,31 +318,211 @@ func assignObject(a *apl.Apl, obj apl.Object, idx apl.IntArray, f apl.Function,
 			if len(idx.Ints) == ar.Size() {
 				vectorize = true
 			} else {
-				return fmt.Errorf("assing object: assignment does not conform")
+				return apl.Errorf(apl.LengthError, "assing object: assignment does not conform")
 			}
 		}
 	}
 	keys := obj.Keys()
+	var order []apl.Value
+	values := make(map[apl.Value]apl.Value)
 	for i := 0; i < len(idx.Ints); i++ {
 		n := int(idx.Ints[i] - a.Origin)
 		if n < 0 || n >= len(keys) {
-			return fmt.Errorf("assign object: index out of range")
+			return apl.Errorf(apl.IndexError, "assign object: index out of range")
 		}
 		k := keys[n]
-		v := R // TODO: copy?
+		v := R
 		if vectorize == true {
 			if err := apl.ArrayBounds(ar, i); err != nil {
 				return err
 			}
 			v = ar.At(i)
 		}
-		if err := obj.Set(a, k, v); err != nil {
+		x, ok := values[k]
+		if ok == false {
+			order = append(order, k)
+		}
+		if f != nil {
+			if ok == false {
+				if x = obj.At(a, k); x == nil {
+					return fmt.Errorf("assign object: key %s has no value", k.String(a))
+				}
+			}
+			if y, err := f.Call(a, x, v); err != nil {
+				return fmt.Errorf("assign object: key %s: %w", k.String(a), err)
+			} else {
+				v = y
+			}
+		}
+		values[k] = v
+	}
+	for _, k := range order {
+		if err := obj.Set(a, k, values[k]); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// assignTable assigns R to the rows and columns of a table, that are selected by idx.
+// The index array has the shape rows × columns, see the table selection function.
+// R may be:
+//	a Table with the selected number of rows and the selected columns
+//	a Dict with the selected columns as keys and scalars or row vectors as values
+//	a List with one scalar or row vector for each selected column
+//	an array that conforms to the selection, or a scalar.
+// Assigning ⍬ to complete rows deletes them:
+//	T[1 3]←⍬
+// Columns keep a uniform type, if possible.
+// The table is returned, as the number of rows may change.
+func assignTable(a *apl.Apl, t apl.Table, idx apl.IntArray, f apl.Function, R apl.Value) (apl.Table, error) {
+	if len(idx.Dims) != 2 {
+		return t, apl.Errorf(apl.RankError, "table assignment: index must have rank 2")
+	}
+	nrows, ncols := idx.Dims[0], idx.Dims[1]
+	n := len(t.K)
+
+	if _, ok := R.(apl.EmptyArray); ok && f == nil {
+		if ncols != n {
+			return t, fmt.Errorf("table assignment: only complete rows can be deleted")
+		}
+		return deleteRows(t, idx), nil
+	}
+
+	// item returns row i of a column value v, which is a scalar or a vector.
+	item := func(v apl.Value, i int) (apl.Value, error) {
+		ar, ok := v.(apl.Array)
+		if ok == false {
+			return v, nil
+		} else if ar.Size() == 1 {
+			return ar.At(0), nil
+		} else if s := ar.Shape(); len(s) != 1 || s[0] != nrows {
+			return nil, fmt.Errorf("table assignment: value must be a scalar or a vector with %d rows", nrows)
+		}
+		return ar.At(i), nil
+	}
+
+	// value returns the value for the selected row i and column j.
+	var value func(i, j int) (apl.Value, error)
+	switch r := R.(type) {
+	case apl.Table:
+		if r.Rows != nrows {
+			return t, fmt.Errorf("table assignment: table has %d rows, selection has %d", r.Rows, nrows)
+		}
+		value = func(i, j int) (apl.Value, error) {
+			k := t.K[idx.Ints[j]%n]
+			col, ok := r.At(a, k).(apl.Array)
+			if ok == false {
+				return nil, fmt.Errorf("table assignment: column %s is missing", k.String(a))
+			}
+			return col.At(i), nil
+		}
+	case apl.Object:
+		value = func(i, j int) (apl.Value, error) {
+			k := t.K[idx.Ints[j]%n]
+			v := r.At(a, k)
+			if v == nil {
+				return nil, fmt.Errorf("table assignment: key %s is missing", k.String(a))
+			}
+			return item(v, i)
+		}
+	case apl.List:
+		if len(r) != ncols {
+			return t, fmt.Errorf("table assignment: list has %d values for %d columns", len(r), ncols)
+		}
+		value = func(i, j int) (apl.Value, error) {
+			return item(r[j], i)
+		}
+	case apl.Array:
+		if r.Size() == 1 {
+			v := r.At(0)
+			value = func(i, j int) (apl.Value, error) { return v, nil }
+		} else if err := conform(idx.Dims, r.Shape()); err != nil {
+			return t, err
+		} else {
+			value = func(i, j int) (apl.Value, error) { return r.At(i*ncols + j), nil }
+		}
+	default:
+		value = func(i, j int) (apl.Value, error) { return R, nil }
+	}
+
+	touched := make(map[apl.Value]bool)
+	for i := 0; i < nrows; i++ {
+		for j := 0; j < ncols; j++ {
+			x := idx.Ints[i*ncols+j]
+			row, k := x/n, t.K[x%n]
+			v, err := value(i, j)
+			if err != nil {
+				return t, err
+			}
+			col, ok := t.M[k].(apl.ArraySetter)
+			if ok == false {
+				return t, fmt.Errorf("table assignment: column %s is not settable: %T", k.String(a), t.M[k])
+			}
+			if f != nil {
+				if v, err = f.Call(a, col.At(row), v); err != nil {
+					return t, err
+				}
+			}
+			if err := col.Set(row, v); err != nil {
+				// Upgrade to a mixed array.
+				m := apl.MixedArray{Dims: []int{col.Size()}, Values: make([]apl.Value, col.Size())}
+				for c := range m.Values {
+					m.Values[c] = col.At(c)
+				}
+				m.Values[row] = v
+				t.M[k] = m
+			}
+			touched[k] = true
+		}
+	}
+
+	// Mixed columns are converted back to a uniform type, if possible.
+	for k := range touched {
+		if m, ok := t.M[k].(apl.MixedArray); ok {
+			if u, ok := a.Unify(m, true); ok {
+				t.M[k] = u
+			}
+		}
+	}
+
+	// Modified sort columns may not be sorted any more.
+	for _, k := range t.Sorted {
+		if touched[k] {
+			t.Sorted = nil
+			break
+		}
+	}
+	return t, nil
+}
+
+// deleteRows removes the rows that are selected by idx from the table.
+func deleteRows(t apl.Table, idx apl.IntArray) apl.Table {
+	n := len(t.K)
+	del := make(map[int]bool)
+	for _, x := range idx.Ints {
+		del[x/n] = true
+	}
+	rows := t.Rows - len(del)
+	for _, k := range t.K {
+		col, ok := t.M[k].(apl.Array)
+		if ok == false {
+			continue
+		}
+		res := apl.MakeArray(col, []int{rows})
+		i := 0
+		for row := 0; row < t.Rows; row++ {
+			if del[row] == false {
+				res.Set(i, col.At(row))
+				i++
+			}
+		}
+		t.M[k] = res
+	}
+	t.Rows = rows
+	return t
+}
+
 // assignList assigns R to the depth index of a list.
 func assignList(a *apl.Apl, l apl.List, idx apl.IntArray, f apl.Function, R apl.Value) error {
 	if f != nil {
@@ -43,7 +43,7 @@ func at(a *apl.Apl, f, g apl.Value) apl.Function {
 			}
 			for i := range mask {
 				if err := apl.ArrayBounds(av, i); err != nil {
-					return nil, fmt.Errorf("at: %s", err)
+					return nil, fmt.Errorf("at: %w", err)
 				}
 				v := av.At(i)
 				if n, ok := v.(apl.Number); ok == false {
@@ -67,13 +67,13 @@ func at(a *apl.Apl, f, g apl.Value) apl.Function {
 				gi = v.(apl.IntArray)
 			}
 			if len(gi.Dims) != 1 {
-				return nil, fmt.Errorf("at: g should have rank 1: %d", len(gi.Dims))
+				return nil, apl.Errorf(apl.RankError, "at: g should have rank 1: %d", len(gi.Dims))
 			}
 			n := apl.ArraySize(ar) / rs[0]
 			for _, major := range gi.Ints {
 				major -= a.Origin
 				if major < 0 || major >= rs[0] {
-					return nil, fmt.Errorf("at: selected major cell is out of range %d: [1, %d]", major+1, rs[0])
+					return nil, apl.Errorf(apl.IndexError, "at: selected major cell is out of range %d: [1, %d]", major+1, rs[0])
 				}
 				off := n * int(major)
 				for i := 0; i < n; i++ {
@@ -131,7 +131,7 @@ func at(a *apl.Apl, f, g apl.Value) apl.Function {
 		}
 		if n := apl.ArraySize(re); n == 1 {
 			for i := range repl {
-				repl[i] = re.At(0) // TODO: copy?
+				repl[i] = re.At(0)
 			}
 		} else if n != len(repl) {
 			return nil, fmt.Errorf("at: number of replacements does not match selection")
@@ -22,5 +22,15 @@ func commute(a *apl.Apl, f, _ apl.Value) apl.Function {
 		}
 		return f.Call(a, R, L)
 	}
-	return function(derived)
+	inverse := func(a *apl.Apl) (apl.Function, error) {
+		return commuteInverse(a, f.(apl.Function))
+	}
+	return invertible{function(derived), inverse}
+}
+
+// commuteInverse returns the inverse of f⍨.
+// Called dyadically, it solves for the left argument of f: (X f L) ≡ R.
+// The inverse is registered for f and the operator ⍨, see apl.RegisterInverse.
+func commuteInverse(a *apl.Apl, f apl.Function) (apl.Function, error) {
+	return a.RegisteredInverse(f, "⍨")
 }
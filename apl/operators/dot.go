@@ -65,9 +65,8 @@ func inner(a *apl.Apl, l, r apl.Value, f, g apl.Function) (apl.Value, error) {
 	// If one is a scalar, convert it to a vector.
 	if lok == false {
 		rs := ar.Shape()
-		if rs == nil || rs[0] == 0 {
-			// TODO fill function?
-			return nil, fmt.Errorf("inner: empty rhs array")
+		if rs == nil {
+			rs = []int{0}
 		}
 		u := apl.MixedArray{Dims: []int{rs[0]}}
 		v := make([]apl.Value, rs[0])
@@ -78,11 +77,11 @@ func inner(a *apl.Apl, l, r apl.Value, f, g apl.Function) (apl.Value, error) {
 		al = u
 	} else if rok == false {
 		ls := al.Shape()
-		if ls == nil || ls[0] == 0 {
-			return nil, fmt.Errorf("inner: empty lhs array")
+		if ls == nil {
+			ls = []int{0}
 		}
 		u := apl.MixedArray{Dims: []int{ls[len(ls)-1]}}
-		v := make([]apl.Value, ls[0])
+		v := make([]apl.Value, ls[len(ls)-1])
 		for i := range v {
 			v[i] = r
 		}
@@ -93,6 +92,12 @@ func inner(a *apl.Apl, l, r apl.Value, f, g apl.Function) (apl.Value, error) {
 	// The result is a new array with a shape of both arrays combined, without the inner dimension.
 	ls := al.Shape()
 	rs := ar.Shape()
+	if _, ok := al.(apl.EmptyArray); ok {
+		ls = []int{0}
+	}
+	if _, ok := ar.(apl.EmptyArray); ok {
+		rs = []int{0}
+	}
 	if len(ls) == 0 || len(rs) == 0 {
 		return nil, fmt.Errorf("inner: empty array")
 	}
@@ -101,6 +106,18 @@ func inner(a *apl.Apl, l, r apl.Value, f, g apl.Function) (apl.Value, error) {
 		return nil, fmt.Errorf("inner dimensions must agree")
 	}
 
+	// If the inner dimension is empty, the result is the identity item of f.
+	if inner == 0 {
+		id := identityItem(f.(apl.Value))
+		if id == nil {
+			return nil, fmt.Errorf("inner: no identity item for empty inner dimension: %T", f)
+		}
+		shape := make([]int, len(ls)+len(rs)-2)
+		copy(shape, ls[:len(ls)-1])
+		copy(shape[len(ls)-1:], rs[1:])
+		return a.MakeFilled(id, shape), nil
+	}
+
 	// If both arrays are vectors, compute a scalar.
 	if len(ls) == 1 && len(rs) == 1 {
 		var v apl.Value
@@ -197,7 +214,7 @@ func outer(a *apl.Apl, L, R apl.Value, f, g apl.Function) (apl.Value, error) {
 		if err != nil {
 			return nil, err
 		}
-		res.Values[i] = v // TODO copy?
+		res.Values[i] = v
 
 		apl.IncArrayIndex(dst, shape)
 	}
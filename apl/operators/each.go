@@ -39,10 +39,7 @@ func each1(a *apl.Apl, R apl.Value, f apl.Function) (apl.Value, error) {
 	ar, ok := R.(apl.Array)
 	if ok {
 		if apl.ArraySize(ar) == 0 {
-			// TODO: Fill function of LO should be applied
-			// with the prototype of R.
-			// The result has the same shape as R.
-			return apl.EmptyArray{}, nil
+			return fill(a, f, nil, ar, emptyShape(ar)), nil
 		}
 	} else {
 		// Apply f to scalar R.
@@ -52,27 +49,32 @@ func each1(a *apl.Apl, R apl.Value, f apl.Function) (apl.Value, error) {
 	res := apl.MixedArray{Dims: apl.CopyShape(ar)}
 	res.Values = make([]apl.Value, apl.ArraySize(res))
 
-	for i := range res.Values {
+	err := a.ForEach(len(res.Values), func(a *apl.Apl, i int) error {
 		v, err := f.Call(a, nil, ar.At(i))
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if _, ok := v.(apl.Array); ok {
-			return nil, fmt.Errorf("each: result must be a scalar")
+			return fmt.Errorf("each: result must be a scalar")
 		}
 		res.Values[i] = v
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return res, nil
 }
 
 func eachList(a *apl.Apl, l apl.List, f apl.Function) (apl.Value, error) {
 	res := make(apl.List, len(l))
-	for i := range res {
+	err := a.ForEach(len(res), func(a *apl.Apl, i int) error {
 		v, err := f.Call(a, nil, l[i])
-		if err != nil {
-			return nil, err
-		}
 		res[i] = v
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 	return res, nil
 }
@@ -104,10 +106,10 @@ func each2(a *apl.Apl, L, R apl.Value, f apl.Function) (apl.Value, error) {
 		return f.Call(a, L, R)
 	}
 	if rok == true && apl.ArraySize(ar) == 0 {
-		return apl.EmptyArray{}, nil // TODO fill function
+		return fill(a, f, L, R, emptyShape(ar)), nil
 	}
 	if lok == true && apl.ArraySize(al) == 0 {
-		return apl.EmptyArray{}, nil // TODO fill function
+		return fill(a, f, L, R, emptyShape(al)), nil
 	}
 
 	if rok == true {
@@ -119,11 +121,11 @@ func each2(a *apl.Apl, L, R apl.Value, f apl.Function) (apl.Value, error) {
 
 	if rok == true && lok == true {
 		if len(ls) != len(rs) {
-			return nil, fmt.Errorf("each: ranks L and R are different")
+			return nil, apl.Errorf(apl.RankError, "each: ranks L and R are different")
 		}
 		for i := range ls {
 			if ls[i] != rs[i] {
-				return nil, fmt.Errorf("each: shapes of L and R must conform")
+				return nil, apl.Errorf(apl.LengthError, "each: shapes of L and R must conform")
 			}
 		}
 	}
@@ -142,7 +144,8 @@ func each2(a *apl.Apl, L, R apl.Value, f apl.Function) (apl.Value, error) {
 
 	res := apl.MixedArray{Dims: shape}
 	res.Values = make([]apl.Value, apl.ArraySize(res))
-	for i := range res.Values {
+	err := a.ForEach(len(res.Values), func(a *apl.Apl, i int) error {
+		lv, rv := lv, rv
 		if rok == true {
 			rv = ar.At(i)
 		}
@@ -151,12 +154,16 @@ func each2(a *apl.Apl, L, R apl.Value, f apl.Function) (apl.Value, error) {
 		}
 		v, err := f.Call(a, lv, rv)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if _, ok := v.(apl.Array); ok {
-			return nil, fmt.Errorf("each: result must be a scalar")
+			return fmt.Errorf("each: result must be a scalar")
 		}
 		res.Values[i] = v
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return res, nil
 }
@@ -175,7 +182,7 @@ func eachList2(a *apl.Apl, L, R apl.Value, f apl.Function) (apl.Value, error) {
 	}
 
 	res := make(apl.List, size)
-	for i := range res {
+	err := a.ForEach(size, func(a *apl.Apl, i int) error {
 		lv := L
 		rv := R
 		if lok {
@@ -185,10 +192,40 @@ func eachList2(a *apl.Apl, L, R apl.Value, f apl.Function) (apl.Value, error) {
 			rv = r[i]
 		}
 		v, err := f.Call(a, lv, rv)
-		if err != nil {
-			return nil, err
-		}
 		res[i] = v
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 	return res, nil
 }
+
+// fill applies the fill function of f to the empty argument.
+// The fill function is f called with the fill elements of L (may be nil) and R.
+// The result is an empty array of the given shape with the type of the result of f.
+// If f fails on the fill elements, the result has the type of R's fill element.
+func fill(a *apl.Apl, f apl.Function, L, R apl.Value, shape []int) apl.Value {
+	var l apl.Value
+	if L != nil {
+		l = a.Fill(L)
+	}
+	r := a.Fill(R)
+	v, err := f.Call(a, l, r)
+	if err != nil {
+		v = r
+	}
+	if _, ok := v.(apl.Array); ok {
+		v = a.Fill(v)
+	}
+	return a.MakeFilled(v, shape)
+}
+
+// emptyShape returns the shape of an empty array.
+// The EmptyArray is a vector of length 0.
+func emptyShape(ar apl.Array) []int {
+	if _, ok := ar.(apl.EmptyArray); ok {
+		return []int{0}
+	}
+	return apl.CopyShape(ar)
+}
@@ -47,5 +47,57 @@ func compose(a *apl.Apl, f, g apl.Value) apl.Function {
 		}
 		return nil, fmt.Errorf("compose: cannot handle %T %T ∘ %T %T", L, f, g, R)
 	}
-	return function(derived)
+	inverse := func(a *apl.Apl) (apl.Function, error) {
+		return composeInverse(a, f, g)
+	}
+	return invertible{function(derived), inverse}
+}
+
+// composeInverse returns the inverse of a composition.
+//	f∘g: X ←→ g⍣¯1 L f⍣¯1 R
+//	A∘g: X ←→ A g⍣¯1 R
+//	f∘B: X ←→ B f⍨⍣¯1 R
+func composeInverse(a *apl.Apl, f, g apl.Value) (apl.Function, error) {
+	fn, isfunc := f.(apl.Function)
+	gn, isgunc := g.(apl.Function)
+	if isfunc && isgunc {
+		fi, err := a.Inverse(fn)
+		if err != nil {
+			return nil, err
+		}
+		gi, err := a.Inverse(gn)
+		if err != nil {
+			return nil, err
+		}
+		return function(func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+			v, err := fi.Call(a, L, R)
+			if err != nil {
+				return nil, err
+			}
+			return gi.Call(a, nil, v)
+		}), nil
+	} else if isgunc {
+		gi, err := a.Inverse(gn)
+		if err != nil {
+			return nil, err
+		}
+		return function(func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+			if L != nil {
+				return nil, fmt.Errorf("compose: inverse of A∘g must be called monadically")
+			}
+			return gi.Call(a, f, R)
+		}), nil
+	} else if isfunc {
+		fi, err := commuteInverse(a, fn)
+		if err != nil {
+			return nil, err
+		}
+		return function(func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+			if L != nil {
+				return nil, fmt.Errorf("compose: inverse of f∘B must be called monadically")
+			}
+			return fi.Call(a, g, R)
+		}), nil
+	}
+	return nil, fmt.Errorf("compose: cannot invert %T ∘ %T", f, g)
 }
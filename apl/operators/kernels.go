@@ -0,0 +1,337 @@
+package operators
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/numbers"
+)
+
+// reduceKernel is the fast path for reductions with + × ⌊ ⌈ ∧ ∨ over a uniform numeric array.
+// It returns the same result as reduce, which is applied along the axis:
+// the values are reduced from right to left and null values are skipped.
+// If all values are null, + and × return their identity, ⌊ and ⌈ return null.
+// It returns false, if the function or the array type is not supported,
+// e.g. ∧/ on integers computes the least common multiple and uses the generic path,
+// or if an integer reduction overflows.
+// The length of the axis is at least 2.
+func reduceKernel(f apl.Value, ar apl.Array, axis int) (apl.Value, bool) {
+	p, ok := f.(apl.Primitive)
+	if ok == false {
+		return nil, false
+	}
+	symbol := string(p)
+	if symbol == "^" {
+		symbol = "∧"
+	}
+
+	// The array is viewed as outer×n×inner, the reduction is over n.
+	shape := ar.Shape()
+	outer, n, inner := 1, shape[axis], 1
+	dims := make([]int, 0, len(shape)-1)
+	for i, s := range shape {
+		if i < axis {
+			outer *= s
+		} else if i > axis {
+			inner *= s
+		}
+		if i != axis {
+			dims = append(dims, s)
+		}
+	}
+	if outer*inner == 0 {
+		return nil, false
+	}
+	l := layout{outer, n, inner}
+
+	switch x := ar.(type) {
+	case apl.BoolArray:
+		// Bools are reduced by counting the 1s.
+		var fn func(count int) int
+		switch symbol {
+		case "+":
+			fn = func(c int) int { return c }
+		case "×", "⌊", "∧":
+			fn = func(c int) int { return b2i(c == n) }
+		case "⌈", "∨":
+			fn = func(c int) int { return b2i(c > 0) }
+		default:
+			return nil, false
+		}
+		z := l.bools(x)
+		for i := range z {
+			z[i] = fn(z[i])
+		}
+		if symbol == "+" || symbol == "×" {
+			return intResult(dims, z), true
+		}
+		return boolResult(dims, z), true
+	case apl.IntArray:
+		if fn := intReducers[symbol]; fn != nil {
+			empty := int(apl.NullInt)
+			if id, ok := nullIdentity[symbol]; ok {
+				empty = id
+			}
+			if z, ok := l.ints(x.Ints, fn, empty); ok {
+				return intResult(dims, z), true
+			}
+		}
+	case numbers.FloatArray:
+		if fn := floatReducers[symbol]; fn != nil {
+			empty := math.NaN()
+			if id, ok := nullIdentity[symbol]; ok {
+				empty = float64(id)
+			}
+			return floatResult(dims, l.floats(x.Floats, fn, empty)), true
+		}
+	case numbers.ComplexArray:
+		var fn func(x, y complex128) complex128
+		switch symbol {
+		case "+":
+			fn = func(x, y complex128) complex128 { return x + y }
+		case "×":
+			fn = func(x, y complex128) complex128 { return x * y }
+		default:
+			return nil, false
+		}
+		z := l.complexes(x.Cmplx, fn)
+		if len(dims) == 0 {
+			return numbers.Complex(z[0]), true
+		}
+		return numbers.ComplexArray{Dims: dims, Cmplx: z}, true
+	}
+	return nil, false
+}
+
+// layout describes an array as outer×n×inner elements,
+// which is reduced over n.
+type layout struct {
+	outer, n, inner int
+}
+
+// bools returns the number of 1s for each reduction.
+// Along the last axis, whole words are counted.
+func (l layout) bools(x apl.BoolArray) []int {
+	z := make([]int, l.outer*l.inner)
+	if l.inner == 1 {
+		for o := range z {
+			z[o] = x.CountRange(o*l.n, o*l.n+l.n)
+		}
+		return z
+	}
+	for o := 0; o < l.outer; o++ {
+		for j := 0; j < l.inner; j++ {
+			c := 0
+			for k := 0; k < l.n; k++ {
+				if x.Bool((o*l.n+k)*l.inner + j) {
+					c++
+				}
+			}
+			z[o*l.inner+j] = c
+		}
+	}
+	return z
+}
+
+// ints reduces x from right to left and skips null values.
+// If all values are null, the result is empty.
+// It returns false, if fn overflows.
+func (l layout) ints(x []int, fn func(x, y int) (int, bool), empty int) ([]int, bool) {
+	null := int(apl.NullInt)
+	z := make([]int, l.outer*l.inner)
+	for o := 0; o < l.outer; o++ {
+		for j := 0; j < l.inner; j++ {
+			v, ok := empty, false
+			for k := l.n - 1; k >= 0; k-- {
+				e := x[(o*l.n+k)*l.inner+j]
+				if e == null {
+					continue
+				} else if ok {
+					if v, ok = fn(e, v); ok == false {
+						return nil, false
+					}
+				} else {
+					v, ok = e, true
+				}
+			}
+			z[o*l.inner+j] = v
+		}
+	}
+	return z, true
+}
+
+// floats reduces x from right to left and skips NaNs.
+// If all values are NaN, the result is empty.
+func (l layout) floats(x []float64, fn func(x, y float64) float64, empty float64) []float64 {
+	z := make([]float64, l.outer*l.inner)
+	for o := 0; o < l.outer; o++ {
+		for j := 0; j < l.inner; j++ {
+			v, ok := empty, false
+			for k := l.n - 1; k >= 0; k-- {
+				e := x[(o*l.n+k)*l.inner+j]
+				if math.IsNaN(e) {
+					continue
+				} else if ok {
+					v = fn(e, v)
+				} else {
+					v, ok = e, true
+				}
+			}
+			z[o*l.inner+j] = v
+		}
+	}
+	return z
+}
+
+// complexes reduces x from right to left. Complex numbers have no null value.
+func (l layout) complexes(x []complex128, fn func(x, y complex128) complex128) []complex128 {
+	z := make([]complex128, l.outer*l.inner)
+	for o := 0; o < l.outer; o++ {
+		for j := 0; j < l.inner; j++ {
+			v := x[(o*l.n+l.n-1)*l.inner+j]
+			for k := l.n - 2; k >= 0; k-- {
+				v = fn(x[(o*l.n+k)*l.inner+j], v)
+			}
+			z[o*l.inner+j] = v
+		}
+	}
+	return z
+}
+
+// nullIdentity is the result of reducing only null values.
+var nullIdentity = map[string]int{"+": 0, "×": 1}
+
+var intReducers = map[string]func(x, y int) (int, bool){
+	"+": apl.AddInt,
+	"×": apl.MulInt,
+	"⌊": func(x, y int) (int, bool) {
+		if x < y {
+			return x, true
+		}
+		return y, true
+	},
+	"⌈": func(x, y int) (int, bool) {
+		if x < y {
+			return y, true
+		}
+		return x, true
+	},
+}
+
+var floatReducers = map[string]func(x, y float64) float64{
+	"+": func(x, y float64) float64 { return x + y },
+	"×": func(x, y float64) float64 { return x * y },
+	"⌊": func(x, y float64) float64 {
+		if x < y {
+			return x
+		}
+		return y
+	},
+	"⌈": func(x, y float64) float64 {
+		if x < y {
+			return y
+		}
+		return x
+	},
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func boolResult(dims []int, z []int) apl.Value {
+	if len(dims) == 0 {
+		return apl.Bool(z[0] == 1)
+	}
+	b := apl.NewBoolArray(dims, nil)
+	for i, v := range z {
+		if v == 1 {
+			b.SetBool(i, true)
+		}
+	}
+	return b
+}
+
+func intResult(dims []int, z []int) apl.Value {
+	if len(dims) == 0 {
+		return apl.Int(z[0])
+	}
+	return apl.IntArray{Dims: dims, Ints: z}
+}
+
+func floatResult(dims []int, z []float64) apl.Value {
+	if len(dims) == 0 {
+		return numbers.Float(z[0])
+	}
+	return numbers.FloatArray{Dims: dims, Floats: z}
+}
+
+// compressBits is the fast path for compress with a boolean vector L and a vector R.
+// It visits only the words of L that contain 1s and copies the selected elements
+// of uniform arrays directly.
+// It returns false for other arguments, which are handled by compress.
+func compressBits(L apl.BoolArray, R apl.Value) (apl.Value, bool) {
+	ar, ok := R.(apl.Array)
+	if ok == false || len(L.Dims) != 1 || len(ar.Shape()) != 1 || ar.Size() != L.Size() {
+		return nil, false
+	}
+	n := L.Count()
+	if n == 0 {
+		return nil, false
+	}
+	k := 0
+	each := func(f func(i, j int)) {
+		for w, x := range L.Bits {
+			for x != 0 {
+				f(k, w<<6+bits.TrailingZeros64(x))
+				x &= x - 1
+				k++
+			}
+		}
+	}
+	dims := []int{n}
+	switch x := ar.(type) {
+	case apl.BoolArray:
+		z := apl.NewBoolArray(dims, nil)
+		each(func(i, j int) {
+			if x.Bool(j) {
+				z.SetBool(i, true)
+			}
+		})
+		return z, true
+	case apl.IntArray:
+		z := apl.IntArray{Dims: dims, Ints: make([]int, n)}
+		each(func(i, j int) { z.Ints[i] = x.Ints[j] })
+		return z, true
+	case numbers.FloatArray:
+		z := numbers.FloatArray{Dims: dims, Floats: make([]float64, n)}
+		each(func(i, j int) { z.Floats[i] = x.Floats[j] })
+		return z, true
+	case numbers.ComplexArray:
+		z := numbers.ComplexArray{Dims: dims, Cmplx: make([]complex128, n)}
+		each(func(i, j int) { z.Cmplx[i] = x.Cmplx[j] })
+		return z, true
+	case apl.StringArray:
+		z := apl.StringArray{Dims: dims, Strings: make([]string, n)}
+		each(func(i, j int) { z.Strings[i] = x.Strings[j] })
+		return z, true
+	}
+	z := apl.MixedArray{Dims: dims, Values: make([]apl.Value, n)}
+	each(func(i, j int) { z.Values[i] = ar.At(j) })
+	return z, true
+}
+
+// boolsToInts converts a BoolArray to an IntArray of 0s and 1s.
+func boolsToInts(b apl.BoolArray) apl.IntArray {
+	z := apl.IntArray{Dims: apl.CopyShape(b), Ints: make([]int, b.Size())}
+	for i := range z.Ints {
+		if b.Bool(i) {
+			z.Ints[i] = 1
+		}
+	}
+	return z
+}
@@ -0,0 +1,593 @@
+package operators
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/ktye/iv/apl"
+	. "github.com/ktye/iv/apl/domain"
+)
+
+func init() {
+	register(operator{
+		symbol:  "⌸",
+		Domain:  MonadicOp(Function(nil)),
+		doc:     "key, group by",
+		derived: key,
+	})
+	register(operator{
+		symbol:  "⌸",
+		Domain:  MonadicOp(IsObject(nil)),
+		doc:     "group by, aggregate table columns",
+		derived: groupBy,
+	})
+}
+
+// key groups the major cells of R by their keys.
+//	f⌸R: R are the keys, f is called with each unique key and the indexes of all occurrences.
+//	L f⌸R: L are the keys, f is called with each unique key and the major cells of R with this key.
+// Keys are the major cells of an array, the elements of a list or the rows of a table.
+// Values may be an array, a list or a table. A table is grouped into sub-tables.
+// If all results have the same shape, they are mixed to an array
+// with one major cell for each unique key.
+// Otherwise a List is returned.
+func key(a *apl.Apl, LO, _ apl.Value) apl.Function {
+	derived := func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		f := LO.(apl.Function)
+		K := R
+		if L != nil {
+			K = L
+		}
+		keys, groups, err := group(a, K)
+		if err != nil {
+			return nil, err
+		}
+
+		if L != nil {
+			if n, err := majorCells(R); err != nil {
+				return nil, err
+			} else if n != count(groups) {
+				return nil, fmt.Errorf("key: L and R have a different number of major cells: %d %d", count(groups), n)
+			}
+		}
+
+		results := make([]apl.Value, len(groups))
+		for i, g := range groups {
+			var v apl.Value
+			if L == nil {
+				idx := apl.IntArray{Dims: []int{len(g)}, Ints: make([]int, len(g))}
+				for k := range g {
+					idx.Ints[k] = g[k] + a.Origin
+				}
+				v = idx
+			} else {
+				v, err = selectCells(a, R, g)
+				if err != nil {
+					return nil, err
+				}
+			}
+			results[i], err = f.Call(a, keys[i], v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return mixResults(results), nil
+	}
+	return function(derived)
+}
+
+// count returns the total number of elements in all groups.
+func count(groups [][]int) int {
+	n := 0
+	for _, g := range groups {
+		n += len(g)
+	}
+	return n
+}
+
+// majorCells returns the number of major cells of V.
+// A scalar is treated as a single cell.
+func majorCells(V apl.Value) (int, error) {
+	switch v := V.(type) {
+	case apl.Table:
+		return v.Rows, nil
+	case apl.List:
+		return len(v), nil
+	case apl.EmptyArray:
+		return 0, nil
+	case apl.Array:
+		s := v.Shape()
+		if len(s) == 0 {
+			return 0, nil
+		}
+		return s[0], nil
+	case apl.Object:
+		return 0, fmt.Errorf("key: cannot group an object: %T", V)
+	}
+	return 1, nil
+}
+
+// cell returns major cell i of V.
+// A table row is returned as a List.
+func cell(V apl.Value, i int) apl.Value {
+	switch v := V.(type) {
+	case apl.Table:
+		row := make(apl.List, len(v.K))
+		for k, key := range v.K {
+			row[k] = v.M[key].(apl.Array).At(i)
+		}
+		return row
+	case apl.List:
+		return v[i]
+	case apl.Array:
+		s := v.Shape()
+		if len(s) == 1 {
+			return v.At(i)
+		}
+		c := apl.MakeArray(v, apl.CopyShape(v)[1:])
+		n := c.Size()
+		for k := 0; k < n; k++ {
+			c.Set(k, v.At(i*n+k))
+		}
+		return c
+	}
+	return V
+}
+
+// group returns the unique keys of K in order of their first occurrence
+// and the indexes of the major cells of K for each key.
+// Uniform vectors of ints or strings are grouped with a hash map,
+// as are other uniform vectors with exact values, see hashable.
+// Floating point values that are compared with ⎕CT are bucketed, see groupTolerant.
+// Other keys are compared with ≡.
+func group(a *apl.Apl, K apl.Value) ([]apl.Value, [][]int, error) {
+	if ar, ok := K.(apl.Array); ok {
+		if _, ok := K.(apl.List); ok == false {
+			if u, ok := a.Unify(ar, false); ok {
+				K = u
+			}
+		}
+	}
+
+	var keys []apl.Value
+	var groups [][]int
+	switch k := K.(type) {
+	case apl.IntArray:
+		if len(k.Dims) == 1 {
+			m := make(map[int]int)
+			for i, n := range k.Ints {
+				if g, ok := m[n]; ok {
+					groups[g] = append(groups[g], i)
+				} else {
+					m[n] = len(keys)
+					keys = append(keys, apl.Int(n))
+					groups = append(groups, []int{i})
+				}
+			}
+			return keys, groups, nil
+		}
+	case apl.StringArray:
+		if len(k.Dims) == 1 {
+			m := make(map[string]int)
+			for i, s := range k.Strings {
+				if g, ok := m[s]; ok {
+					groups[g] = append(groups[g], i)
+				} else {
+					m[s] = len(keys)
+					keys = append(keys, apl.String(s))
+					groups = append(groups, []int{i})
+				}
+			}
+			return keys, groups, nil
+		}
+	}
+
+	if u, ok := K.(apl.Uniform); ok && hashable(a, u) {
+		m := make(map[interface{}]int)
+		for i := 0; i < u.Size(); i++ {
+			v := u.At(i)
+			var h interface{} = v
+			if apl.IsNull(v) {
+				h = nullKey{}
+			}
+			if g, ok := m[h]; ok {
+				groups[g] = append(groups[g], i)
+			} else {
+				m[h] = len(keys)
+				keys = append(keys, v)
+				groups = append(groups, []int{i})
+			}
+		}
+		return keys, groups, nil
+	}
+
+	if u, ok := K.(apl.Uniform); ok {
+		if keys, groups, ok := groupTolerant(a, u); ok {
+			return keys, groups, nil
+		}
+	}
+
+	n, err := majorCells(K)
+	if err != nil {
+		return nil, nil, err
+	}
+	match := apl.Primitive("≡")
+	for i := 0; i < n; i++ {
+		c := cell(K, i)
+		found := false
+		for g := range keys {
+			if eq, err := match.Call(a, keys[g], c); err != nil {
+				return nil, nil, err
+			} else if eq.(apl.Bool) == true {
+				groups[g] = append(groups[g], i)
+				found = true
+				break
+			}
+		}
+		if found == false {
+			keys = append(keys, c)
+			groups = append(groups, []int{i})
+		}
+	}
+	return keys, groups, nil
+}
+
+// nullKey is the hash key for null values, which match each other, e.g. NaN.
+type nullKey struct{}
+
+// tolerantKey is the hash bucket of a float value with the comparison tolerance.
+type tolerantKey struct {
+	neg bool
+	b   uint64
+}
+
+// groupTolerant groups a uniform vector of real numbers that are compared with ⎕CT.
+// The bits of |x| are truncated, such that a bucket is at least ⎕CT×|x| wide.
+// Values that are tolerantly equal are in the same or in neighbouring buckets.
+// Each bucket stores the groups of the keys it contains and a value is compared to the keys
+// of it's own and the neighbouring buckets.
+// The result is the same as comparing with each key in order, but it does not depend on the number of groups.
+// It returns false, if the values cannot be bucketed.
+func groupTolerant(a *apl.Apl, u apl.Uniform) ([]apl.Value, [][]int, bool) {
+	type tolerant interface {
+		TolerantEquals(apl.Value, float64) (apl.Bool, bool)
+		ToFloat() (float64, bool)
+	}
+	if s := u.Shape(); len(s) != 1 || s[0] == 0 || a.CT <= 0 || a.CT >= 1 {
+		return nil, nil, false
+	}
+	shift := 53 + int(math.Ceil(math.Log2(a.CT)))
+	if shift < 0 {
+		shift = 0
+	}
+	var keys []apl.Value
+	var groups [][]int
+	m := make(map[tolerantKey][]int)
+	null := -1
+	for i := 0; i < u.Size(); i++ {
+		v := u.At(i)
+		if apl.IsNull(v) {
+			if null < 0 {
+				null = len(keys)
+				keys = append(keys, v)
+				groups = append(groups, nil)
+			}
+			groups[null] = append(groups[null], i)
+			continue
+		}
+		t, ok := v.(tolerant)
+		if ok == false {
+			return nil, nil, false
+		}
+		f, ok := t.ToFloat()
+		if ok == false || math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, nil, false
+		}
+		k := tolerantKey{neg: f < 0, b: math.Float64bits(math.Abs(f)) >> uint(shift)}
+		g := -1
+		for _, b := range []uint64{k.b - 1, k.b, k.b + 1} {
+			if b == k.b-1 && k.b == 0 {
+				continue
+			}
+			for _, c := range m[tolerantKey{k.neg, b}] {
+				if g >= 0 && c > g {
+					break
+				}
+				if eq, ok := t.TolerantEquals(keys[c], a.CT); ok && eq == true {
+					g = c
+					break
+				}
+			}
+		}
+		if g < 0 {
+			g = len(keys)
+			m[k] = append(m[k], g)
+			keys = append(keys, v)
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return keys, groups, true
+}
+
+// hashable returns true, if the values of a uniform vector can be used as keys in a hash map
+// with the same result as comparing them with ≡.
+// The values must be comparable and not pointers.
+// Values that are compared with the comparison tolerance are only accepted if ⎕CT is 0,
+// or if all values are integers that are too small to be tolerantly equal to each other.
+func hashable(a *apl.Apl, u apl.Uniform) bool {
+	if s := u.Shape(); len(s) != 1 || s[0] == 0 {
+		return false
+	}
+	v := u.At(0)
+	t := reflect.TypeOf(v)
+	if t.Comparable() == false || t.Kind() == reflect.Ptr {
+		return false
+	}
+	if _, ok := v.(interface {
+		TolerantEquals(apl.Value, float64) (apl.Bool, bool)
+	}); ok == false || a.CT == 0 {
+		return true
+	}
+	for i := 0; i < u.Size(); i++ {
+		v := u.At(i)
+		if apl.IsNull(v) {
+			continue
+		}
+		n, ok := v.(apl.Number).ToIndex()
+		if ok == false || math.Abs(float64(n))*a.CT >= 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// groupRows groups the rows of the columns by their values, using the same semantics as group.
+// It returns the row indexes for each group in order of their first occurrence.
+// Each column is grouped separately, the group ids of multiple columns
+// are combined with a hash map in a single pass over the rows.
+func groupRows(a *apl.Apl, cols []apl.Array, rows int) ([][]int, error) {
+	ids := make([]int, rows)
+	for c, col := range cols {
+		_, groups, err := group(a, col)
+		if err != nil {
+			return nil, err
+		}
+		if c == 0 {
+			for g, idx := range groups {
+				for _, i := range idx {
+					ids[i] = g
+				}
+			}
+			continue
+		}
+		colid := make([]int, rows)
+		for g, idx := range groups {
+			for _, i := range idx {
+				colid[i] = g
+			}
+		}
+		m := make(map[[2]int]int)
+		for i := range ids {
+			k := [2]int{ids[i], colid[i]}
+			id, ok := m[k]
+			if ok == false {
+				id = len(m)
+				m[k] = id
+			}
+			ids[i] = id
+		}
+	}
+
+	var groups [][]int
+	for i, id := range ids {
+		if id == len(groups) {
+			groups = append(groups, nil)
+		}
+		groups[id] = append(groups[id], i)
+	}
+	return groups, nil
+}
+
+// groupBy aggregates the columns of a table for each group of rows.
+//	K D⌸T
+// K contains the names of the key columns of table T,
+// D is a dict that maps column names to functions, e.g: `Qty`Px#(+/;{(+/⍵)÷≢⍵};)
+// Rows with the same values in the key columns form a group, as for the key operator.
+// The result is a table with one row for each group in order of their first occurrence.
+// It contains the key columns followed by a column for each key in D,
+// with the result of the function called with the values of the group.
+func groupBy(a *apl.Apl, LO, _ apl.Value) apl.Function {
+	derived := func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		d, ok := LO.(*apl.Dict)
+		if ok == false {
+			return nil, fmt.Errorf("group by: left operand must be a dict: %T", LO)
+		}
+		t, ok := R.(apl.Table)
+		if ok == false {
+			return nil, fmt.Errorf("group by: right argument must be a table: %T", R)
+		}
+		if L == nil {
+			return nil, fmt.Errorf("group by: left argument must contain the key columns")
+		}
+		var keys []apl.Value
+		if ar, ok := L.(apl.Array); ok {
+			for i := 0; i < ar.Size(); i++ {
+				keys = append(keys, ar.At(i))
+			}
+		} else {
+			keys = []apl.Value{L}
+		}
+
+		res := apl.Dict{M: make(map[apl.Value]apl.Value)}
+		cols := make([]apl.Array, len(keys))
+		for i, k := range keys {
+			col, ok := t.M[k]
+			if ok == false {
+				return nil, apl.Errorf(apl.IndexError, "group by: key column does not exist: %s", k.String(a))
+			} else if _, ok := res.M[k]; ok {
+				return nil, fmt.Errorf("group by: duplicate key column: %s", k.String(a))
+			}
+			cols[i] = col.(apl.Array)
+			res.K = append(res.K, k)
+			res.M[k] = nil
+		}
+		seen := make(map[apl.Value]bool)
+		for _, k := range d.K {
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			if _, ok := t.M[k]; ok == false {
+				return nil, apl.Errorf(apl.IndexError, "group by: column does not exist: %s", k.String(a))
+			} else if _, ok := res.M[k]; ok {
+				return nil, fmt.Errorf("group by: cannot aggregate a key column: %s", k.String(a))
+			} else if _, ok := d.M[k].(apl.Function); ok == false {
+				return nil, fmt.Errorf("group by: value for %s is not a function: %T", k.String(a), d.M[k])
+			}
+			res.K = append(res.K, k)
+		}
+
+		groups, err := groupRows(a, cols, t.Rows)
+		if err != nil {
+			return nil, err
+		}
+		if len(groups) == 0 {
+			for _, k := range res.K {
+				res.M[k] = apl.EmptyArray{}
+			}
+			return apl.Table{Dict: &res, Rows: 0}, nil
+		}
+
+		first := make([]int, len(groups))
+		for i, g := range groups {
+			first[i] = g[0]
+		}
+		for i, k := range keys {
+			if res.M[k], err = selectCells(a, cols[i], first); err != nil {
+				return nil, err
+			}
+		}
+		for _, k := range res.K[len(keys):] {
+			f := d.M[k].(apl.Function)
+			col := t.M[k]
+			m := apl.MixedArray{Dims: []int{len(groups)}, Values: make([]apl.Value, len(groups))}
+			for i, g := range groups {
+				v, err := selectCells(a, col, g)
+				if err != nil {
+					return nil, err
+				}
+				if m.Values[i], err = f.Call(a, nil, v); err != nil {
+					return nil, err
+				}
+			}
+			if u, ok := a.Unify(m, true); ok {
+				res.M[k] = u
+			} else {
+				res.M[k] = m
+			}
+		}
+		return apl.Table{Dict: &res, Rows: len(groups)}, nil
+	}
+	return function(derived)
+}
+
+// selectCells returns the major cells of V with the given indexes.
+// Tables return a sub-table, lists a list and arrays an array of the same type.
+func selectCells(a *apl.Apl, V apl.Value, idx []int) (apl.Value, error) {
+	switch v := V.(type) {
+	case apl.Table:
+		d := apl.Dict{K: make([]apl.Value, len(v.K)), M: make(map[apl.Value]apl.Value)}
+		for i, k := range v.K {
+			col := v.M[k].(apl.Array)
+			c := apl.MakeArray(col, []int{len(idx)})
+			for n, row := range idx {
+				if err := c.Set(n, col.At(row)); err != nil {
+					return nil, err
+				}
+			}
+			d.K[i] = k
+			d.M[k] = c
+		}
+		return apl.Table{Dict: &d, Rows: len(idx)}, nil
+	case apl.List:
+		l := make(apl.List, len(idx))
+		for i, n := range idx {
+			l[i] = v[n]
+		}
+		return l, nil
+	case apl.Array:
+		shape := apl.CopyShape(v)
+		if len(shape) == 0 {
+			return apl.EmptyArray{}, nil
+		}
+		shape[0] = len(idx)
+		res := apl.MakeArray(v, shape)
+		m := 1
+		for _, n := range shape[1:] {
+			m *= n
+		}
+		for i, n := range idx {
+			for k := 0; k < m; k++ {
+				if err := res.Set(i*m+k, v.At(n*m+k)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return res, nil
+	}
+	return V, nil
+}
+
+// mixResults returns the results of the key operator.
+// If all results are scalars, it returns a vector.
+// If all are arrays with the same shape, they are mixed to an array of rank+1.
+// Otherwise, e.g. if the results are ragged, a List is returned.
+func mixResults(results []apl.Value) apl.Value {
+	if len(results) == 0 {
+		return apl.EmptyArray{}
+	}
+	var shape []int
+	for i, v := range results {
+		_, istable := v.(apl.Table)
+		_, islist := v.(apl.List)
+		_, isobj := v.(apl.Object)
+		if istable || islist || isobj {
+			return apl.List(results)
+		}
+		var s []int
+		if ar, ok := v.(apl.Array); ok {
+			s = ar.Shape()
+			if len(s) == 0 {
+				return apl.List(results)
+			}
+		}
+		if i == 0 {
+			shape = s
+		} else if len(s) != len(shape) {
+			return apl.List(results)
+		} else {
+			for k := range s {
+				if s[k] != shape[k] {
+					return apl.List(results)
+				}
+			}
+		}
+	}
+
+	res := apl.MixedArray{Dims: append([]int{len(results)}, shape...)}
+	res.Values = make([]apl.Value, apl.ArraySize(res))
+	if len(shape) == 0 {
+		copy(res.Values, results)
+		return res
+	}
+	m := len(res.Values) / len(results)
+	for i, v := range results {
+		ar := v.(apl.Array)
+		for k := 0; k < m; k++ {
+			res.Values[i*m+k] = ar.At(k)
+		}
+	}
+	return res
+}
@@ -32,7 +32,11 @@ func power(a *apl.Apl, f, g apl.Value) apl.Function {
 			}
 			n := int(nv.(apl.Int))
 			if n < 0 {
-				return nil, fmt.Errorf("power: function inverse is not implemented")
+				inv, err := a.Inverse(f)
+				if err != nil {
+					return nil, err
+				}
+				f, n = inv, -n
 			} else if n == 0 {
 				return R, nil
 			}
@@ -77,5 +81,15 @@ func power(a *apl.Apl, f, g apl.Value) apl.Function {
 			}
 		}
 	}
-	return function(derived)
+	inverse := func(a *apl.Apl) (apl.Function, error) {
+		if _, ok := g.(apl.Function); ok {
+			return nil, fmt.Errorf("domain error: power with a function right operand has no inverse")
+		}
+		nv, ok := ToIndex(nil).To(a, g)
+		if ok == false {
+			return nil, fmt.Errorf("power: non-function RO must be an integer: %T", g)
+		}
+		return power(a, f, -nv.(apl.Int)), nil
+	}
+	return invertible{function(derived), inverse}
 }
@@ -67,10 +67,10 @@ func rank(a *apl.Apl, LO, RO apl.Value) apl.Function {
 			}
 			shape := x.Shape()
 			if rank < 0 || rank > len(shape) {
-				return nil, fmt.Errorf("cannot get %d-subcell of array with rank %d", rank, len(shape))
+				return nil, apl.Errorf(apl.RankError, "cannot get %d-subcell of array with rank %d", rank, len(shape))
 			}
 			if n < 0 || n > shape[len(shape)-1-rank] {
-				return nil, fmt.Errorf("cannot get %d-subcell number %d: length of axis is %d", rank, n, shape[rank])
+				return nil, apl.Errorf(apl.IndexError, "cannot get %d-subcell number %d: length of axis is %d", rank, n, shape[rank])
 			}
 
 			subshape := apl.CopyShape(x)
@@ -100,21 +100,21 @@ func rank(a *apl.Apl, LO, RO apl.Value) apl.Function {
 				q += len(ls)
 			}
 			if q < 0 || q > len(ls) {
-				return nil, fmt.Errorf("rank: q (%d) exceeds rank of left argument: %d", q, len(ls))
+				return nil, apl.Errorf(apl.RankError, "rank: q (%d) exceeds rank of left argument: %d", q, len(ls))
 			}
 			// r specifies rank of R.
 			if r < 0 {
 				r += len(rs)
 			}
 			if r < 0 || r > len(rs) {
-				return nil, fmt.Errorf("rank: r (%d) exceeds rank of right argument: %d", r, len(rs))
+				return nil, apl.Errorf(apl.RankError, "rank: r (%d) exceeds rank of right argument: %d", r, len(rs))
 			}
 
 			// The number of subcells must match or any must be 0 and is repeated.
 			ml := subcells(al, q)
 			mr := subcells(ar, r)
 			if ml != mr && ml > 0 && mr > 0 {
-				return nil, fmt.Errorf("rank: L and R have different number of subcells")
+				return nil, apl.Errorf(apl.LengthError, "rank: L and R have different number of subcells")
 			}
 			m := ml
 			if ml < mr {
@@ -127,14 +127,16 @@ func rank(a *apl.Apl, LO, RO apl.Value) apl.Function {
 				frame = apl.CopyShape(al)
 				frame = frame[:len(frame)-q]
 			}
-			var subl, subr apl.Value
-			for i := 0; i < m; i++ {
+			results = make([]apl.Value, m)
+			err = a.ForEach(m, func(a *apl.Apl, i int) error {
+				var subl, subr apl.Value
+				var err error
 				if ml == 0 {
 					subl = al
 				} else {
 					subl, err = subcell(al, q, i)
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
 				if mr == 0 {
@@ -142,14 +144,14 @@ func rank(a *apl.Apl, LO, RO apl.Value) apl.Function {
 				} else {
 					subr, err = subcell(ar, r, i)
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
-				v, err := f.Call(a, subl, subr)
-				if err != nil {
-					return nil, err
-				}
-				results = append(results, v)
+				results[i], err = f.Call(a, subl, subr)
+				return err
+			})
+			if err != nil {
+				return nil, err
 			}
 
 		} else {
@@ -159,21 +161,22 @@ func rank(a *apl.Apl, LO, RO apl.Value) apl.Function {
 				p += len(rs)
 			}
 			if p < 0 || r > len(rs) {
-				return nil, fmt.Errorf("rank: p (%d) exceeds rank of right argument: %d", p, len(rs))
+				return nil, apl.Errorf(apl.RankError, "rank: p (%d) exceeds rank of right argument: %d", p, len(rs))
 			}
 
 			// Apply f successsively to sub arrays of R specified by p.
 			frame = frame[:len(frame)-p]
-			for i := 0; i < subcells(ar, p); i++ {
+			results = make([]apl.Value, subcells(ar, p))
+			err = a.ForEach(len(results), func(a *apl.Apl, i int) error {
 				s, err := subcell(ar, p, i)
 				if err != nil {
-					return nil, err
-				}
-				v, err := f.Call(a, nil, s)
-				if err != nil {
-					return nil, err
+					return err
 				}
-				results = append(results, v)
+				results[i], err = f.Call(a, nil, s)
+				return err
+			})
+			if err != nil {
+				return nil, err
 			}
 		}
 
@@ -200,7 +203,7 @@ func rank(a *apl.Apl, LO, RO apl.Value) apl.Function {
 					ga := apl.MixedArray{Dims: common}
 					ga.Values = make([]apl.Value, apl.ArraySize(ga))
 					for i := range ga.Values {
-						ga.Values[i] = results[n] // TODO copy?
+						ga.Values[i] = results[n]
 					}
 					results[n] = ga
 				}
@@ -263,7 +266,7 @@ func rank(a *apl.Apl, LO, RO apl.Value) apl.Function {
 			} else {
 				vr := results[i].(apl.Array)
 				for n := 0; n < commonsize; n++ {
-					res.Values[off+n] = vr.At(n) // TODO copy?
+					res.Values[off+n] = vr.At(n)
 				}
 				off += commonsize
 			}
@@ -276,6 +279,7 @@ func rank(a *apl.Apl, LO, RO apl.Value) apl.Function {
 // sendSubArray assembles an array the given rank from strings read on channel c.
 // It returns a channel and sends arrays of the rank.
 func sendSubArray(a *apl.Apl, rank int, in apl.Channel) (apl.Value, error) {
+	a = a.Fork()
 	out := apl.NewChannel()
 	go func() {
 		defer close(out[0])
@@ -313,7 +317,7 @@ func Take(a *apl.Apl, ai apl.IntArray, ar apl.Array, x []int) (apl.Array, error)
 	}
 
 	if len(ai.Ints) != len(x) {
-		return nil, fmt.Errorf("take: length of L and axis must match")
+		return nil, apl.Errorf(apl.LengthError, "take: length of L and axis must match")
 	}
 
 	shape := make([]int, len(rs))
@@ -338,11 +342,19 @@ func Take(a *apl.Apl, ai apl.IntArray, ar apl.Array, x []int) (apl.Array, error)
 		}
 	}
 
-	res := apl.MixedArray{Dims: shape}
-	res.Values = make([]apl.Value, apl.ArraySize(res))
+	// Overtake fills with the fill element of R.
+	// The result has the same type as R.
+	res := apl.MakeArray(ar, shape)
+	fill := a.Fill(ar)
+	if _, ok := fill.(apl.Array); ok == false && res.Size() == 0 {
+		// An empty result keeps a scalar fill element of R, e.g. 1↓`a is an empty string array.
+		if _, ok := res.(apl.Uniform); ok == false {
+			return a.MakeFilled(fill, shape).(apl.Array), nil
+		}
+	}
 	idx := make([]int, len(shape))
 	ic, src := apl.NewIdxConverter(ar.Shape())
-	for i := range res.Values {
+	for i := 0; i < res.Size(); i++ {
 		copy(src, idx)
 		zero := false
 		for k, n := range off {
@@ -351,10 +363,12 @@ func Take(a *apl.Apl, ai apl.IntArray, ar apl.Array, x []int) (apl.Array, error)
 				zero = true
 			}
 		}
-		if zero {
-			res.Values[i] = apl.Int(0) // TODO: fill element of R?
-		} else {
-			res.Values[i] = ar.At(ic.Index(src)) // TODO: copy?
+		v := fill
+		if zero == false {
+			v = ar.At(ic.Index(src))
+		}
+		if err := res.Set(i, v); err != nil {
+			return nil, err
 		}
 		apl.IncArrayIndex(idx, shape)
 	}
@@ -62,6 +62,21 @@ func init() {
 		doc:     "expand first axis",
 		derived: expandFirst,
 	})
+	register(operator{
+		symbol:  "/",
+		Domain:  MonadicOp(IsBoolArray(nil)),
+		doc:     "compress with a boolean array",
+		derived: replicateLast,
+		selection: selectSimple(func(a *apl.Apl, LO, R apl.Value) (apl.Value, error) {
+			return Replicate(a, LO, R, -1)
+		}),
+	})
+	register(operator{
+		symbol:  "⌿",
+		Domain:  MonadicOp(IsBoolArray(nil)),
+		doc:     "compress first axis with a boolean array",
+		derived: replicateFirst,
+	})
 }
 
 func reduceLast(a *apl.Apl, f, _ apl.Value) apl.Function {
@@ -164,7 +179,7 @@ func reduct(a *apl.Apl, f apl.Function, l, r apl.Value, axis int) (apl.Value, er
 		axis = len(shape) + axis
 	}
 	if axis < 0 || axis >= len(shape) {
-		return nil, fmt.Errorf("reduce: axis rank is %d but axis %d", len(shape), axis)
+		return nil, apl.Errorf(apl.IndexError, "reduce: axis rank is %d but axis %d", len(shape), axis)
 	}
 
 	// n is the number of values being reduced (the length if the reduction axis).
@@ -194,11 +209,15 @@ func reduct(a *apl.Apl, f apl.Function, l, r apl.Value, axis int) (apl.Value, er
 		if id := identityItem(f.(apl.Value)); id == nil {
 			return nil, fmt.Errorf("reduce empty axis: cannot get identify item for %T", f)
 		} else {
-			ida := apl.MixedArray{Dims: []int{1}, Values: []apl.Value{id}}
-			return ida.Reshape(dims), nil
+			return a.MakeFilled(id, dims), nil
 		}
 	}
 
+	// Uniform numeric arrays are reduced by a kernel for some primitive functions.
+	if v, ok := reduceKernel(f.(apl.Value), ar, axis); ok {
+		return v, nil
+	}
+
 	// Reduce directly, if R is a vector.
 	if len(shape) == 1 {
 		vec := make([]apl.Value, shape[0])
@@ -234,7 +253,7 @@ func reduct(a *apl.Apl, f apl.Function, l, r apl.Value, axis int) (apl.Value, er
 		apl.IncArrayIndex(tidx, dims)
 
 		if res, err := reduce(a, vec, f); err != nil {
-			return nil, fmt.Errorf("cannot reduce: %s", err)
+			return nil, fmt.Errorf("cannot reduce: %w", err)
 		} else {
 			v.Values[k] = res
 		}
@@ -296,7 +315,7 @@ func scanfunc(a *apl.Apl, f apl.Function, L, R apl.Value, axis int) (apl.Value,
 		axis = len(dims) + axis
 	}
 	if axis < 0 || axis >= len(dims) {
-		return nil, fmt.Errorf("scan: axis rank is %d but axis %d", len(dims), axis)
+		return nil, apl.Errorf(apl.IndexError, "scan: axis rank is %d but axis %d", len(dims), axis)
 	}
 
 	// Shortcut, if R is a vector
@@ -383,10 +402,17 @@ func replicate(a *apl.Apl, L apl.Value, axis int) apl.Function {
 }
 
 // Replicate is the function L over R (L/R) where L and R are arrays.
+// L is an IntArray or a BoolArray.
 func Replicate(a *apl.Apl, L, R apl.Value, axis int) (apl.Value, error) {
+	if b, ok := L.(apl.BoolArray); ok {
+		if v, ok := compressBits(b, R); ok {
+			return v, nil
+		}
+		L = boolsToInts(b)
+	}
 	ai, ar, ax, err := commonReplExp(a, L, R, axis)
 	if err != nil {
-		return nil, fmt.Errorf("replicate: %s", err)
+		return nil, fmt.Errorf("replicate: %w", err)
 	}
 	axis = ax
 
@@ -404,7 +430,7 @@ func Replicate(a *apl.Apl, L, R apl.Value, axis int) (apl.Value, error) {
 		}
 	}
 	if ai.Dims[0] != rs[axis] {
-		return nil, fmt.Errorf("replicate: length of L must conform to length of R[axis]")
+		return nil, apl.Errorf(apl.LengthError, "replicate: length of L must conform to length of R[axis]")
 	}
 
 	iscompress := true
@@ -440,14 +466,15 @@ func Replicate(a *apl.Apl, L, R apl.Value, axis int) (apl.Value, error) {
 	res.Values = make([]apl.Value, apl.ArraySize(res))
 	ic, idx := apl.NewIdxConverter(rs)
 	dst := make([]int, len(shape))
+	fill := a.Fill(ar)
 	for i := range res.Values {
 		k := dst[axis]
 		if n := axismap[k]; n == -1 {
-			res.Values[i] = apl.Int(0) // TODO: When is a Fill value different from 0?
+			res.Values[i] = fill
 		} else {
 			copy(idx, dst)
 			idx[axis] = int(n)
-			res.Values[i] = ar.At(ic.Index(idx)) // TODO copy
+			res.Values[i] = ar.At(ic.Index(idx))
 		}
 		apl.IncArrayIndex(dst, shape)
 	}
@@ -494,7 +521,7 @@ func Expand(a *apl.Apl, L, R apl.Value, axis int) (apl.Value, error) {
 
 	ai, ar, ax, err := commonReplExp(a, L, R, axis)
 	if err != nil {
-		return nil, fmt.Errorf("expand: %s", err)
+		return nil, fmt.Errorf("expand: %w", err)
 	}
 	axis = ax
 
@@ -543,7 +570,7 @@ func Expand(a *apl.Apl, L, R apl.Value, axis int) (apl.Value, error) {
 				for m := 0; m < int(k); m++ {
 					dst[axis] = d
 					d++
-					res.Values[dic.Index(dst)] = ar.At(ic.Index(idx)) // TODO copy
+					res.Values[dic.Index(dst)] = ar.At(ic.Index(idx))
 				}
 			} else if k == 0 {
 				dst[axis] = d
@@ -618,7 +645,7 @@ func commonReplExp(a *apl.Apl, L, R apl.Value, axis int) (apl.IntArray, apl.Arra
 		for i := range r.Values {
 			copy(idx, dst)
 			idx[axis] = 0
-			r.Values[i] = ar.At(ic.Index(idx)) // TODO copy
+			r.Values[i] = ar.At(ic.Index(idx))
 			apl.IncArrayIndex(dst, shape)
 		}
 		ar = r
@@ -626,7 +653,7 @@ func commonReplExp(a *apl.Apl, L, R apl.Value, axis int) (apl.IntArray, apl.Arra
 	}
 
 	if axis < 0 || axis >= len(rs) {
-		return ai, nil, axis, fmt.Errorf("replicate: axis out of range: %d", axis)
+		return ai, nil, axis, apl.Errorf(apl.IndexError, "replicate: axis out of range: %d", axis)
 	}
 	return ai, ar, axis, nil
 }
@@ -662,9 +689,21 @@ func compress(a *apl.Apl, L, R apl.Value, axis int) (apl.Value, error) {
 	return res, nil
 }
 
+// reduce reduces the values in vec from right to left.
+// Reductions with + × ⌊ ⌈ skip null values.
+// If all values are null, + and × return their identity in the type of the nulls, ⌊ and ⌈ return null.
 func reduce(a *apl.Apl, vec []apl.Value, d apl.Function) (apl.Value, error) {
+	if p, ok := d.(apl.Primitive); ok && (p == "+" || p == "×" || p == "⌊" || p == "⌈") {
+		r := skipNulls(vec)
+		if id, ok := nullIdentity[string(p)]; ok && len(r) == 0 {
+			_, z, err := a.Tower.SameType(vec[0].(apl.Number), apl.Int(id))
+			return z, err
+		} else if len(r) > 0 {
+			vec = r
+		}
+	}
 	var err error
-	v := vec[len(vec)-1] // TODO: copy?
+	v := vec[len(vec)-1]
 	for i := len(vec) - 2; i >= 0; i-- {
 		v, err = d.Call(a, vec[i], v)
 		if err != nil {
@@ -674,6 +713,24 @@ func reduce(a *apl.Apl, vec []apl.Value, d apl.Function) (apl.Value, error) {
 	return v, nil
 }
 
+// skipNulls returns the values of vec that are not null numbers.
+func skipNulls(vec []apl.Value) []apl.Value {
+	var r []apl.Value
+	for i, v := range vec {
+		if _, ok := v.(apl.Number); ok && apl.IsNull(v) {
+			if r == nil {
+				r = append(make([]apl.Value, 0, len(vec)), vec[:i]...)
+			}
+		} else if r != nil {
+			r = append(r, v)
+		}
+	}
+	if r == nil {
+		return vec
+	}
+	return r
+}
+
 func reduceChannel(a *apl.Apl, L apl.Value, f apl.Function, c apl.Channel) (apl.Value, error) {
 	if L != nil {
 		return nil, fmt.Errorf("TODO: n-wise channel reduction")
@@ -721,7 +778,7 @@ func reduceTable(a *apl.Apl, f apl.Function, L, R apl.Value, scan bool) (apl.Val
 		if v, ok := col.(apl.Array); ok {
 			ar = v
 		} else {
-			ar = apl.MixedArray{Dims: []int{1}, Values: []apl.Value{col}} // TODO: copy?
+			ar = apl.MixedArray{Dims: []int{1}, Values: []apl.Value{col}}
 		}
 		var v apl.Value
 		if scan {
@@ -763,7 +820,7 @@ func dict2table(a *apl.Apl, d *apl.Dict) (apl.Table, error) {
 func scan(a *apl.Apl, vec []apl.Value, d apl.Function) ([]apl.Value, error) {
 	// The ith element of the result is: d/I↑V
 	res := make([]apl.Value, len(vec))
-	res[0] = vec[0] // TODO: copy?
+	res[0] = vec[0]
 	for i := 1; i < len(res); i++ {
 		if v, err := reduce(a, vec[:i+1], d); err != nil {
 			return nil, err
@@ -810,7 +867,7 @@ func nwise(a *apl.Apl, f apl.Function, L, R apl.Value, axis int) (apl.Value, err
 		} else if n == 1 {
 			return apl.EmptyArray{}, nil
 		} else {
-			return nil, fmt.Errorf("n-wise reduction: length error")
+			return nil, apl.Errorf(apl.LengthError, "n-wise reduction: length error")
 		}
 	}
 
@@ -818,13 +875,13 @@ func nwise(a *apl.Apl, f apl.Function, L, R apl.Value, axis int) (apl.Value, err
 		axis = len(rs) + axis
 	}
 	if axis < 0 || axis >= len(rs) {
-		return nil, fmt.Errorf("n-wise reduction: axis out of range")
+		return nil, apl.Errorf(apl.IndexError, "n-wise reduction: axis out of range")
 	}
 
 	shape := apl.CopyShape(ar)
 	shape[axis] -= n - 1
 	if n-rs[axis] > 2 {
-		return nil, fmt.Errorf("n-wise reduction: length error")
+		return nil, apl.Errorf(apl.LengthError, "n-wise reduction: length error")
 	}
 
 	res := apl.MixedArray{Dims: shape}
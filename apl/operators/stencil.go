@@ -36,7 +36,7 @@ func stencil(a *apl.Apl, f, RO apl.Value) apl.Function {
 		}
 		is := ai.Shape()
 		if len(is) > 2 {
-			return nil, fmt.Errorf("stencil: rank of RO is > 2: %d", len(is))
+			return nil, apl.Errorf(apl.RankError, "stencil: rank of RO is > 2: %d", len(is))
 		}
 
 		// R is an array.
@@ -113,7 +113,7 @@ func stencil(a *apl.Apl, f, RO apl.Value) apl.Function {
 				if out {
 					tmp.Values[k] = apl.Int(0)
 				} else {
-					tmp.Values[k] = ar.At(ic.Index(dst)) // TODO copy?
+					tmp.Values[k] = ar.At(ic.Index(dst))
 				}
 
 				apl.IncArrayIndex(sdx, tmp.Dims)
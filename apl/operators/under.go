@@ -0,0 +1,81 @@
+package operators
+
+import (
+	"github.com/ktye/iv/apl"
+	. "github.com/ktye/iv/apl/domain"
+)
+
+func init() {
+	register(operator{
+		symbol:  "⍢",
+		Domain:  DyadicOp(Split(Function(nil), Function(nil))),
+		doc:     "under, dual",
+		derived: under,
+	})
+}
+
+// under applies f to the arguments transformed by g and transforms the result back
+// with the inverse of g:
+//	f⍢g R ←→ g⍣¯1 f g R
+//	L f⍢g R ←→ g⍣¯1 (g L) f g R
+// The inverse of ravel is structural, it restores the shape of R:
+//	f⍢, R ←→ (⍴R)⍴f,R
+func under(a *apl.Apl, f, g apl.Value) apl.Function {
+	derived := func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		f := f.(apl.Function)
+		g := g.(apl.Function)
+		var inv apl.Function
+		if p, ok := g.(apl.Primitive); ok && p == "," {
+			inv = unravel(R)
+		} else {
+			var err error
+			inv, err = a.Inverse(g)
+			if err != nil {
+				return nil, err
+			}
+		}
+		r, err := g.Call(a, nil, R)
+		if err != nil {
+			return nil, err
+		}
+		var l apl.Value
+		if L != nil {
+			l, err = g.Call(a, nil, L)
+			if err != nil {
+				return nil, err
+			}
+		}
+		v, err := f.Call(a, l, r)
+		if err != nil {
+			return nil, err
+		}
+		return inv.Call(a, nil, v)
+	}
+	return function(derived)
+}
+
+// unravel returns the inverse of ravel for R.
+// It reshapes to the shape of R, which requires the same number of elements.
+func unravel(R apl.Value) apl.Function {
+	return function(func(a *apl.Apl, _, v apl.Value) (apl.Value, error) {
+		var shape []int
+		if ar, ok := R.(apl.Array); ok {
+			shape = apl.CopyShape(ar)
+		}
+		size := 1
+		for _, d := range shape {
+			size *= d
+		}
+		ar, ok := v.(apl.Array)
+		if ok == false {
+			ar = apl.MixedArray{Dims: []int{1}, Values: []apl.Value{v}}
+		}
+		if n := ar.Size(); n != size {
+			return nil, apl.Errorf(apl.LengthError, "under: inverse of ravel: result has %d elements, expected %d", n, size)
+		}
+		if shape == nil {
+			return ar.At(0), nil
+		}
+		return apl.Primitive("⍴").Call(a, apl.IntArray{Dims: []int{len(shape)}, Ints: shape}, v)
+	})
+}
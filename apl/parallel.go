@@ -0,0 +1,87 @@
+package apl
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// SetParallel is called when a value is assigned to ⎕PAR.
+// It sets the number of goroutines used by ForEach.
+// The default value 0 evaluates sequentially.
+func (a *Apl) SetParallel(R Value) error {
+	if n, ok := R.(Number); ok {
+		if i, ok := n.ToIndex(); ok && i >= 0 {
+			a.Parallel = i
+			return nil
+		}
+	}
+	return fmt.Errorf("⎕PAR must be a non-negative integer: %s", R.String(a))
+}
+
+// ForEach calls f for each i from 0 to n-1.
+// It is used by operators like each ¨ and rank ⍤, which apply a function to the cells of an array.
+//
+// If a.Parallel is larger than 1, the calls are distributed over a.Parallel goroutines.
+// Each goroutine evaluates on it's own fork of the interpreter and evaluates sequentially
+// within f, see Fork.
+// The function should be pure: it may assign variables outside of a lambda
+// or write to the output, but the order is not defined.
+// f must store it's results by the index i.
+//
+// ForEach returns the error for the lowest index, as a sequential evaluation would.
+// Calls for higher indexes are not started after an error.
+// A panic in a goroutine is returned as an error, as Eval does for a sequential evaluation.
+func (a *Apl) ForEach(n int, f func(a *Apl, i int) error) error {
+	workers := a.Parallel
+	if workers > n {
+		workers = n
+	}
+	if workers < 2 {
+		for i := 0; i < n; i++ {
+			if err := f(a, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var next int64 = -1
+	first, firstErr := int64(n), error(nil)
+	call := func(b *Apl, i int) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %s\n%s", r, string(debug.Stack()))
+			}
+		}()
+		return f(b, i)
+	}
+	for w := 0; w < workers; w++ {
+		b := a.Fork()
+		b.Parallel = 0
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := atomic.AddInt64(&next, 1)
+				if i >= atomic.LoadInt64(&first) {
+					return
+				}
+				if err := call(b, int(i)); err != nil {
+					mu.Lock()
+					if i < first {
+						atomic.StoreInt64(&first, i)
+						firstErr = err
+					}
+					mu.Unlock()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,59 @@
+package apl
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestForEach(t *testing.T) {
+	for _, workers := range []int{0, 1, 4, 200} {
+		a := New(nil)
+		a.Parallel = workers
+		v := make([]int, 100)
+		err := a.ForEach(len(v), func(b *Apl, i int) error {
+			if workers > 1 && b == a {
+				return fmt.Errorf("not forked")
+			}
+			v[i] = i * i
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range v {
+			if v[i] != i*i {
+				t.Fatalf("workers %d: v[%d] = %d", workers, i, v[i])
+			}
+		}
+
+		// The error for the lowest index is returned.
+		err = a.ForEach(len(v), func(b *Apl, i int) error {
+			if i%30 == 29 {
+				return fmt.Errorf("error %d", i)
+			}
+			return nil
+		})
+		if err == nil || err.Error() != "error 29" {
+			t.Fatalf("workers %d: got %v", workers, err)
+		}
+	}
+}
+
+// TestForEachPanic tests that a panic in a goroutine is returned as an error.
+func TestForEachPanic(t *testing.T) {
+	for _, workers := range []int{4, 200} {
+		a := New(nil)
+		a.Parallel = workers
+		err := a.ForEach(100, func(b *Apl, i int) error {
+			if i%30 == 29 {
+				var m map[int]int
+				m[i] = i
+			}
+			return nil
+		})
+		if err == nil || strings.HasPrefix(err.Error(), "panic: assignment to entry in nil map") == false {
+			t.Fatalf("workers %d: got %v", workers, err)
+		}
+	}
+}
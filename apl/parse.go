@@ -13,6 +13,7 @@ type parser struct {
 	tokens []scan.Token
 	stack  []item
 	pos    int
+	nouns  map[string]bool // operands ⍺⍺ or ⍵⍵ that are arrays, see lambdaOp.
 }
 
 const (
@@ -140,9 +141,9 @@ func (p *parser) parseStatement() (item, error) {
 		// A symbol may be a primitive function, a dyadic or a monadic operator.
 		case scan.Symbol:
 
-			if _, ok := p.a.primitives[Primitive(t.S)]; ok {
+			if p.a.handlers(Primitive(t.S)) != nil {
 				push(item{e: Primitive(t.S), class: verb}, false)
-			} else if ops, ok := p.a.operators[t.S]; ok {
+			} else if ops, ok := p.a.operator(t.S); ok {
 				i := item{e: &derived{op: t.S}, class: adverb}
 				if ops[0].DyadicOp() == true {
 					i.class = conjunction
@@ -164,8 +165,15 @@ func (p *parser) parseStatement() (item, error) {
 
 		case scan.Identifier:
 			i := item{class: verb}
-			if ok, fok := isVarname(t.S); ok == false {
+			if ok, fok := p.isVarname(t.S); ok == false {
 				return item{}, fmt.Errorf("illegal variable name: %s", t.S)
+			} else if isop, dyadic := scan.IsOperatorName(t.S); isop && p.isAssignTarget() == false {
+				// User defined operator.
+				i.e = &derived{op: t.S}
+				i.class = adverb
+				if dyadic {
+					i.class = conjunction
+				}
 			} else if fok == false {
 				e, err := p.collectArray(t)
 				if err != nil {
@@ -262,7 +270,7 @@ rev:
 	}
 
 	// Create a new parser for the substatement and return it's result.
-	q := &parser{a: p.a, tokens: tokens}
+	q := &parser{a: p.a, tokens: tokens, nouns: p.nouns}
 
 	switch left {
 	case scan.LeftParen:
@@ -288,7 +296,7 @@ func (p *parser) parseList() (item, error) {
 	}
 	lst := make(list, len(l))
 	for i := range l {
-		q := &parser{a: p.a, tokens: l[i]}
+		q := &parser{a: p.a, tokens: l[i], nouns: p.nouns}
 		it, err := q.parseStatement()
 		if err != nil {
 			return item{}, err
@@ -304,7 +312,7 @@ func (p *parser) parseBrackets() (item, error) {
 	l := p.splitTokens(scan.Semicolon, scan.LeftBrack, scan.RightBrack)
 	spec := make(idxSpec, len(l))
 	for i := range l {
-		q := &parser{a: p.a, tokens: l[i]}
+		q := &parser{a: p.a, tokens: l[i], nouns: p.nouns}
 		it, err := q.parseStatement()
 		if err != nil {
 			return item{}, err
@@ -324,11 +332,20 @@ func (p *parser) parseBrackets() (item, error) {
 // The outer braces are not present anymore in the parsers's tokens.
 // Lambdas are calles dfns in dyalog: DyaProg p. 131
 func (p *parser) parseLambda() (item, error) {
+	// The tokens of an operator body are kept to parse it again for array operands.
+	var tokens []scan.Token
+	for _, t := range p.tokens {
+		if t.T == scan.Identifier && (t.S == "⍺⍺" || t.S == "⍵⍵") {
+			tokens = append([]scan.Token(nil), p.tokens...)
+			break
+		}
+	}
+
 	// Entries of the guardList are separated by diamonds.
 	l := p.splitTokens(scan.Diamond, scan.LeftBrace, scan.RightBrace)
 	body := make(guardList, len(l))
 	for i := range l {
-		q := &parser{a: p.a, tokens: l[i]}
+		q := &parser{a: p.a, tokens: l[i], nouns: p.nouns}
 		ge, ternary, err := q.guardExpr()
 		if err != nil {
 			return item{}, err
@@ -347,20 +364,29 @@ func (p *parser) parseLambda() (item, error) {
 			body = body[:len(body)-1]
 		}
 	}
-	return item{e: &lambda{body}, class: verb}, nil
+	return item{e: &lambda{body: body, tokens: tokens}, class: verb}, nil
 }
 
 // GuardExpr parses a guarded expression, which is part of a lambda expression.
 //	cond:expr
 //	cond:expr:expr2 (short ternary form, only for the last in the list).
+//	numbers::expr (error guard).
 func (p *parser) guardExpr() (*guardExpr, expr, error) {
 	l := p.splitTokens(scan.Colon, scan.LeftBrace, scan.RightBrace)
 	if len(l) > 3 {
 		return nil, nil, fmt.Errorf("lambda has too many colons")
 	}
 	ge := &guardExpr{}
+	if len(l) == 3 && len(l[1]) == 0 {
+		// An error guard has an empty middle part.
+		ge.trap = true
+		l = [][]scan.Token{l[0], l[2]}
+		if len(l[0]) == 0 || len(l[1]) == 0 {
+			return nil, nil, fmt.Errorf("lambda: error guard needs error numbers and an expression")
+		}
+	}
 	for i := range l {
-		q := &parser{a: p.a, tokens: l[i]}
+		q := &parser{a: p.a, tokens: l[i], nouns: p.nouns}
 		item, err := q.parseStatement()
 		if err != nil {
 			return nil, nil, err
@@ -377,6 +403,15 @@ func (p *parser) guardExpr() (*guardExpr, expr, error) {
 	return ge, nil, nil
 }
 
+// isVarname is the parser's version of isVarname.
+// Operands of user defined operators may be parsed as arrays, see lambdaOp.
+func (p *parser) isVarname(s string) (ok, isfunc bool) {
+	if p.nouns[s] {
+		return true, false
+	}
+	return isVarname(s)
+}
+
 // collectArray pulls tokens from the parser that form an array starting with the given right end token.
 //
 // TODO: this is not correct: Vector binding is not stronger than right operand binding
@@ -420,7 +455,7 @@ loop:
 			}
 
 		case scan.Identifier:
-			if ok, fok := isVarname(t.S); ok == false || fok == true {
+			if ok, fok := p.isVarname(t.S); ok == false || fok == true {
 				break loop
 			}
 			ar = append(ar, numVar{t.S})
@@ -777,6 +812,17 @@ func (p *parser) reduceff(last bool) bool {
 	return false
 }
 
+// IsAssignTarget returns true, if the left item on the stack is an assignment
+// without a target.
+// It is used to assign to user defined operators, which are otherwise parsed as operators.
+func (p *parser) isAssignTarget() bool {
+	if len(p.stack) < 1 {
+		return false
+	}
+	d, ok := p.leftItem(0).e.(*derived)
+	return ok && d.op == "←" && d.lo == nil
+}
+
 // SpecialJot converts the item from a dyadic operator to a primitive function,
 // if it follows a dot.
 // This special case is applied for ∘ only, which is registered as a DOP,
@@ -0,0 +1,245 @@
+package apl
+
+import (
+	"fmt"
+)
+
+// Pick returns the item of R at the path L, L⊃R.
+//
+// The path is a List with one entry for each level of depth.
+// A simple scalar or a vector is also accepted as a path.
+// At each level, the entry selects:
+//	- a List element by it's index
+//	- an Object value by it's key
+//	- a Table column by it's key or a row by it's index, which is returned as a Dict
+//	- an array element by it's index, or by an index vector for arrays of higher rank.
+// Example:
+//	(2;`key;3;)⊃X
+func (a *Apl) Pick(L, R Value) (Value, error) {
+	path, err := pickPath(L)
+	if err != nil {
+		return nil, err
+	}
+	v := R
+	for len(path) > 0 {
+		v, path, err = a.pickStep(v, path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// Reach replaces the item of R at the path L.
+// It is used by reach indexed assignment:
+//	((2;`key;3;)⊃X)←V
+// The function f is called with the current item and returns it's replacement.
+// R is modified in place, if possible.
+// Reach returns the modified value, which must be assigned to the variable.
+func (a *Apl) Reach(L, R Value, f func(Value) (Value, error)) (Value, error) {
+	path, err := pickPath(L)
+	if err != nil {
+		return nil, err
+	}
+	return a.reach(R, path, f)
+}
+
+// pickPath converts the left argument of pick to a list of path entries.
+func pickPath(L Value) ([]Value, error) {
+	switch l := L.(type) {
+	case List:
+		return []Value(l), nil
+	case EmptyArray:
+		return nil, nil
+	case Array:
+		if s := l.Shape(); len(s) != 1 {
+			return nil, fmt.Errorf("pick: path must be a vector or a list")
+		}
+		p := make([]Value, l.Size())
+		for i := range p {
+			p[i] = l.At(i)
+		}
+		return p, nil
+	case Object:
+		return nil, fmt.Errorf("pick: path cannot be an object: %T", L)
+	}
+	return []Value{L}, nil
+}
+
+// pickIndex converts a path entry to an index into a dimension of length n.
+// Negative indexes count from the end.
+func (a *Apl) pickIndex(v Value, n int) (int, error) {
+	num, ok := v.(Number)
+	if ok == false {
+		return 0, fmt.Errorf("pick: index must be a number: %T", v)
+	}
+	i, ok := num.ToIndex()
+	if ok == false {
+		return 0, fmt.Errorf("pick: index must be an integer: %s", v.String(a))
+	}
+	if i < 0 {
+		i += n + a.Origin
+	}
+	i -= a.Origin
+	if i < 0 || i >= n {
+		return 0, Errorf(IndexError, "pick: index out of range")
+	}
+	return i, nil
+}
+
+// arrayIndex converts a path entry to the flat index of an array element.
+// The entry is a scalar for a vector or an index vector with one value for each axis.
+func (a *Apl) arrayIndex(ar Array, v Value) (int, error) {
+	shape := ar.Shape()
+	var idx []Value
+	if iv, ok := v.(Array); ok {
+		if s := iv.Shape(); len(s) != 1 {
+			return 0, fmt.Errorf("pick: array index must be a vector")
+		}
+		idx = make([]Value, iv.Size())
+		for i := range idx {
+			idx[i] = iv.At(i)
+		}
+	} else {
+		idx = []Value{v}
+	}
+	if len(idx) != len(shape) {
+		return 0, Errorf(RankError, "pick: rank error: index has %d values for array of rank %d", len(idx), len(shape))
+	}
+	n := 0
+	for i := range idx {
+		k, err := a.pickIndex(idx[i], shape[i])
+		if err != nil {
+			return 0, err
+		}
+		n = n*shape[i] + k
+	}
+	return n, nil
+}
+
+// pickStep picks a single level from v and returns the remaining path.
+// Picking a row from a table consumes the next path entry, if it exists.
+func (a *Apl) pickStep(v Value, path []Value) (Value, []Value, error) {
+	p := path[0]
+	switch x := v.(type) {
+	case List:
+		i, err := a.pickIndex(p, len(x))
+		if err != nil {
+			return nil, nil, err
+		}
+		return x[i], path[1:], nil
+	case Table:
+		if col := x.At(a, p); col != nil {
+			return col, path[1:], nil
+		}
+		row, err := a.pickIndex(p, x.Rows)
+		if err != nil {
+			return nil, nil, Errorf(IndexError, "pick: table has no column %s and %s", p.String(a), err)
+		}
+		if len(path) > 1 {
+			col := x.At(a, path[1])
+			if col == nil {
+				return nil, nil, Errorf(IndexError, "pick: table column does not exist: %s", path[1].String(a))
+			}
+			return col.(Array).At(row), path[2:], nil
+		}
+		d := Dict{K: make([]Value, len(x.K)), M: make(map[Value]Value)}
+		for i, k := range x.K {
+			d.K[i] = k
+			d.M[k] = x.M[k].(Array).At(row)
+		}
+		return &d, nil, nil
+	case Object:
+		if y := x.At(a, p); y != nil {
+			return y, path[1:], nil
+		}
+		return nil, nil, Errorf(IndexError, "pick: key does not exist: %s", p.String(a))
+	case Array:
+		i, err := a.arrayIndex(x, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return x.At(i), path[1:], nil
+	}
+	return nil, nil, fmt.Errorf("pick: cannot pick from %T", v)
+}
+
+// reach replaces the value at the path in v.
+func (a *Apl) reach(v Value, path []Value, f func(Value) (Value, error)) (Value, error) {
+	if len(path) == 0 {
+		return f(v)
+	}
+	p := path[0]
+	switch x := v.(type) {
+	case List:
+		i, err := a.pickIndex(p, len(x))
+		if err != nil {
+			return nil, err
+		}
+		y, err := a.reach(x[i], path[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		x[i] = y
+		return x, nil
+	case Table:
+		if x.At(a, p) == nil {
+			// Row index: swap with the column key.
+			if len(path) < 2 {
+				return nil, fmt.Errorf("reach assignment: a table row needs a column key")
+			}
+			path = append([]Value{path[1], p}, path[2:]...)
+			p = path[0]
+		}
+		col, ok := x.At(a, p).(Array)
+		if ok == false {
+			return nil, Errorf(IndexError, "reach assignment: table column does not exist: %s", p.String(a))
+		}
+		y, err := a.reach(col, path[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		if ar, ok := y.(Array); ok == false || len(ar.Shape()) != 1 || ar.Size() != x.Rows {
+			return nil, fmt.Errorf("reach assignment: table column must be a vector with %d rows", x.Rows)
+		}
+		x.M[p] = y
+		for _, k := range x.Sorted {
+			if k == p {
+				x.Sorted = nil // The column may not be sorted any more.
+				break
+			}
+		}
+		return x, nil
+	case Object:
+		y, err := a.reach(x.At(a, p), path[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		return x, x.Set(a, p, y)
+	case Array:
+		i, err := a.arrayIndex(x, p)
+		if err != nil {
+			return nil, err
+		}
+		y, err := a.reach(x.At(i), path[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := y.(Array); ok {
+			return nil, fmt.Errorf("reach assignment: cannot assign an array to an array element")
+		}
+		if s, ok := x.(ArraySetter); ok {
+			if err := s.Set(i, y); err == nil {
+				return s, nil
+			}
+		}
+		// Upgrade to a mixed array.
+		m := MixedArray{Dims: CopyShape(x), Values: make([]Value, x.Size())}
+		for k := range m.Values {
+			m.Values[k] = x.At(k)
+		}
+		m.Values[i] = y
+		return m, nil
+	}
+	return nil, fmt.Errorf("reach assignment: cannot reach into %T", v)
+}
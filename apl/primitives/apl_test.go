@@ -33,6 +33,13 @@ var testCases = []struct {
 	{"1a90", "0J1", float}, // a complex number
 	{"1a60+1a300", "1J0", float},
 	{"1J1", "1J1", float},
+	{"9223372036854775807+1", "9.22337E+18", small},                          // integer overflow promotes to float
+	{"9223372036854775807+⍳2", "9.22337E+18 9.22337E+18", small},             // not 0N ¯9223372036854775807
+	{"¯9223372036854775807-1 0", "¯9.22337E+18 ¯9223372036854775807", small}, // ¯9223372036854775808 is 0N
+	{"4294967296×4294967296", "1.84467E+19", small},
+	{"×/4294967296 4294967296", "1.84467E+19", small},
+	{"+/9223372036854775807 1 ¯1", "9223372036854775807", small}, // reduces from the right
+	{"+/¯1 9223372036854775807 1", "9.22337E+18", small},
 
 	{"⍝ Vectors", "", 0},
 	{"1 2 3", "1 2 3", 0},
@@ -61,7 +68,25 @@ var testCases = []struct {
 	{"2×1 2 3=4 2 1", "0 2 0", 0},             // dyadic array
 	{"-3<4", "¯1", 0},                         // monadic scalar
 	{"-1 2 3=0 2 3", "0 ¯1 ¯1", 0},            // monadic array
-	{"⍝ TODO Comparison tolerance is not implemented.", "", 0},
+	{"⎕CT", "1E¯14", float},                   // default comparison tolerance
+	{"1=1+1E¯15", "1", float},                 // tolerant equality
+	{"1≠1+1E¯13", "1", float},                 // outside of tolerance
+	{"⎕CT←0 ⋄ 1=1+1E¯15", "0", float},         // exact comparison
+	{"(0.1+0.2)=0.3", "1", float},             // tolerant float
+	{"0.3=0.1+0.2J0", "1", float},             // tolerant complex
+	{"1 2 3≡1 2 3+1E¯15", "1", float},         // match is tolerant
+	{"(0.1+0.2)⍳0.3", "1", float},             // index of is tolerant
+	{"0.3∊0.1+0.2", "1", float},               // membership is tolerant
+	{"∪0.3,(0.1+0.2),0.4", "0.3 0.4", float},  // unique is tolerant
+	{"⌊3×1÷3", "1", float},                    // tolerant floor
+	{"⌈¯3×1÷3", "¯1", float},                  // tolerant ceil
+	{"(0.3<0.1+0.2),(0.1+0.2)>0.3", "0 0", float},
+	{"⎕CT←0 ⋄ (0.3<0.1+0.2),(0.1+0.2)>0.3", "1 1", small},
+	{"(0.3 0.3<0.1+0.2),(0.1+0.2)>0.3 0.3", "0 0 0 0", float},
+	{"⌊1-1E¯15 ⋄ ⎕CT←0 ⋄ ⌊1-1E¯15", "1\n0", float},
+	{"⎕CT←1E¯3 ⋄ 1=1.0001", "1", float},       // set tolerance
+	{"⎕CT←¯1", "fail: CT is out of range", 0}, // negative tolerance
+	{"3=3", "1", 0},                           // integers are always exact
 
 	{"⍝ Boolean, logical", "apl/primitives/boolean.go", 0},
 	{"0 1 0 1 ^ 0 0 1 1", "0 0 0 1", 0}, // and
@@ -74,6 +99,9 @@ var testCases = []struct {
 	{"~0", "1", 0},                      // scalar not
 	{"~1.0", "0", 0},                    // scalar not
 	{"~0 1", "1 0", 0},                  // array not
+	{"B←0=3|⍳70⋄+/B⋄+/~B", "23\n47", 0},
+	{"B←0=3|⍳70⋄(∧/B∨~B),∨/B∧~B", "1 0", 0},
+	{"+/2 65⍴0=3|⍳130", "21 22", 0},
 
 	{"⍝ Least common multiple, greatest common divisor", "apl/primitives/boolean.go", 0},
 	{"30^36", "180", small},                     // lcm
@@ -151,9 +179,16 @@ var testCases = []struct {
 	{"⍸1 0 1 0 0 0 0 1 0", "1 3 8", 0},
 	{"⍸'e'='Pete'", "2 4", 0},
 	{"⍸1=1", "1", 0},
+	{"⍸0=3|⍳70", "3 6 9 12 15 18 21 24 27 30 33 36 39 42 45 48 51 54 57 60 63 66 69", 0},
 	{"10 20 30⍸11 1 31 21", "1 0 3 2", 0},
 	{"'AEIOU'⍸'DYALOG'", "1 5 1 3 4 2", 0},
 	{"0.8 2 3.3⍸1.3 1.9 0.7 4 .6 3.2", "1 1 0 3 0 2", 0},
+	{"10 20 20 30⍸19 20 21", "1 3 3", 0},
+	{"30 20⍸⍳3", "fail: intervalindex: values of left argument must be increasing", 0},
+	{"T←⍉`S`T#(`a`b`c ;1 5 9;)⋄T⍸[`T]0 1 6 10", "0 1 2 3", 0}, // interval index on a table column
+	{"T←⍉`T#(,3 1 2)⋄T⍸[`T]1", "fail: intervalindex: column T: values of left argument must be increasing", 0},
+	{"T←⍉`T#(,3 1 2)⋄T←⍋[`T]T⋄T⍸[`T]2.5", "2", 0}, // sorted table
+	{"T←⍉`T#(,3 1 2)⋄T←⍋[`T]T⋄T[1;`T]←5⋄T⍸[`T]2", "fail: intervalindex: column T: values of left argument must be increasing", 0},
 
 	{"⍝ Membership", "apl/primitives/iota.go", 0},
 	{"'BANANA'∊'AN'", "0 1 1 1 1 1", 0},
@@ -423,6 +458,8 @@ var testCases = []struct {
 	{"1 1 0 0 1/'STRAY'", "S T Y", 0},
 	{"1 0 1 0/3 4⍴⍳12", "1 3\n5 7\n9 11", 0},
 	{"1 0 1/1 2 3", "1 3", 0},
+	{"+/(0=3|⍳70)/⍳70", "828", 0},
+	{"A←⍳70⋄((0=7|A)/A)←0⋄+/A", "2100", 0},
 	{"1/1 2 3", "1 2 3", 0},
 	{"3 2 1/1 2 3", "1 1 1 2 2 3", 0},
 	{"1 0 1/2", "2 2", 0},
@@ -492,7 +529,7 @@ var testCases = []struct {
 	{"¯5○1.175201194", "1", small},
 	{"¯6○1.543080635", "1", small},
 
-	{"⍝ Take, drop", "apl/primitives/take.go", 0}, // Monadic First and split are not implemented.
+	{"⍝ Take, drop", "apl/primitives/take.go", 0}, // Monadic split is not implemented.
 	{"5↑'ABCDEF'", "A B C D E", 0},
 	{"5↑1 2 3", "1 2 3 0 0", 0},
 	{"¯5↑1 2 3", "0 0 1 2 3", 0},
@@ -552,19 +589,56 @@ var testCases = []struct {
 
 	{"⍝ Format as a string, Execute", "apl/primitives/format.go", 0},
 
-	{"⍕10", "10", 0},                                  // format as string
-	{"⍕10.1", "10.1", small},                          // format as string
-	{"⍕123.45678901234", "123.457", small},            // format as string
-	{"4⍕123.45678901234", "123.5", small},             // format with precision
-	{"`%.3f@%.1f ⍕1J2", "2.236@63.4", small},          // format with string
-	{"`%.3f ⍕¯1.23456", "¯1.235", small},              // format with string
-	{"`-%.3f ⍕¯1.23456", "-1.235", small},             // format with string (normal minus sign)
-	{`⍕"alpha"`, `alpha`, 0},                          // format with default stringer
-	{`¯1⍕"alpha"`, `"alpha"`, 0},                      // format with text marshaler
-	{`¯1⍕"al\npha"`, `"al\npha"`, 0},                  // format with text marshaler
-	{"`csv ⍕2 3⍴⍳6", "1,2,3\n4,5,6", 0},               // format as csv
-	{"`csv ⍕2 2⍴`a`b`c\"t`d", "a,b\n\"c\"\"t\",d", 0}, // format as csv
-	{`⍎"1+1"`, "2", 0},                                // evaluate expression
+	{"⍕10", "10", 0},                                     // format as string
+	{"⍕10.1", "10.1", small},                             // format as string
+	{"⍕123.45678901234", "123.457", small},               // format as string
+	{"4⍕123.45678901234", "123.5", small},                // format with precision
+	{"`%.3f@%.1f ⍕1J2", "2.236@63.4", small},             // format with string
+	{"`%.3f ⍕¯1.23456", "¯1.235", small},                 // format with string
+	{"`-%.3f ⍕¯1.23456", "-1.235", small},                // format with string (normal minus sign)
+	{`⍕"alpha"`, `alpha`, 0},                             // format with default stringer
+	{`¯1⍕"alpha"`, `"alpha"`, 0},                         // format with text marshaler
+	{`¯1⍕"al\npha"`, `"al\npha"`, 0},                     // format with text marshaler
+	{"`csv ⍕2 3⍴⍳6", "1,2,3\n4,5,6", 0},                  // format as csv
+	{"`csv ⍕2 2⍴`a`b`c\"t`d", "a,b\n\"c\"\"t\",d", 0},    // format as csv
+	{`⍎"1+1"`, "2", 0},                                   // evaluate expression
+	{"¯1⍕,5", "[5]", 0},                                  // data format, vector with one element
+	{"¯1⍕1 3⍴⍳3", "[[1 2 3]]", 0},                        // data format, leading axis of length 1
+	{"¯1⍕⍬", "[]", 0},                                    // data format, empty array
+	{"¯1⍕(1;2 2⍴⍳4;\"a\";)", `(1;[[1 2][3 4]];"a";)`, 0}, // data format, list
+	{"¯1⍕1e21 1.5e¯30", "1e21 1.5e¯30", small},           // data format, floats
+	{`⍴"A"⍎"[5]"`, "1", 0},                               // parse data, vector
+	{"X←(1;2 3;)⋄X≡\"A\"⍎¯1⍕X", "1", 0},                  // parse data, list
+	{"D←`a`b#(1 2;(3;\"x\";);)⋄D≡\"D\"⍎¯1⍕D", "1", 0},    // parse data, dict
+	{"T←⍉`A`B#(1 2;\"x\" \"y\";)⋄T≡\"T\"⍎¯1⍕T", "1", 0},  // parse data, table
+	{"\"T\"⍎\"\\\"A\\\" \\\"B\\\"\\n1 2\\n3\"", "fail: row 2 has 1 values", 0},
+	{"D←`a`b#(1 2;`c#3;)⋄¯1⍕D", "\"a\": 1 2\n\"b\": {\"c\": 3;}", 0},
+	{"D←`a`b#(1 2;`c#3;)⋄D≡\"D\"⍎¯1⍕D", "1", 0},
+	{"L←(1;⍉`a`b#1 2;)⋄¯1⍕L", "(1;{\"a\" \"b\"| 1 2;};)", 0},
+	{"L←(1;⍉`a`b#1 2;)⋄L≡\"A\"⍎¯1⍕L", "1", 0},
+	{"T←⍉`a`b#(1 2;(`x#1;`x#2;);)⋄T≡\"T\"⍎¯1⍕T", "1", 0},
+	{"\"D\"⍎\"\\\"a\\\": {\\\"b\\\": 1}\"", "fail: dict: value of b is not terminated", 0},
+	{`X←"json"⍎"{\"A\":1,\"B\":[1,2.5],\"C\":[\"x\",2]}"⋄X→B⋄⍴X→C`, "1 2.5\n2", small},     // parse json
+	{`X←2 3⍴⍳6⋄X≡"json"⍎¯2⍕X`, "1", 0},                                                     // parse json matrix
+	{`"json"⍎"null"`, "0N", 0},                                                             // json null
+	{`⍰"json"⍎"[\"a\",null]"`, "0 1", 0},                                                   // json null string
+	{`"json"⍎"[1.5,null]"`, "1.5 0n", small},                                               // json null float
+	{`T←"jsontable"⍎"[{\"N\":\"a\",\"Q\":1},{\"N\":\"b\",\"Q\":2}]"⋄T→Q`, "1 2", 0},        // parse json table
+	{`"json"⍎"[1,2"`, "fail: parse json: unexpected EOF", 0},                               // parse json
+	{`+/"json"⍎⍕¨go→source 4`, "6", 0},                                                     // parse json lines
+	{`T←"csv"⍎"A,B,C\n1,x,1.5\n,y,2"⋄T→A⋄T→C`, "1 0N\n1.5 2", small},                       // parse csv
+	{`T←"csv"⍎"A,B\n\"1,5\",-2\n\"x\"\"\",3"⋄T→A⋄+/T→B`, "1,5 x\"\n1", 0},                  // parse csv, quoted fields
+	{"T←(`B#0.5)⍎[\";\"]\"A;B\\n1;2\"⋄T→B⋄⍰T→A", "2\n0", small},                            // parse csv, delimiter and prototype
+	{"P←⍉`A`B#(`x ;0.5;)⋄T←P⍎[\",\"]\"A,B\\n1,2\"⋄T→B⋄⍰T→A", "2\n0", small},                // parse csv, table prototype
+	{"T←(`A`B#(\"\";0;))⍎[`header#0]\"1,2\\n3,4\"⋄T→A⋄+/T→B", "1 3\n6", 0},                 // parse csv without header
+	{"O←`delimiter`header#(\";\";0;)⋄T←(`A`B#(\"\";0;))⍎[O]\"1;2\"⋄T→B", "2", 0},           // parse csv, options
+	{`≢"csv"⍎[","]⍕¨go→source 4`, "3", 0},                                                  // parse csv channel
+	{`"csv"⍎"A,B\n1,2,3"`, "fail: parse csv: record on line 2: wrong number of fields", 0}, // parse csv
+	{"(`B#0)⍎[\",\"]\"A,B\\n1,2.5\"", "fail: parse csv: column B: row 1: cannot convert", 0},
+	{"P←⍉`A`B#(`x ;0.5;)⋄P⍎[\",\"]\"A\\n1\"", "fail: parse csv: column is missing: B", 0},
+	{`"csv"⍎[";;"]"A"`, "fail: parse csv: delimiter must be a single character: ;;", 0},
+	{"\"csv\"⍎[`x#1]\"A\"", "fail: parse csv: unknown option: x", 0},
+	{`T←"csv"⍎"A\n1\n2"⋄T≡"csv"⍎"csv"⍕T`, "1", 0}, // csv round trip
 	{"⍝ TODO: dyadic format with specification.", "", 0},
 	{"⍝ TODO: dyadic execute with namespace.", "", 0},
 
@@ -576,7 +650,19 @@ var testCases = []struct {
 	{"⍒33 11 44 66 22", "4 3 1 5 2", 0},                             // grade down
 	{"⍋'alpha'", "1 5 4 2 3", 0},                                    // strings grade up
 	{"'ABCDE'⍒'BEAD'", "2 4 1 3", 0},                                // grade down with collating sequence
-	{"⍝ TODO dyadic grade up/down is only implemented for vector L", "", 0},
+	{"(2 3⍴'ABCabc')⍋'cBxaA'", "5 4 2 1 3", 0},                      // grade with collating array
+	{"(2 3⍴'ABCabc')⍋4 2⍴'abAbaBAB'", "4 2 3 1", 0},                 // collating array, case is the second key
+	{"(2 3⍴'ABCabc')⍒4 2⍴'abAbaBAB'", "1 3 2 4", 0},                 // grade down with collating array
+	{"T←⍉`S`D#(`b`a`b`a ;2 2 1 1;)⋄⍋T", "4 2 3 1", 0},               // grade table by all columns
+	{"T←⍉`S`D#(`b`a`b`a ;2 2 1 1;)⋄⍒T", "1 3 2 4", 0},               // grade down table
+	{"T←⍉`S`D#(`b`a`b`a ;2 2 1 1;)⋄`D ⍋T", "3 4 1 2", 0},            // grade by a column, stable
+	{"T←⍉`S`D#(`b`a`b`a ;2 2 1 1;)⋄(`S`D#1 ¯1)⍋T", "2 4 1 3", 0},    // mixed directions
+	{"T←⍉`S`D#(`b`a`b`a ;2 2 1 1;)⋄(`S`D#1 ¯1)⍒T", "3 1 4 2", 0},    // grade down reverses all directions
+	{"T←⍉`S`D#(`b`a ;2 1;)⋄T[`D ⍋T]", "S D\na 1\nb 2", 0},           // sort table rows
+	{"T←⍉`S`D#(`b`a ;2 1;)⋄⍋[`S]T", "S D\na 1\nb 2", 0},             // sort table
+	{"T←⍉`S`D#(`b`a ;2 1;)⋄⍒[`S]T", "S D\nb 2\na 1", 0},             // sort table descending
+	{"T←⍉`S`D#(`b`a ;2 1;)⋄`X ⍋T", "fail: grade table: column does not exist: X", 0},
+	{"T←⍉`S`D#(`b`a ;2 1;)⋄(`S`D#1 0)⍋T", "fail: grade table: direction must be 1 or ¯1: 0", 0},
 	{"A←23 11 13 31 12⋄A[⍋A]", "11 12 13 23 31", 0}, // sort
 
 	{"⍝ Reverse, revere first", "apl/primitives/reverse.go", 0},
@@ -628,10 +714,27 @@ var testCases = []struct {
 	{`⍴','⊃",a,,b,c"`, "5", 0},
 	{`⍴""⊃" a  b c\tc "`, "4", 0},
 
+	{"⍝ Partition, partitioned enclose", "apl/primitives/partition.go", 0},
+	{"1 1 2 2 0 3⊆⍳6", "(1 2;3 4;6;)", 0},
+	{"1 1 0 1 1⊆⍳5", "(1 2;4 5;)", 0},
+	{"2 2 1 1⊆⍳4", "(1 2 3 4;)", 0},
+	{"0⊆⍳3", "()", 0},
+	{"1 0 1 0 0 1⊂⍳6", "(1 2;3 4 5;6;)", 0},
+	{"0 1 0 1⊂'abcd'", "(b c;d;)", 0},
+	{"1 0 2⊂⍳3", "(1 2;;3;)", 0},
+	{"1⊂⍳3", "(1;2;3;)", 0},
+	{"1 0 1⊆`a`b`c", "(a;c;)", 0},
+	{"⍴¨1 0 1⊂[1]3 2⍴⍳6", "(2 2;1 2;)", 0},
+	{"2⊃1 0 1⊂[1]3 2⍴⍳6", "5 6", 0},
+	{"⍴¨1 1 0⊆[2]2 3⍴⍳6", "(2 2;)", 0},
+	{"≢¨1 0 1⊂(1;2;`x ;)", "(2;1;)", 0},
+	{"1 0⊆⍳3", "fail: partition: length error", 0},
+	{"1 ¯1 1⊆⍳3", "fail: partition: left argument must not be negative", 0},
+	{"1 0 1⊂[3]2 3⍴⍳6", "fail: partitioned enclose: axis out of range", 0},
+
 	{"⍝ Domino, solve linear system", "apl/primitives/domino.go", 0},
 	{"⌹2 2⍴2 0 0 1", "0.5 0\n0 1", small},
-	// TODO: this fails for big.Float. Remove sfloat and debug
-	{"(1 ¯2 0)⌹3 3⍴3 2 ¯1 2 ¯2 4 ¯1 .5 ¯1", "1\n¯2\n¯2", small},
+	{"(1 ¯2 0)⌹3 3⍴3 2 ¯1 2 ¯2 4 ¯1 .5 ¯1", "1\n¯2\n¯2", 0},
 	// A←2a30
 	// B←1a10
 	// RHS←A+B**(¯1+⍳6)×○1÷3
@@ -714,6 +817,42 @@ var testCases = []struct {
 	{"1 2 3+¨1", "2 3 4", 0},     // dyadic each
 	{"1 2 3+¨4 5 6", "5 7 9", 0}, // dyadic each
 	{"1+¨1", "2", 0},             // dyadic each
+	{"⎕PAR←4⋄{X←⍵⋄X×X}¨⍳10", "1 4 9 16 25 36 49 64 81 100", 0},
+	{"⎕PAR←4⋄1 2 3{⍺+⍵}¨4 5 6", "5 7 9", 0},
+	{"⎕PAR←4⋄{⍵=7:⍵+'a'⋄⍵}¨⍳100", "fail: right argument is not a numeric type", 0},
+	{"⎕PAR←3⋄⎕PAR", "3", 0},
+	{"⎕PAR←¯1", "fail: ⎕PAR must be a non-negative integer", 0},
+
+	{"⍝ Fill elements, prototypes and fill functions", "apl/fill.go", 0},
+	{"⍴⍬", "0", 0},
+	{"⍬≡⍳0", "1", 0},
+	{"⍬≡0⍴1", "1", 0},
+	{"⍬≡0↑`a`b", "0", 0},
+	{"⍬←1", "fail: cannot assign to ⍬", 0},
+	{"5↑1 2", "1 2 0 0 0", 0},
+	{"\"\"≡5⊃5↑`a`b", "1", 0},
+	{`(5↑"a" "b")≡"a" "b" "" "" ""`, "1", 0},
+	{"¯2↑⍬", "0 0", 0},
+	{"\"\"≡1⊃3↑0↑`a`b", "1", 0},
+	{"\"\"≡1⊃3↑0⍴`a", "1", 0},
+	{"\"\"≡3⊃3↑1↓`a", "1", 0},
+	{"⍴0 2⍴`a", "0 2", 0},
+	{"0≡1⊃2↑0⍴1.5", "1", 0},
+	{"\"\"≡2⊃1 ¯1 1/`a`b`c", "1", 0},
+	{"⍴-¨⍬", "0", 0},
+	{"(-¨⍬)≡⍬", "1", 0},
+	{"⍴{⍵,⍵}¨0 3⍴1", "0 3", 0},
+	{"(÷¨0⍴1)≡⍬", "1", 0},
+	{"⍬≡1+¨⍬", "1", 0},
+	{"(0⍴`a`b)≡{⍵}¨0⍴`a`b", "1", 0},
+	{"+/0⍴1", "0", 0},
+	{"×/0⍴1", "1", 0},
+	{"⍴+/3 0⍴1", "3", 0},
+	{"+/3 0⍴1", "0 0 0", 0},
+	{"⍴×⌿0 2 3⍴1", "2 3", 0},
+	{"1+.×⍬", "0", 0},
+	{"⍬+.×⍬", "0", 0},
+	{"(2 0⍴1)+.×0 3⍴1", "0 0 0\n0 0 0", 0},
 
 	{"⍝ Commute, duplicate", "apl/operators/commute.go", 0},
 	{"∘.≤⍨1 2 3", "1 1 1\n0 1 1\n0 0 1", 0},
@@ -731,10 +870,45 @@ var testCases = []struct {
 
 	{"⍝ Power operator", "apl/operators/power.go", 0},
 	{"⍟⍣2 +2 3 4", "¯0.366513 0.0940478 0.326634", float}, // log log
-	// TODO: 1+∘÷⍣=1 oscillates for big.Float.
-	// TODO: Add comparison tolerance and remove sfloat.
-	{"1+∘÷⍣=1", "1.61803", small}, // fixed point iteration golden ratio
-	{"⍝ TODO: function inverse", "", 0},
+	{"1+∘÷⍣=1", "1.61803", float},                         // fixed point iteration golden ratio
+	{"3+⍣¯1+10", "7", 0},                                  // inverse with bound left argument
+	{"-⍣¯1⊢5", "¯5", 0},                                   // function inverse
+	{"2×⍣¯1⊢8", "4", 0},                                   // inverse multiply
+	{"2÷⍣¯1⊢4", "0.5", float},                             // inverse divide
+	{"*⍣¯1⊢*2", "2", float},                               // inverse exponential
+	{"10⍟⍣¯1⊢2", "100", 0},                                // inverse logarithm
+	{"○⍣¯1○1", "1", small},                                // inverse pi times
+	{"1○⍣¯1⊢1○0.5", "0.5", small},                         // inverse sin
+	{"2 2 2⊤⍣¯1⊢1 0 1", "5", 0},                           // inverse encode
+	{"2 2 2⊥⍣¯1⊢5", "1 0 1", 0},                           // inverse decode
+	{"⍴⍉⍣¯1⊢2 3⍴⍳6", "3 2", 0},                            // inverse transpose
+	{"R←2 3 4⍴⍳24⋄R≡2 3 1⍉2 3 1⍉⍣¯1⊢R", "1", 0},           // inverse dyadic transpose
+	{"1⌽⍣¯1⊢1 2 3 4", "4 1 2 3", 0},                       // inverse rotate
+	{"1 2,⍣¯1⊢1 2 3 4", "3 4", 0},                         // inverse catenate
+	{"(2 2⍴1),⍣¯1⊢2 3⍴⍳6", "3\n6", 0},                     // inverse catenate matrix
+	{"(+∘1)⍣¯1⊢5", "4", 0},                                // inverse f∘B
+	{"(2∘×)⍣¯1⊢8", "4", 0},                                // inverse A∘g
+	{"(÷∘2)⍣¯1⊢3", "6", 0},                                // inverse f∘B, commuted
+	{"(-∘÷)⍣¯1⊢4", "¯0.25", float},                        // inverse f∘g
+	{"3-⍨⍣¯1⊢5", "8", 0},                                  // inverse commute
+	{"×⍨⍣¯1⊢16", "4", float},                              // inverse square
+	{"(-÷)⍣¯1⊢4", "¯0.25", float},                         // inverse atop
+	{"(1+(2∘×))⍣¯1⊢7", "3", 0},                            // inverse fork
+	{"(1+⊢)⍣¯1⊢7", "6", 0},                                // inverse fork with identity
+	{"(2∘×)⍣¯2⊢16", "4", 0},                               // inverse applied twice
+	{"((2∘×)⍣2)⍣¯1⊢16", "4", 0},                           // inverse of power
+	{"f←2∘×⋄f⍣¯1⊢8", "4", 0},                              // inverse of function variable
+	{"×⍣¯1⊢2", "fail: domain error: monadic × has no inverse", 0},
+	{"{⍵+1}⍣¯1⊢2", "fail: domain error: function has no inverse", 0},
+	{"{⍵}⍨⍣¯1⊢2", "fail: domain error: function has no inverse: {⍵}⍨", 0},
+	{"1 2,⍣¯1⊢2 3⍴⍳6", "2 3\n5 6", 0},
+	{"-⍢÷ 4", "¯4", 0},
+	{"2+⍢(3∘×)4", "6", 0},
+	{"1+⍢⌽1 2 3", "2 3 4", 0},
+	{"⌽⍢, 2 3⍴⍳6", "6 5 4\n3 2 1", 0},
+	{"⍴-⍢, 5", "", 0},
+	{"1↓⍢, 2 3⍴⍳6", "fail: under: inverse of ravel: result has 5 elements, expected 6", 0},
+	{"+⍢{⍵} 1", "fail: domain error: function has no inverse: {⍵}", 0},
 
 	{"⍝ Rank operator", "apl/operators/rank.go", 0},
 	{`+\⍤0 +2 3⍴1`, "1 1 1\n1 1 1", 0},
@@ -744,6 +918,8 @@ var testCases = []struct {
 	{"4 5+⍤1 0 2 +2 2⍴7 8 9 10", "11 12\n13 14\n\n12 13\n14 15", 0},
 	{"⍉2 2 2⊤⍤1 0 ⍳5", "0 0 0 1 1\n0 1 1 0 0\n1 0 1 0 1", 0},
 	{"⍳⍤1 +3 1⍴⍳3", "1 0 0\n1 2 0\n1 2 3", 0},
+	{"⎕PAR←4⋄{+/⍵}⍤1⊢3 4⍴⍳12", "10 26 42", 0},
+	{"⎕PAR←4⋄(⍳3){⍺+⍵}⍤0 1⊢3 2⍴⍳6", "2 3\n5 6\n8 9", 0},
 
 	{"⍝ At", "apl/operators/at.go", 0},
 	{"(10 20@2 4)⍳5", "1 10 3 20 5", 0},
@@ -758,6 +934,33 @@ var testCases = []struct {
 	{"÷@(2∘|)⍳5", "1 2 0.333333 4 0.2", small},
 	{"⌽@(2∘|)⍳5", "5 2 3 4 1", 0},
 
+	{"⍝ Key", "apl/operators/key.go", 0},
+	{"{⍺,≢⍵}⌸1 1 2 3 3 3", "1 2\n2 1\n3 3", 0},
+	{"{≢⍵}⌸`a`b`a`c`a", "3 1 1", 0},
+	{"{⍵}⌸1 1 2", "(1 2;3;)", 0},
+	{"{⍺}⌸1.5 2 1.5", "1.5 2", float},
+	{"`a`b`a {+/⍵}⌸ 1 2 3", "4 2", 0},
+	{"1 2 1 {+⌿⍵}⌸ 3 2⍴⍳6", "6 8\n3 4", 0},
+	{"{≢⍵}⌸ 3 2⍴1 2 3 4 1 2", "2 1", 0},
+	{"{⍺}⌸ 3 2⍴1 2 3 4 1 2", "1 2\n3 4", 0},
+	{"(1;2;1;){⍵}⌸(`x ;`y ;`z ;)", "((x;z;);(y;);)", 0},
+	{"{≢⍵}⌸(1;2 3;1;)", "2 1", 0},
+	{"T←⍉`k`v#(`a`b`a ;1 2 3;)⋄(⍉T)[`k]{+/(⍉⍵)[`v]}⌸T", "4 2", small},
+	{"T←⍉`k`v#(`a`b`a ;1 2 3;)⋄(⍉T)[`k]{≢⍵}⌸T", "2 1", small},
+	{"T←⍉`k`v#(`a`b`a ;1 1 3;)⋄{≢⍵}⌸T", "1 1 1", small},
+	{"1 2 {+/⍵}⌸ 1 2 3", "fail: key: L and R have a different number of major cells", 0},
+	{"{⍺,≢⍵}⌸1.5 2 1.5 0n 0n", "1.5 2\n2 1\n0n 2", small},
+	{"{≢⍵}⌸1.0 2 1 3 3", "2 1 2", 0},
+	{"{≢⍵}⌸1 1.000000000000001 2 0.999999999999999 2", "3 2", float},
+	{"{≢⍵}⌸¯1.5 1.5 0 ¯1.5 0.1 0", "2 1 2 1", float},
+	{"⎕CT←0⋄{≢⍵}⌸1 1.000000000000001 2 0.999999999999999 2", "1 1 2 1", float},
+	{"⎕CT←0.1⋄{≢⍵}⌸1 1.05 1.12 1.2 0n 0n", "2 2 2", float},
+	{"T←⍉`S`Q`P#(`a`b`a`c ;1 2 3 4;1.5 2 2.5 3;)⋄`S (`Q`P#(+/;⌈/;))⌸T", "S Q P\na 4 2.5\nb 2 2\nc 4 3", small},
+	{"T←⍉`S`D`Q#(`a`b`a`b ;1 1 1 2;1 2 3 4;)⋄`S`D ((,`Q)#(+/;))⌸T", "S D Q\na 1 4\nb 1 2\nb 2 4", 0},
+	{"T←⍉`S`Q#(`a`b`a ;1 2 3;)⋄`S (`Q`Q#({≢⍵};+/;))⌸T", "S Q\na 4\nb 2", 0},
+	{"T←⍉`S`Q#(`a`b`a ;1 2 3;)⋄`S (`Q`S#(+/;+/;))⌸T", "fail: group by: cannot aggregate a key column: S", 0},
+	{"T←⍉`S`Q#(`a`b`a ;1 2 3;)⋄`X ((,`Q)#(+/;))⌸T", "fail: group by: key column does not exist: X", 0},
+
 	{"⍝ Stencil", "apl/operators/stencil.go", 0},
 	{"{⌈/⌈/⍵}⌺(3 3) ⊢3 3⍴⍳25", "5 6 6\n8 9 9\n8 9 9", 0},
 
@@ -776,7 +979,43 @@ var testCases = []struct {
 	{"A←2 2⍴⍳4 ⋄ +A[1;1]←3 ⋄ A", "3\n3 2\n3 4", 0},
 	{"A←⍳5 ⋄ A[2 3]←10 ⋄ A", "1 10 10 4 5", 0},
 	{"A←2 3⍴⍳6 ⋄ A[;2 3]←2 2⍴⍳4 ⋄ A", "1 1 2\n4 3 4", 0},
-	{"⍝ TODO: choose/reach indexed assignment", "", 0},
+
+	{"⍝ Reach indexed assignment", "apl/operators/assign.go", 0},
+	{"A←1 2 3 ⋄ (2⊃A)←1.5 ⋄ A", "1 1.5 3", float},
+	{"A←2 2⍴⍳4 ⋄ ((1 2;)⊃A)×←10 ⋄ A", "1 20\n3 4", 0},
+	{"X←(1;(2;`a`b#(10;20 30;);3;);) ⋄ ((2;2;`b ;2;)⊃X)←99 ⋄ (2;2;`b ;)⊃X", "20 99", 0},
+	{"X←(1;(2;`a`b#(10;20 30;);3;);) ⋄ ((2;2;`a ;)⊃X)+←5 ⋄ (2;2;`a ;)⊃X", "15", 0},
+	{"X←(1;(2;`a`b#(10;20 30;);3;);) ⋄ ((2;2;`c ;)⊃X)←7 ⋄ #(2;2;)⊃X", "a b c", 0},
+	{"T←⍉`k`v#(`a`b`a ;1 2 3;) ⋄ ((2;`v ;)⊃T)←20 ⋄ T", "k v\na 1\nb 20\na 3", small},
+	{"T←⍉`k`v#(`a`b`a ;1 2 3;) ⋄ ((`k ;3;)⊃T)←`c ⋄ T", "k v\na 1\nb 2\nc 3", small},
+	{"T←⍉`k`v#(`a`b`a ;1 2 3;) ⋄ (`v ⊃T)←4 5 6 ⋄ T", "k v\na 4\nb 5\na 6", small},
+	{"T←⍉`k`v#(`a`b`a ;1 2 3;) ⋄ (`v ⊃T)←4 5", "fail: reach assignment: table column must be a vector with 3 rows", small},
+	{"X←(1;2;) ⋄ ((3;)⊃X)←1", "fail: pick: index out of range", 0},
+
+	{"⍝ Copy, variables own their values", "apl/value.go", 0},
+	{"A←1 2 3 ⋄ B←A ⋄ B[1]←9 ⋄ A ⋄ B", "1 2 3\n9 2 3", 0},
+	{"A←2 3⍴⍳6 ⋄ B←A ⋄ B[1;1]←9 ⋄ A", "1 2 3\n4 5 6", 0},
+	{"A←`a`b ⋄ B←A ⋄ B[1]←`z ⋄ A", "a b", 0},
+	{"A←1 `a ⋄ B←A ⋄ B[1]←5 ⋄ A", "1 a", 0},
+	{"A←1 2 3 ⋄ B←A,⍬ ⋄ B[1]←9 ⋄ A", "1 2 3", 0},
+	{"A←1 2 3 ⋄ B←⊢A ⋄ B[1]←9 ⋄ A", "1 2 3", 0},
+	{"A←1 2 3 ⋄ B←A ⋄ A[1]←9 ⋄ B", "1 2 3", 0},
+	{"A←1 2 3 ⋄ B←A ⋄ B+←1 ⋄ A", "1 2 3", 0},
+	{"A←1 2 3 ⋄ B←4 5 6 ⋄ A⊢←B ⋄ A[1]←0 ⋄ B", "4 5 6", 0},
+	{"A←1 2 3 ⋄ f←{⍵} ⋄ B←f A ⋄ B[1]←9 ⋄ A", "1 2 3", 0},
+	{"A←1 2 3 ⋄ f←{⍵[1]←9 ⋄ ⍵} ⋄ f A ⋄ A", "9 2 3\n1 2 3", 0},
+	{"A←1 2 3 ⋄ f←{⍺[1]←9 ⋄ ⍺} ⋄ A f 0 ⋄ A", "9 2 3\n1 2 3", 0},
+	{"D←`a`b#1 2 ⋄ E←D ⋄ E[`a]←5 ⋄ D[`a]", "1", 0},
+	{"L←(1;2;) ⋄ M←L ⋄ M[1]←5 ⋄ L", "(1;2;)", 0},
+	{"A←1 2 3 ⋄ L←(A;) ⋄ A[1]←9 ⋄ L", "(1 2 3;)", 0},
+	{"A←1 2 3 ⋄ L←(0;) ⋄ L[1]←A ⋄ A[1]←9 ⋄ L", "(1 2 3;)", 0},
+	{"L←(1;(2;3;);) ⋄ X←L ⋄ ((2;1;)⊃X)←7 ⋄ L", "(1;(2;3;);)", 0},
+	{"T←⍉`k`v#(`a`b ;1 2;) ⋄ U←T ⋄ ((2;`v ;)⊃U)←9 ⋄ T", "k v\na 1\nb 2", small},
+	{"L←(1;2;),3 ⋄ f←{(⍵,4;⍵,5;)} ⋄ f L", "((1;2;3;4;);(1;2;3;5;);)", 0},
+	{"A←1 2 3 ⋄ A[1]←9 ⋄ B←A ⋄ A[2]←8 ⋄ A ⋄ B", "9 8 3\n9 2 3", 0},
+	{"A←1 2 3 ⋄ B←A ⋄ X←{A[⍵]←0}¨⍳3 ⋄ A ⋄ B", "0 0 0\n1 2 3", 0},
+	{"A←1 2 3 ⋄ L←(A;) ⋄ ((1;1;)⊃L)←9 ⋄ A ⋄ L", "1 2 3\n(9 2 3;)", 0},
+	{"D←`a#1 ⋄ E←D ⋄ E[`c]←2 ⋄ D", "a: 1", 0},
 
 	{"⍝ Multiple assignment", "apl/operators/assign.go", 0},
 	{"A←B←C←D←1 ⋄ A B C D", "1 1 1 1", 0},
@@ -825,7 +1064,23 @@ var testCases = []struct {
 	{"A←2 3⍴⍳6 ⋄ (¯2↑[2]A)←2 2⍴10×⍳4 ⋄ A", "1 10 20\n4 30 40", 0},
 	{"A←3 3⍴⍳9 ⋄ (1 1⍉A)←10 20 30 ⋄ A", "10 2 3\n4 20 6\n7 8 30", 0},
 	{"A←3 3⍴'STYPIEANT' ⋄ (⍉A)←3 3⍴⍳9 ⋄ A", "1 4 7\n2 5 8\n3 6 9", 0},
-	{"⍝ TODO: First (↓) and Pick (⊃) are not implemented", "", 0},
+	{"⍝ First, pick", "apl/primitives/pick.go", 0},
+	{"↓5 6 7", "5", 0},
+	{"↓2 2⍴⍳4", "1", 0},
+	{"↓(1 2;3;)", "1 2", 0},
+	{"↓`a`b#4 5", "4", 0},
+	{"↓3", "3", 0},
+	{"T←⍉`k`v#(`a`b`a ;1 2 3;) ⋄ ↓T", "k: a\nv: 1", small},
+	{"2⊃5 6 7", "6", 0},
+	{"¯1⊃5 6 7", "7", 0},
+	{"(2 1;)⊃2 2⍴⍳4", "3", 0},
+	{"X←(1;(2;`a`b#(10;20 30;);3;);) ⋄ (2;2;`b ;2;)⊃X", "30", 0},
+	{"X←(1;(2;`a`b#(10;20 30;);3;);) ⋄ 2 3⊃X", "3", 0},
+	{"T←⍉`k`v#(`a`b`a ;1 2 3;) ⋄ (2;`v ;)⊃T", "2", small},
+	{"T←⍉`k`v#(`a`b`a ;1 2 3;) ⋄ (`v ;3;)⊃T", "3", small},
+	{"T←⍉`k`v#(`a`b`a ;1 2 3;) ⋄ 2⊃T", "k: b\nv: 2", small},
+	{"4⊃5 6 7", "fail: pick: index out of range", 0},
+	{"(1;`x ;)⊃(`a#1;)", "fail: pick: key does not exist: x", 0},
 
 	{"⍝ Lambda expressions", "apl/lambda.go", 0},
 	{"{2×⍵}3", "6", 0},           // lambda in monadic context
@@ -836,6 +1091,19 @@ var testCases = []struct {
 	{"{⍺×⍵}/2 3 4", "24", 0},
 	{"A←1⋄{A+←1⋄A>0:B←A⋄B}0", "2", 0}, // continue if guarded expr is an assignment (differs from Dyalog)
 	{`{1:1+2⋄{1:1+⍵}3}4`, "3", 0},
+	{"{⍵<5:∇⍵+1 ⋄ ⍵}1", "5", 0},
+	{"{⎕←⍵ ⋄ ⍵+1}1", "1\n2", 0}, // unguarded expressions continue
+	{"{g←{⎕←⍵} ⋄ g ⍵ ⋄ g ⍵+1 ⋄ ⍵+2}1", "1\n2\n3", 0},
+	{"{X←⍵ ⋄ ⍵>2:X←10 ⋄ X}3", "10", 0}, // guarded assignments fall through
+	{"{X←⍵ ⋄ ⍵>2:X←10 ⋄ X}1", "1", 0},
+	{"{X←⍵}3", "", 0}, // shy result
+	{"{X←⍵ ⋄ Y←X×2}3 ⋄ Z←{X←⍵ ⋄ Y←X×2}3 ⋄ Z", "6", 0},
+	{"f←{X←⍵} ⋄ f 3 ⋄ 1+f 3", "4", 0},
+	{"{⍵>0:X←1 ⋄ ⍵<0:X←2}5", "", 0},
+	{"{X←⍵}¨1 2", "1 2", 0},
+	{"{⍵}{X←⍵}3", "3", 0},
+	{"_s←{X←⍺⍺ ⍵}⋄-_s 3", "", 0},
+	{"_s←{X←⍺⍺ ⍵}⋄1+-_s 3", "¯2", 0},
 
 	{"⍝ Evaluation order", "apl/function.go", 0},
 	{"A←1⋄A+(A←2)", "4", 0},
@@ -862,6 +1130,50 @@ var testCases = []struct {
 	{"⍝ Tail call", "apl/lambda.go", 0},
 	{"{⍵>1000:⍵⋄∇⍵+1}1", "1001", 0},
 
+	{"⍝ Error guards", "apl/trap.go", 0},
+	{"{0::¯1 ⋄ 1 2 3⍉⍵}2 2⍴1", "¯1", 0},
+	{"{0::¯1 ⋄ ⍵=2:⍵+`a ⋄ ⍵}¨⍳3", "1 ¯1 3", 0},
+	{"{5::⎕EN ⋄ 1 2 3⍉⍵}2 2⍴1", "5", 0},
+	{"{4 5::⎕EN ⋄ 1 2 3⍉⍵}2 2⍴1", "5", 0},
+	{"{4::⎕EN ⋄ 1 2 3⍉⍵}2 2⍴1", "fail: transpose: length of L must be the rank of R", 0},
+	{"{0::⎕EN ⋄ ⍵+`a}1", "11", 0},
+	{"{0::⎕DM ⋄ 1 2 3⍉⍵}2 2⍴1", "LENGTH ERROR transpose: length of L must be the rank of R ((1 2 3) ⍉ ⍵)", 0},
+	{"f←{⍵+`a} ⋄ {0::1↓⎕DM ⋄ f ⍵}1", "+: right argument is not a numeric type apl.String (⍵ + a)", 0},
+	{"{0::1 ⋄ {5::2 ⋄ ⍵+`a}⍵}1", "1", 0},
+	{"{0::1 ⋄ {0::2 ⋄ ⍵+`a}⍵}1", "2", 0},
+	{"{0::1 ⋄ 11::2 ⋄ ⍵+`a}1", "2", 0},
+	{"{0::⎕EN ⋄ ⍵>2:⍵+`a ⋄ ⍵}3", "11", 0},
+	{"{X←⍵+`a ⋄ 0::1 ⋄ X}1", "fail: +: right argument is not a numeric type apl.String", 0},
+	{"{`x::1 ⋄ ⍵}1", "fail: error guard: error numbers must be integers", 0},
+	{"{0::⎕EN ⋄ (⍳3)+⍳4}0", "5", 0},
+	{"{0::⎕EN ⋄ (⍳3)+2 2⍴1}0", "4", 0},
+	{"{0::⎕EN ⋄ 4⊃⍳3}0", "3", 0},
+	{`{0::⎕EN ⋄ ⍎"1+)"}0`, "2", 0},
+	{"{0::⎕EN ⋄ 1 2+¨1 2 3}0", "5", 0},
+	{"{0::⎕EN ⋄ +/[3]2 2⍴1}0", "3", 0},
+	{"{0::⎕EN ⋄ ⎕CT←¯1}0", "11", 0},
+	{"{0::⎕EN ⋄ 1 2 3⍉⍵}2 2⍴1", "5", 0},
+	{"{0::⎕EN ⋄ ⍵}1 ⋄ ⎕EN", "1\n0", 0},
+	{"{0::⍵ ⋄ ⍵+`a}1 ⋄ ⎕EN", "1\n11", 0},
+
+	{"⍝ User defined operators", "apl/lambda.go", 0},
+	{"_twice←{⍺⍺ ⍺⍺ ⍵}⋄-_twice 3", "3", 0},
+	{"_add_←{⍺⍺+⍵⍵+⍵}⋄(1 _add_ 2) 3", "6", 0},
+	{"_add_←{⍺⍺+⍵⍵+⍵}⋄1 2 _add_ 3⊢4", "8 9", 0},
+	{"_c_←{(⍺⍺ ⍵),⍵⍵}⋄(-_c_ 9) 1", "¯1 9", 0},
+	{"_m←{⍺⍺×⍵}⋄(2 _m) 3", "6", 0},
+	{"f←{_i←{⍺⍺ ⍵}⋄-_i ⍵}⋄f 4", "¯4", 0},
+	{"f←{_i←{⍺⍺ ⍵}⋄-_i ⍵}⋄X←f 4⋄-_i 5", "fail: operator _i does not exist", 0},
+	{"_twice←{⍺⍺ ⍺⍺ ⍵}⋄{2×⍵}_twice 3", "12", 0},
+	{"_twice←{⍺⍺ ⍺⍺ ⍵}⋄+/_twice 2 2⍴⍳4", "10", 0},
+	{"_twice←{⍺⍺ ⍺⍺ ⍵}⋄_twice←{⍺⍺ ⍵}⋄-_twice 3", "¯3", 0},
+	{"_sum←{⍵=0:0⋄⍵ ⍺⍺ ∇ ⍵-1}⋄+_sum 4", "10", 0},
+	{"_over_←{(⍵⍵ ⍺)⍺⍺(⍵⍵ ⍵)}⋄3 +_over_| ¯4", "7", 0},
+	{"_c_←{⍺⍺ ⍵⍵ ⍵}⋄-_c_÷ 4", "¯0.25", float},
+	{"_c_←{⍺⍺ ⍵⍵ ⍵}⋄f←-_c_÷⋄f 5", "¯0.2", float},
+	{"_x←1", "fail: only lambda expressions can be assigned to operator _x", 0},
+	{"+_undefined 1", "fail: operator _undefined does not exist", 0},
+
 	{"⍝ Trains, forks, atops", "apl/train.go", 0},
 	{"-,÷ 5", "¯0.2", float},
 	{"(-,÷)5", "¯5 0.2", float},
@@ -929,6 +1241,14 @@ var testCases = []struct {
 	{"D←`alpha#1⋄D[`alpha`beta]←3 4⋄D", "alpha: 3\nbeta: 4", 0},
 	{"D←`a`b`c#1⋄D⋄#D", "a: 1\nb: 1\nc: 1\na b c", 0},
 	{"D←`a`b`c#1 2 3⋄G←D[`a`c]⋄G", "a: 1\nc: 3", 0},
+	{"D←`a`b#1 2⋄D[`a]+←1⋄D", "a: 2\nb: 2", 0},                                                  // modified assignment
+	{"D←`a`b#1 2⋄D[`a`b]×←10 100⋄D", "a: 10\nb: 200", 0},
+	{"D←`a`b#1 2⋄D[`a`b`a]+←1⋄D", "a: 3\nb: 3", 0},                                              // repeated keys
+	{"D←`a`b#1 2⋄D[`a]+←`x ", "fail: assign object: key a: +: right argument is not a numeric type apl.String", 0},
+	{"D←`a`b#1 2⋄D[`c]+←1", "fail: modified assignment: key does not exist: c", 0},
+	{"D←`a`b#1 2⋄D[`a`c]+←1", "fail: modified assignment: key does not exist: c", 0},
+	{"D←`a`b#1 2⋄{0::D ⋄ D[`c]+←1}0", "a: 1\nb: 2", 0},
+	{"D←`a`b#1 2⋄{0::D ⋄ D[`a`b]+←1 `x}0", "a: 1\nb: 2", 0},
 
 	{"⍝ Table, transpose a dict to create a table", "apl/primitives/transpose.go", 0},
 	{"⍉`a`b#1 2", "a b\n1 2", 0},
@@ -949,7 +1269,30 @@ var testCases = []struct {
 	{"T←⍉`a`b`c#(1 2 3;4 5 6;7 8 9;)⋄(⍉T)[`b]", "4 5 6", small},    // single column table as a vector
 	{"T←⍉`a`b`c#(1 2 3;4 5 6;7 8 9;)⋄T[1 2;`b]", "b\n4\n5", small}, // subtable if any index is multiple
 	{"T←⍉`a`b`c#(1 2 3;4 5 6;7 8 9;)⋄T[¯1;`c]", "8", small},        // single value
-	{"⍝ TODO: Assignment on tables, updates", "", 0},
+
+	{"⍝ Assignment on tables, updates, inserts and deletes", "apl/operators/assign.go", 0},
+	{"T←⍉`a`b#(1 2 3;4 5 6;)⋄T[2]←`a`b#(8;9;)⋄T", "a b\n1 4\n8 9\n3 6", small},                      // update a row with a dict
+	{"T←⍉`a`b#(1 2 3;4 5 6;)⋄T[1 3]←⍉`a`b#(7 8;9 0;)⋄T", "a b\n7 9\n2 5\n8 0", small},              // update rows with a table
+	{"T←⍉`a`b#(1 2 3;4 5 6;)⋄T[2;]←(0;0;)⋄T", "a b\n1 4\n0 0\n3 6", small},                          // update a row with a list
+	{"T←⍉`a`b#(1 2 3;4 5 6;)⋄T[;`b]←7 8 9⋄T", "a b\n1 7\n2 8\n3 9", small},                          // update a column
+	{"T←⍉`a`b#(1 2 3;4 5 6;)⋄T[¯1;`a]←0⋄T", "a b\n1 4\n0 5\n3 6", small},                            // update a single value
+	{"T←⍉`a`b#(1 2 3;4 5 6;)⋄T[;`c]←`x`y`z⋄T", "a b c\n1 4 x\n2 5 y\n3 6 z", small},              // add a column
+	{"T←⍉`a`b#(1 2 3;4 5 6;)⋄T[;`a]←9⋄⌶(⍉T)[`a]", "apl.IntArray", small},
+	{"T←⍉`a`b#(1 2 3;4 5 6;)⋄T[2;`a]←`x ⋄⌶(⍉T)[`a]", "apl.MixedArray", small},                              // upgrade a column
+	{"T←⍉`Qty`Px#(3 7 9;1 2 3;)⋄T[⍸T→Qty>5;`Px]×←1.5⋄T", "Qty Px\n3 1\n7 3\n9 4.5", small},        // modified assignment
+	{"T←⍉`Qty`Px#(3 7 9;1 2 3;)⋄T[⍸T→Qty>5;`Px]×←1.5⋄⌶T→Px", "numbers.FloatArray", small},            // uptype a column
+	{"T←⍉`a`b#(1 2;3 4;)⋄T[1 2]+←`a`b#(1;10 20;)⋄T", "a b\n2 13\n3 24", small},
+	{"T←⍉`a`b#(1 2;3 4;)⋄T,←`a`b#(5;6;)⋄T", "a b\n1 3\n2 4\n5 6", small},                            // append a row
+	{"T←⍉`a`b#(1 2;3 4;)⋄T,←`a`b#(5 7;6 8;)⋄⍴T", "4 2", small},                                        // append rows
+	{"T←⍉`a`b#(1 2;3 4;)⋄T⍪←⍉`a`b#(5 7;6 8;)⋄(⍉T)[`b]", "3 4 6 8", small},                                 // append a table
+	{"T←⍉`a`b#(1 2;3 4;)⋄(`a`b#(0;0;)),T", "a b\n0 0\n1 3\n2 4", small},                            // insert a row at the front
+	{"T←⍉`a`b#(1 2 3 4;5 6 7 8;)⋄T[1 3]←⍬⋄T", "a b\n2 6\n4 8", small},                                // delete rows
+	{"T←⍉`a`b#(1 2 3 4;5 6 7 8;)⋄T[⍸2<(⍉T)[`a]]←⍬⋄⍴T", "2 2", small},
+	{"T←⍉`a`b#(1 2 3;4 5 6;)⋄S←T⋄S[1]←⍬⋄⍴T", "3 2", small},                                            // variables own their tables
+	{"T←⍉`a`b#(1 2 3;4 5 6;)⋄T[;`a]←⍬", "fail: table assignment: only complete rows can be deleted", small},
+	{"T←⍉`a`b#(1 2 3;4 5 6;)⋄T[1]←`a#0", "fail: table assignment: key b is missing", small},
+	{"T←⍉`a`b#(1 2 3;4 5 6;)⋄T[1 2;`a]←1 2 3", "fail: indexed assignment: arrays are not conforming: [3] != [2]", small},
+	{"T←⍉`a`b#(1 2;3 4;)⋄T,←`a`b#(5;6 7;)", "fail: catenate: row values have different length", small},
 
 	{"⍝ Elementary functions on dicts and tables", "apl/primitives/elementary.go", 0},
 	{"A←`a`b#(1 2;3 4;)⋄-A", "a: ¯1 ¯2\nb: ¯3 ¯4", small},
@@ -984,10 +1327,83 @@ var testCases = []struct {
 	{"2+/⍉`a`b#(1 2 3;4 6 7;)", "a b\n3 10\n5 13", small},
 	// TODO {"T←⍉`a`b`c#(1 2 3;4 5 6;7 8 9;)⋄T,(+⌿÷≢)T", "a b c\n1 4 7\n2 5 8\n3 6 9\n2 5 8", small}, // table with avg value.
 
+	{"⍝ Missing values", "apl/primitives/null.go", 0},
+	{"1+0N", "0N", 0},
+	{"1 2 0N×3", "3 6 0N", 0},
+	{"0N=0N", "1", 0},
+	{"0N<¯5", "1", 0},
+	{"⍋3 0N 1", "2 3 1", 0},
+	{"+/1 0N 2", "3", 0},
+	{"⌈/0N 0N", "0N", 0},
+	{"+/0N 0N", "0", 0},
+	{"×/0N 0N", "1", 0},
+	{"×/0n 0n", "1", small},
+	{"×/2 2⍴0N 0N 2 0N", "1 2", 0},
+	{"⌊/0n 0n", "0n", small},
+	{"×/⍤1⊢2 2⍴0N", "1 1", 0},
+	{"+⌿2 3⍴1 0N 3 0N 0N 0N", "1 0 3", 0},
+	{"⍰1 0N 3", "0 1 0", 0},
+	{"⍰0N", "1", 0},
+	{"0⍰1 0N 3", "1 0 3", 0},
+	{"4 5 6⍰1 0N 3", "1 5 3", 0},
+	{"1.5+0n", "0n", small},
+	{"⍰1.5 0n", "0 1", small},
+	{"0⍰1.5 0n", "1.5 0", small},
+	{"⍰2018.12.24T00.00.00 0Nt", "0 1", small},
+	{"⍰\"a\" \"\"", "0 1", 0},
+	{"\"x\"⍰\"a\" \"\"", "a x", 0},
+	{"1 2⍰1 0N 3", "fail: fill: shapes do not conform", 0},
+	{"⍰`a`b#(1 0N;0N;)", "a: 0 1\nb: 1", 0},
+	{"T←⍉`A`B#(1 0N 3;4 5 0N;)⋄⍰T", "A B\n0 0\n1 0\n0 1", 0},
+	{"T←⍉`A`B#(1 0N 3;4 5 0N;)⋄(`A#9)⍰T", "A B\n1 4\n9 5\n3 0N", 0},
+	{"T←⍉`A`B#(1 0N 3;4 5 0N;)⋄+/T", "A B\n4 9", small},
+	{"T←⍉`A`B#(1 0N;4 5;)⋄T≡\"T\"⍎¯1⍕T", "1", 0},
+	{"¯2⍕1 0N", "[1,null]", 0},
+
+	{"⍝ Joins, left, inner, union and as-of join", "apl/primitives/join.go", 0},
+	{"A←⍉`K`X#(1 2 3;10 20 30;)⋄B←⍉`K`Y#(2 3 4;`b`c`d ;)⋄A,[`K]B", "K X Y\n1 10 \n2 20 b\n3 30 c", 0},
+	{"A←⍉`K`X#(1 2 3;10 20 30;)⋄B←⍉`K`Y#(3 2 4;`c`b`d ;)⋄A∩[`K]B", "K X Y\n2 20 b\n3 30 c", 0},
+	{"A←⍉`K`X#(1 2;10 20;)⋄B←⍉`K`X#(2 5;200 500;)⋄A∪[`K]B", "K X\n1 10\n2 200\n5 500", 0},
+	{"A←⍉`K`X#(1 2;10 20;)⋄B←⍉`K`Y#(2 5;1.5 2.5;)⋄A∪[`K]B", "K X Y\n1 10 0n\n2 20 1.5\n5 0N 2.5", small},
+	{"A←⍉`K`X#(1 0N 3;10 20 30;)⋄B←⍉`K`Y#(0N 3;7 8;)⋄J←A,[`K]B⋄J→Y", "0N 0N 8", 0},
+	{"A←⍉`K`L`X#(1 1 2;`a`b`a ;1 2 3;)⋄B←⍉`K`L`Y#(1 2;`b`a ;5 6;)⋄J←A,[`K`L]B⋄J→Y", "0N 5 6", 0},
+	{"A←⍉`K`X#(1 2 3;10 20 30;)⋄B←⍉`K`Y#(2.0 3.5 1;`b`c`d ;)⋄A,[`K]B", "K X Y\n1 10 d\n2 20 b\n3 30", small},
+	{"A←⍉`K`L`X#(1 2;`a`b ;1 2;)⋄B←⍉`K`L`Y#(1 2;`b`b ;5 6;)⋄A∩[`K`L]B", "K L X Y\n2 b 2 6", 0},
+	{"Q←⍉`S`T`P#(`a`a`b`a ;1 3 2 5;10 11 20 12;)⋄T←⍉`S`T#(`a`b`a`a`c ;2 1 4 0 9;)⋄Q⍸[`S`T]T", "S T P\na 2 10\nb 1 0N\na 4 11\na 0 0N\nc 9 0N", 0},
+	{"Q←⍉`T`P#(1 3 5;10 11 12;)⋄T←⍉`T`N#(0 3 4 9;⍳4;)⋄J←Q⍸[`T]T⋄J→P", "0N 11 11 12", 0},
+	{"Q←⍉`T`P#(3 1;10 11;)⋄T←⍉`T`N#(0 3;1 2;)⋄Q⍸[`T]T", "fail: as-of join: column T of L is not sorted", 0},
+	{"Q←⍉`S`T`P#(`a`a`b`a ;5 1 2 3;12 10 20 11;)⋄Q←⍋[`S`T]Q⋄T←⍉`S`T#(`a`b`c ;4 1 9;)⋄Q⍸[`S`T]T", "S T P\na 4 11\nb 1 0N\nc 9 0N", 0},
+	{"A←⍉`K`X#(1 2;10 20;)⋄A,[`Z]A", "fail: join: key column is missing in L: Z", 0},
+
+	{"⍝ Keyed tables, lookup and upsert", "apl/primitives/keytable.go", 0},
+	{"T←⍉`S`D`Q#(`a`b`c ;1 2 3;4.5 5 6;)⋄T[;,`S]#T[;`D`Q]", "S | D Q\na | 1 4.5\nb | 2 5\nc | 3 6", small},
+	{"T←⍉`S`D`Q#(`a`b ;1 2;4 5;)⋄K←T[;`S`D]#T[;,`Q]⋄(≢K),⍴#K", "2 2 2", 0},
+	{"T←⍉`S`Q#(`a`b`c ;4 5 6;)⋄K←T[;,`S]#T[;,`Q]⋄K[`b]", "Q: 5", 0},
+	{"T←⍉`S`Q#(`a`b`c ;4 5 6;)⋄K←T[;,`S]#T[;,`Q]⋄K[`c`x`a]", "Q\n6\n0N\n4", 0},
+	{"T←⍉`S`D`Q#(`a`b ;1 2;4 5;)⋄K←T[;`S`D]#T[;,`Q]⋄I←(`b ;2;)⋄K[I]", "Q: 5", 0},
+	{"T←⍉`S`D`Q#(`a`b ;1 2;4 5;)⋄K←T[;`S`D]#T[;,`Q]⋄R←(#K)[2 1]⋄K[R]", "Q\n5\n4", 0},
+	{"T←⍉`S`Q#(`a`b ;4 5;)⋄K←T[;,`S]#T[;,`Q]⋄(#K),K[#K]", "S Q\na 4\nb 5", 0},
+	{"T←⍉`S`Q#(`a`b ;4 5;)⋄K←T[;,`S]#T[;,`Q]⋄K,←⍉`S`Q#(`b`c ;7 8;)⋄K", "S | Q\na | 4\nb | 7\nc | 8", 0},
+	{"T←⍉`S`D`Q#(`a`b ;1 2;4 5;)⋄K←T[;,`S]#T[;`D`Q]⋄K,`S`Q#(`c ;9;)", "S | D Q\na | 1 4\nb | 2 5\nc | 0N 9", 0},
+	{"T←⍉`S`Q#(`a`b ;4 5;)⋄K←T[;,`S]#T[;,`Q]⋄K≡K,K", "1", 0},
+	{"T←⍉`S`Q#(`a`b ;4 5;)⋄K←T[;,`S]#T[;,`Q]⋄K,←`S`Q#(`c ;8;)⋄K,←`S`Q#(`d ;9;)⋄K,←`S`Q#(`a ;1;)⋄K", "S | Q\na | 1\nb | 5\nc | 8\nd | 9", 0},
+	{"T←⍉`S`Q#(`a`b ;4 5;)⋄K←T[;,`S]#T[;,`Q]⋄A←K,`S`Q#(`c ;8;)⋄B←K,`S`Q#(`d ;9;)⋄X←A[`c`d]⋄Y←B[`c`d]⋄(X→Q),(Y→Q),≢K", "8 0N 0N 9 2", 0},
+	{"T←⍉`S`Q#(`a`b ;4 5;)⋄K←T[;,`S]#T[;,`Q]⋄K,←`S`Q#(`a`c ;1.5 2;)⋄K", "S | Q\na | 1.5\nb | 5\nc | 2", small},
+	{"T←⍉`S`D`Q#(`a`b ;1 2;4 5;)⋄K←T[;`S`D]#T[;,`Q]⋄K,←⍉`S`D`Q#(`b`a ;2.0 3;6 7;)⋄V←K[#K]⋄V→Q", "4 6 7", small},
+	{"T←⍉`S`Q#(`a`b ;4 5;)⋄K←T[;,`S]#T[;,`Q]⋄\"json\"⍕K", "{\"key\":{\"S\":[\"a\",\"b\"]},\"value\":{\"Q\":[4,5]}}", 0},
+	{"T←⍉`S`Q#(`a`b ;4 0N;)⋄K←T[;,`S]#T[;,`Q]⋄\"csv\"⍕K", "[S],Q\na,4\nb,", 0},
+	{"T←⍉`S`Q#(`a`b ;4 5;)⋄K←T[;,`S]#T[;,`Q]⋄K≡\"csv\"⍎\"csv\"⍕K", "1", 0},
+	{"T←⍉`S`Q#(`a`a ;4 5;)⋄T[;,`S]#T[;,`Q]", "fail: keyed table: duplicate key in row 2", 0},
+	{"T←⍉`S`Q#(`a`b ;4 5;)⋄T[;,`S]#T", "fail: keyed table: column is key and value: S", 0},
+	{"T←⍉`S`Q#(`a`b ;4 5;)⋄K←T[;,`S]#T[;,`Q]⋄K,`Q#7", "fail: upsert: key column is missing: S", 0},
+
 	{"⍝ Object, go example", "apl/xgo/register.go", 0},
 	{"X←go→t 0⋄X[`V]←`a`b⋄X[`V]", "a b", 0},
 	{"X←go→t 0⋄X[`I]←55⋄X[`inc]⍨0⋄X[`I]", "56", small},
 	{"X←go→t 0⋄X[`V]←'abcd'⋄X[`join]⍨'+'", "(4;a+b+c+d;)", small},
+	{"X←go→t 0⋄X[`I]←5⋄X[`I]+←2⋄X[`I]", "7", small},
+	{"X←go→t 0⋄X[`I`F]+←3 1.5⋄X[`I`F]+←1⋄X[`I]⋄X[`F]", "4\n2.5", small},
+	{"X←go→t 0⋄X[`I]+←0.5", "fail: cannot convert numbers.Float to int", small},
 	{"S←go→s 0⋄#[1]S", "sum", 0},
 
 	{"⍝ Channels read, write and close", "apl/primitives/take.go", 0},
@@ -1005,6 +1421,11 @@ var testCases = []struct {
 	{"⍝ Communicate over a channel", "apl/channel.go", 0},
 	{`C←go→echo"?"⋄C↓'a'⋄C↓'b'⋄2↑C⋄↓C`, "a\nb\n?a ?b\n1", 0},
 
+	{"⍝ Partition a channel", "apl/primitives/partition.go", 0},
+	{"M←0=¨3|¨go→source 6⋄C←go→source 6⋄M⊂C", "(0;1;2;)\n(3;4;5;)", 0},
+	{"M←2<¨go→source 6⋄C←go→source 6⋄M⊆C", "(3;4;5;)", 0},
+	{"M←go→source 6⋄C←go→source 6⋄M⊆C", "(1;)\n(2;)\n(3;)\n(4;)\n(5;)", 0},
+
 	{"⍝ Primes", "", 0},
 	{"f←{(2=+⌿0=X∘.|X)⌿X←⍳⍵} ⋄ f 42", "2 3 5 7 11 13 17 19 23 29 31 37 41", 0},        // 01-primes
 	{"⎕IO←0 ⋄ f←{(~X∊X∘.×X)⌿X←2↓⍳⍵} ⋄ f 42", "2 3 5 7 11 13 17 19 23 29 31 37 41", 0}, // 01-primes
@@ -1023,7 +1444,7 @@ var testCases = []struct {
 	// life2←{3=s-⍵∧4=s←{+/,⍵}⌺3 3⊢⍵} // Dya: works without braces.
 
 	// github.com/DhavalDalal/APL-For-FP-Programmers
-	// filter←{(⍺⍺¨⍵)⌿⍵} // 01-primes
+	{"_filter←{(⍺⍺¨⍵)⌿⍵}⋄{2=+/0=⍵|⍨⍳⍵}_filter⍳20", "2 3 5 7 11 13 17 19", 0}, // 01-primes
 	// ⎕IO←0 ⋄ sieve ← {⍸⊃{~⍵[⍺]:⍵ ⋄ 0@(⍺×2↓⍳⌈(≢⍵)÷⍺)⊢⍵}/⌽(⊂0 0,(⍵-2)⍴1),⍳⍵} // 02-sieve
 	// ⎕IO←0 ⋄ triples←{{⍵/⍨(2⌷x)=+⌿2↑x←×⍨⍵}⍉↑,1+⍳⍵ ⍵ ⍵}// 03-pythagoreans
 	// ⎕IO←0 ⋄ '-:'⊣@(' '=⊢)¨(14⍴(4⍴1),0)(17⍴1 1 0)\¨⊂⍉(⎕D,6↑⎕A)[(12⍴16)⊤?10⍴2*48] // 04-MacAddress
@@ -1065,24 +1486,32 @@ const (
 )
 
 func TestNormal(t *testing.T) {
-	testApl(t, nil, 0)
+	testApl(t, nil, 0, false)
+}
+
+// TestCompiled runs the tests with compiled programs, see apl.Compile.
+func TestCompiled(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	testApl(t, nil, 0, true)
 }
 
 func TestBig(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
 	}
-	testApl(t, big.SetBigTower, small|float)
+	testApl(t, big.SetBigTower, small|float, false)
 }
 
 func TestPrecise(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
 	}
-	testApl(t, func(a *apl.Apl) { big.SetPreciseTower(a, 256) }, small)
+	testApl(t, func(a *apl.Apl) { big.SetPreciseTower(a, 256) }, small, false)
 }
 
-func testApl(t *testing.T, tower func(*apl.Apl), skip int) {
+func testApl(t *testing.T, tower func(*apl.Apl), skip int, compile bool) {
 	log := func(v ...interface{}) {
 		if testing.Short() {
 			t.Log(v...)
@@ -1150,11 +1579,19 @@ func testApl(t *testing.T, tower func(*apl.Apl), skip int) {
 		lines := strings.Split(tc.in, "\n")
 		for k, s := range lines {
 			logf("\t%s", s)
-			err := a.ParseAndEval(s)
+			p, err := a.Parse(s)
+			if err == nil {
+				if compile {
+					p = a.Compile(p)
+				}
+				err = a.Eval(p)
+			}
 			if err != nil && mustfail == false {
 				t.Fatalf("tc%d:%d: %s: %s\n", i+1, k+1, tc.in, err)
 			} else if err == nil && mustfail == true {
 				t.Fatalf("tc%d:%d: %s: should fail but did not", i+1, k+1, tc.in)
+			} else if msg := strings.TrimPrefix(tc.exp, "fail: "); mustfail && strings.Contains(err.Error(), msg) == false {
+				t.Fatalf("tc%d:%d: %s: expected error %q, got %q", i+1, k+1, tc.in, msg, err)
 			}
 		}
 		if mustfail {
@@ -2,6 +2,7 @@ package primitives
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
 
 	"github.com/ktye/iv/apl"
@@ -13,7 +14,7 @@ import (
 // If this function suceeds, only these cases are possible:
 //	- one or both are empty (apl.EmptyArray)
 //	- one is scalar and the other an array
-//	- both are arrays of the same shape
+//	- both are arrays, array2 returns an error if the shapes differ
 // A single element array is converted to a scalar, if the other is a larger array.
 type arrays struct{}
 
@@ -61,7 +62,8 @@ func (ars arrays) To(a *apl.Apl, L, R apl.Value) (apl.Value, apl.Value, bool) {
 		if apl.ArraySize(ar) == 1 {
 			return al, ar.At(0), true
 		}
-		return L, R, false
+		// Arrays that do not conform are accepted, array2 returns the error.
+		return L, R, true
 	}
 	if isLarray && scalarR {
 		return L, R, true
@@ -70,6 +72,9 @@ func (ars arrays) To(a *apl.Apl, L, R apl.Value) (apl.Value, apl.Value, bool) {
 	}
 	return L, R, false
 }
+func (ars arrays) Rejects(L, R reflect.Type) bool {
+	return L == nil || (isArrayType(L) == false && isArrayType(R) == false)
+}
 func (ars arrays) String(a *apl.Apl) string { return "arithmetic arrays" }
 
 // ArraysWithAxis is the domain for binary arithmetic functions
@@ -105,13 +110,25 @@ func (ars arraysWithAxis) To(a *apl.Apl, L, R apl.Value) (apl.Value, apl.Value,
 
 	return al, apl.Axis{A: x, R: ar}, true
 }
+func (ars arraysWithAxis) Rejects(L, R reflect.Type) bool {
+	return L == nil || R != reflect.TypeOf(apl.Axis{})
+}
 func (ars arraysWithAxis) String(a *apl.Apl) string { return "arithmetic arrays with axis" }
 
+// isArrayType returns true, if values of type t are arrays.
+func isArrayType(t reflect.Type) bool {
+	return t != nil && t.Implements(reflect.TypeOf((*apl.Array)(nil)).Elem())
+}
+
 // array1 tries to apply the elementary function returned by arith1(fn)
 // monadically to each element of the array R
+// Uniform numeric arrays are computed by a kernel, if possible, see kernel1.
 func array1(symbol string, fn func(*apl.Apl, apl.Value) (apl.Value, bool)) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
 	efn := arith1(symbol, fn)
 	return func(a *apl.Apl, _ apl.Value, R apl.Value) (apl.Value, error) {
+		if v, ok := kernel1(a, symbol, R); ok {
+			return v, nil
+		}
 		ar := R.(apl.Array)
 		res := apl.MixedArray{
 			Values: make([]apl.Value, apl.ArraySize(ar)),
@@ -131,6 +148,7 @@ func array1(symbol string, fn func(*apl.Apl, apl.Value) (apl.Value, bool)) func(
 // array2 tries to apply the elementary function returned by arith2(fn)
 // dyadically to the elements of the arrays L and R.
 // L and R have been tested and converted by arrays.
+// Uniform numeric arguments are computed by a kernel, if possible, see kernel2.
 func array2(symbol string, fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value, bool)) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
 	efn := arith2(symbol, fn)
 	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
@@ -145,6 +163,11 @@ func array2(symbol string, fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value, b
 
 		al, isLarray := L.(apl.Array)
 		ar, isRarray := R.(apl.Array)
+		if isLarray && isRarray {
+			if err := conform(symbol, al, ar); err != nil {
+				return nil, err
+			}
+		}
 
 		var shape []int
 		if isLarray == false {
@@ -152,6 +175,9 @@ func array2(symbol string, fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value, b
 		} else {
 			shape = apl.CopyShape(al)
 		}
+		if v, ok := kernel2(a, symbol, L, R, shape); ok {
+			return v, nil
+		}
 		res := apl.MixedArray{Dims: shape}
 		res.Values = make([]apl.Value, apl.ArraySize(res))
 		for i := range res.Values {
@@ -173,6 +199,20 @@ func array2(symbol string, fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value, b
 	}
 }
 
+// conform returns a rank or length error, if the shapes of L and R differ.
+func conform(symbol string, L, R apl.Array) error {
+	ls, rs := L.Shape(), R.Shape()
+	if len(ls) != len(rs) {
+		return apl.Errorf(apl.RankError, "%s: arguments have different rank: %d %d", symbol, len(ls), len(rs))
+	}
+	for i := range ls {
+		if ls[i] != rs[i] {
+			return apl.Errorf(apl.LengthError, "%s: arguments do not conform: %v %v", symbol, ls, rs)
+		}
+	}
+	return nil
+}
+
 // ArrayAxis is like array2 but with R bound in an axis specification.
 func arrayAxis(symbol string, fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value, bool)) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
 	efn := arith2(symbol, fn)
@@ -205,7 +245,7 @@ func arrayAxis(symbol string, fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value
 		// (⍴,X) ←→ (⍴⍴L)⌊⍴⍴R
 		// (⍴,X) ←→ ∧/X∊⍳(⍴⍴L)⌈⍴⍴R
 		if len(X.Dims) != 1 {
-			return nil, fmt.Errorf("axis specification must have rank 1: %T", len(X.Dims))
+			return nil, apl.Errorf(apl.RankError, "axis specification must have rank 1: %T", len(X.Dims))
 		}
 
 		// X≡X[⍋X]
@@ -218,17 +258,17 @@ func arrayAxis(symbol string, fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value
 
 		// (⍴L)[X] ←→ (⍴R).
 		if len(rs) != len(x) {
-			return nil, fmt.Errorf("axis rank must match lower argument rank")
+			return nil, apl.Errorf(apl.RankError, "axis rank must match lower argument rank")
 		}
 		for i, n := range x {
 			if n < 0 || n >= len(ls) {
-				return nil, fmt.Errorf("axis exceeds higher argument rank")
+				return nil, apl.Errorf(apl.RankError, "axis exceeds higher argument rank")
 			}
 			if i > 0 && n == x[i-1] {
 				return nil, fmt.Errorf("axis values are not unique")
 			}
 			if ls[n] != rs[i] {
-				return nil, fmt.Errorf("arguments with axis do not conform")
+				return nil, apl.Errorf(apl.LengthError, "arguments with axis do not conform")
 			}
 		}
 
@@ -86,7 +86,7 @@ func splitCatAxis(a *apl.Apl, L, R apl.Value) (apl.Value, int, bool, error) {
 	if n, ok := num.ToIndex(); ok {
 		n -= a.Origin
 		if n < 0 || n >= max {
-			return nil, 0, false, fmt.Errorf("axis is out of range")
+			return nil, 0, false, apl.Errorf(apl.IndexError, "axis is out of range")
 		}
 		return R, n, false, nil
 	}
@@ -13,7 +13,7 @@ import (
 func channelSource(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 	r, ax, err := splitAxis(a, R)
 	if err != nil {
-		return nil, fmt.Errorf("channel send: %s", err)
+		return nil, fmt.Errorf("channel send: %w", err)
 	}
 	if len(ax) > 1 {
 		return nil, fmt.Errorf("channel send: axis must be scalar")
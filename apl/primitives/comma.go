@@ -85,7 +85,7 @@ func ravelWithAxis(a *apl.Apl, R apl.Value) (apl.Value, error) {
 		R = r
 		x = vec
 	} else if r, n, frac, err := splitCatAxis(a, apl.Int(0), R); err != nil {
-		return nil, fmt.Errorf("ravel with axis: %s", err)
+		return nil, fmt.Errorf("ravel with axis: %w", err)
 	} else {
 		// The result has rank ⍴⍴R+1 with the same shape as R,
 		// but a new axis 1 at position x.
@@ -211,7 +211,7 @@ func catenate(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	// Catenate two scalars.
 	if isLarray == false && isRarray == false {
 		v, _ := a.Unify(apl.MixedArray{
-			Values: []apl.Value{L, R}, // TODO: copy?
+			Values: []apl.Value{L, R},
 			Dims:   []int{2},
 		}, false)
 		return v, nil
@@ -224,7 +224,7 @@ func catenate(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		}
 		ary.Values = make([]apl.Value, apl.ArraySize(ary))
 		for i := range ary.Values {
-			ary.Values[i] = scalar // TODO: copy?
+			ary.Values[i] = scalar
 		}
 		return ary
 	}
@@ -256,7 +256,7 @@ func catenate(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	}
 	if d := len(sl) - len(sr); d != 0 {
 		if d < -1 || d > 2 {
-			return nil, fmt.Errorf("catenate: ranks differ more that 1")
+			return nil, apl.Errorf(apl.RankError, "catenate: ranks differ more that 1")
 		}
 		if d == -1 {
 			sl = insert1(apl.CopyShape(al), x)
@@ -277,7 +277,7 @@ func catenate(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		if i == x { // i == len(sl)-1 {
 			newshape[i] = sl[i] + sr[i]
 		} else if sl[i] != sr[i] {
-			return nil, fmt.Errorf("catenate: all axis lengths except for the catenation axis must match")
+			return nil, apl.Errorf(apl.LengthError, "catenate: all axis lengths except for the catenation axis must match")
 		}
 	}
 	res := apl.MixedArray{
@@ -299,7 +299,7 @@ func catenate(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		} else {
 			v = al.At(lc.Index(src))
 		}
-		res.Values[i] = v // TODO: copy?
+		res.Values[i] = v
 		apl.IncArrayIndex(dst, newshape)
 	}
 	return res, nil
@@ -309,13 +309,17 @@ func catenateLists(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	l, lok := L.(apl.List)
 	r, rok := R.(apl.List)
 	if lok == false {
-		return append(apl.List{L}, r...), nil // TODO: copy
+		return append(apl.List{L}, r...), nil
 	} else if rok == false {
-		return append(l, R), nil // TODO: copy
+		// Do not append to l, it may share it's memory.
+		res := make(apl.List, len(l)+1)
+		copy(res, l)
+		res[len(l)] = R
+		return res, nil
 	}
 	res := make(apl.List, len(l)+len(r))
-	copy(res, l)          // TODO: copy
-	copy(res[len(l):], r) // TODO: copy
+	copy(res, l)
+	copy(res[len(l):], r)
 	return res, nil
 }
 
@@ -335,7 +339,7 @@ func laminate(a *apl.Apl, L, R apl.Value, x int) (apl.Value, error) {
 		}
 		ary.Values = make([]apl.Value, apl.ArraySize(ary))
 		for i := range ary.Values {
-			ary.Values[i] = scalar // TODO: copy?
+			ary.Values[i] = scalar
 		}
 		return ary
 	}
@@ -349,7 +353,7 @@ func laminate(a *apl.Apl, L, R apl.Value, x int) (apl.Value, error) {
 	rs := ar.Shape()
 
 	if len(ls) != len(rs) {
-		return nil, fmt.Errorf("laminate: arguments must have the same rank")
+		return nil, apl.Errorf(apl.RankError, "laminate: arguments must have the same rank")
 	}
 	for i := range ls {
 		if ls[i] != rs[i] {
@@ -385,7 +389,7 @@ func laminate(a *apl.Apl, L, R apl.Value, x int) (apl.Value, error) {
 		} else {
 			v = ar.At(ic.Index(src))
 		}
-		res.Values[i] = v // TODO: copy?
+		res.Values[i] = v
 		apl.IncArrayIndex(dst, shape)
 	}
 	return res, nil
@@ -422,7 +426,7 @@ func table(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 func enlist(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 	r, ok := R.(apl.List)
 	if ok == false {
-		return apl.List{R}, nil // TODO: copy
+		return apl.List{R}, nil
 	}
 
 	var f func(l apl.List) apl.List
@@ -431,9 +435,9 @@ func enlist(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 		for _, e := range l {
 			if v, ok := e.(apl.List); ok {
 				v = f(v)
-				res = append(res, v...) // TODO: copy
+				res = append(res, v...)
 			} else {
-				res = append(res, e) // TODO: copy
+				res = append(res, e)
 			}
 		}
 		return res
@@ -49,29 +49,33 @@ func compare(symbol string) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, boo
 	return func(a *apl.Apl, L apl.Value, R apl.Value) (apl.Value, bool) {
 		switch symbol {
 		case "=":
-			return equals(L, R)
+			return equals(a, L, R)
 		case "<":
-			return less(L, R)
+			eq, ls, ok := equalless(a, L, R)
+			if ok == false {
+				return nil, false
+			}
+			return apl.Bool(ls && !eq), true
 		case ">":
-			eq, ls, ok := equalless(L, R)
+			eq, ls, ok := equalless(a, L, R)
 			if ok == false {
 				return nil, false
 			}
 			return apl.Bool(!eq && !ls), true
 		case "≠":
-			eq, ok := equals(L, R)
+			eq, ok := equals(a, L, R)
 			if ok == false {
 				return nil, false
 			}
 			return apl.Bool(!eq), true
 		case "≤":
-			eq, ls, ok := equalless(L, R)
+			eq, ls, ok := equalless(a, L, R)
 			if ok == false {
 				return nil, false
 			}
 			return apl.Bool(eq || ls), true
 		case "≥":
-			eq, ls, ok := equalless(L, R)
+			eq, ls, ok := equalless(a, L, R)
 			if ok == false {
 				return nil, false
 			}
@@ -81,8 +85,8 @@ func compare(symbol string) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, boo
 	}
 }
 
-func equalless(L, R apl.Value) (apl.Bool, apl.Bool, bool) {
-	eq, ok := equals(L, R)
+func equalless(a *apl.Apl, L, R apl.Value) (apl.Bool, apl.Bool, bool) {
+	eq, ok := equals(a, L, R)
 	if ok == false {
 		return false, false, false
 	}
@@ -92,7 +96,18 @@ func equalless(L, R apl.Value) (apl.Bool, apl.Bool, bool) {
 	}
 	return eq, ls, true
 }
-func equals(L, R apl.Value) (apl.Bool, bool) {
+// equals compares L and R, which have the same type.
+// Inexact numbers are compared with the comparison tolerance ⎕CT.
+// Nulls are equal to each other.
+func equals(a *apl.Apl, L, R apl.Value) (apl.Bool, bool) {
+	if ln, rn := apl.IsNull(L), apl.IsNull(R); ln || rn {
+		return apl.Bool(ln && rn), true
+	}
+	if a.CT > 0 {
+		if eq, ok := L.(tolerantEqualer); ok {
+			return eq.TolerantEquals(R, a.CT)
+		}
+	}
 	if eq, ok := L.(equaler); ok {
 		return eq.Equals(R)
 	}
@@ -103,7 +118,17 @@ type equaler interface {
 	Equals(apl.Value) (apl.Bool, bool)
 }
 
+type tolerantEqualer interface {
+	TolerantEquals(apl.Value, float64) (apl.Bool, bool)
+}
+
+// less compares L and R. A null is less than any other value.
 func less(L, R apl.Value) (apl.Bool, bool) {
+	if ln, rn := apl.IsNull(L), apl.IsNull(R); ln || rn {
+		if _, ok := L.(lesser); ok {
+			return apl.Bool(ln && !rn), true
+		}
+	}
 	if ls, ok := L.(lesser); ok {
 		return ls.Less(R)
 	}
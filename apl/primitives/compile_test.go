@@ -0,0 +1,73 @@
+package primitives
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/domain"
+	"github.com/ktye/iv/apl/numbers"
+	"github.com/ktye/iv/apl/operators"
+)
+
+// TestCompileRegister tests that registering a primitive invalidates the dispatch cache.
+func TestCompileRegister(t *testing.T) {
+	var buf strings.Builder
+	a := apl.New(&buf)
+	numbers.Register(a)
+	Register(a)
+	operators.Register(a)
+	p, err := a.Parse("{⍺+⍵}/1 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = a.Compile(p)
+	if err := a.Eval(p); err != nil {
+		t.Fatal(err)
+	}
+	a.RegisterPrimitive("+", apl.ToHandler(func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		return apl.String("plus"), nil
+	}, domain.Dyadic(domain.Split(domain.IsNumber(nil), domain.IsNumber(nil))), "test"))
+	if err := a.Eval(p); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "3\nplus\n" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+// BenchmarkCompile compares compiled programs with the interpreter
+// for lambda functions, that are called for each element.
+//	go test -run XXX -bench Compile -count 3 ./apl/primitives
+func BenchmarkCompile(b *testing.B) {
+	for _, s := range []string{
+		"+/{X←⍵+1⋄X×⍵-2}¨⍳1000",
+		"+/{⍵>500:⍵⋄-⍵}¨⍳1000",
+		"f←{0=⍵:⍺⋄(⍺+⍵)∇⍵-1}⋄0 f 1000",
+	} {
+		for _, c := range []bool{false, true} {
+			name := s + "/interpreted"
+			if c {
+				name = s + "/compiled"
+			}
+			b.Run(name, func(b *testing.B) {
+				a := apl.New(nil)
+				numbers.Register(a)
+				Register(a)
+				operators.Register(a)
+				p, err := a.Parse(s)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if c {
+					p = a.Compile(p)
+				}
+				for i := 0; i < b.N; i++ {
+					if _, err := a.EvalProgram(p); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
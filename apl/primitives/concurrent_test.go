@@ -0,0 +1,44 @@
+package primitives
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/numbers"
+	"github.com/ktye/iv/apl/operators"
+	"github.com/ktye/iv/apl/xgo"
+)
+
+// TestConcurrent reads values from a channel pipeline, that is evaluated in the background,
+// while the interpreter assigns variables, ⎕PP and operators.
+// The pipeline formats with the ⎕PP at the time it was created.
+// Run it with go test -race.
+func TestConcurrent(t *testing.T) {
+	var buf strings.Builder
+	a := apl.New(&buf)
+	numbers.Register(a)
+	Register(a)
+	operators.Register(a)
+	xgo.Register(a, "go")
+
+	eval := func(s string) {
+		if err := a.ParseAndEval(s); err != nil {
+			t.Fatalf("%s: %s", s, err)
+		}
+	}
+	eval("E1←{⍕⍵÷4}¨⍳100⋄E2←{⍕⍵÷4}¨1+⍳100")
+	eval("X←1⋄C←⎕←{⍕⍵÷4}¨{⍵+X}¨go→source 100")
+	e1, e2 := a.Lookup("E1").(apl.Array), a.Lookup("E2").(apl.Array)
+	for i := 0; i < 100; i++ {
+		eval(fmt.Sprintf("X←%d⋄⎕PP←3⋄_t←{⍺⍺ ⍵}⋄Y←-_t+/{⍵×X}¨⍳10⋄T←↑C", 1+i%2))
+		v := a.Lookup("T").String(a)
+		if x1, x2 := e1.At(i).String(a), e2.At(i).String(a); v != x1 && v != x2 {
+			t.Fatalf("#%d: got %s, expected %s or %s", i, v, x1, x2)
+		}
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 100 {
+		t.Fatalf("expected 100 lines of output, got %d", n)
+	}
+}
@@ -0,0 +1,263 @@
+package primitives
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/big"
+	"github.com/ktye/iv/apl/numbers"
+	"github.com/ktye/iv/apl/operators"
+)
+
+// TestData tests the round trip of the data format: X≡P⍎¯1⍕X
+// for random values of all types.
+func TestData(t *testing.T) {
+	towers := []struct {
+		name  string
+		set   func(*apl.Apl)
+		kinds string
+		image bool
+	}{
+		{"normal", nil, "ifcbts", true},
+		{"big", big.SetBigTower, "irbs", false},
+		{"precise", func(a *apl.Apl) { big.SetPreciseTower(a, 256) }, "fcs", false},
+	}
+	for _, tw := range towers {
+		var buf strings.Builder
+		a := apl.New(&buf)
+		numbers.Register(a)
+		if tw.set != nil {
+			tw.set(a)
+		}
+		Register(a)
+		operators.Register(a)
+
+		g := dataGen{a: a, r: rand.New(rand.NewSource(1)), kinds: tw.kinds, images: tw.image}
+		for i := 0; i < 500; i++ {
+			x, p := g.value(i)
+			if err := a.Assign("X", x); err != nil {
+				t.Fatal(err)
+			}
+			if err := a.Assign("P", p); err != nil {
+				t.Fatal(err)
+			}
+			buf.Reset()
+			err := a.ParseAndEval("X≡P⍎¯1⍕X")
+			if got := strings.TrimSpace(buf.String()); err != nil || got != "1" {
+				a.PP = -1
+				t.Fatalf("%s tower, value %d (%T): round trip failed: %v\n%s", tw.name, i, x, err, x.String(a))
+			}
+		}
+	}
+}
+
+// dataGen generates random values for TestData.
+// Numbers are created by parsing their string form with the current tower.
+// Some of them are replaced by the null value of their type.
+// Kinds are: i(int) f(float) c(complex) b(bool) t(time) r(rational) s(string).
+type dataGen struct {
+	a      *apl.Apl
+	r      *rand.Rand
+	kinds  string
+	images bool
+}
+
+// value returns a random value and the prototype or type string to parse it.
+func (g *dataGen) value(i int) (apl.Value, apl.Value) {
+	switch i % 7 {
+	case 0:
+		return g.scalar(g.kind()), apl.String("A")
+	case 1:
+		return g.uniform(), apl.String("A")
+	case 2:
+		return g.mixed(), apl.String("A")
+	case 3:
+		return g.list(2), apl.String("A")
+	case 4:
+		return g.dict(1), apl.String("D")
+	case 5:
+		return g.table(1), apl.String("T")
+	}
+	if g.images == false {
+		return g.list(1), apl.String("A")
+	}
+	im := g.image()
+	return im, im
+}
+
+func (g *dataGen) kind() byte {
+	return g.kinds[g.r.Intn(len(g.kinds))]
+}
+
+func (g *dataGen) float() string {
+	f := g.r.NormFloat64() * math.Pow(10, float64(g.r.Intn(60)-30))
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func (g *dataGen) scalar(k byte) apl.Value {
+	var s string
+	switch k {
+	case 'i':
+		s = strconv.Itoa(g.r.Intn(2000) - 1000)
+	case 'f':
+		s = g.float()
+	case 'c':
+		s = g.float() + "J" + g.float()
+	case 'b':
+		s = strconv.Itoa(g.r.Intn(2)) + "b"
+	case 't':
+		if g.r.Intn(2) == 0 {
+			d := time.Duration(g.r.Int63n(1e13) - 5e12)
+			s = strings.Replace(d.String(), "µ", "u", 1)
+		} else {
+			s = time.Unix(g.r.Int63n(4e9), g.r.Int63n(1e9)).UTC().Format("2006.01.02T15.04.05.000000000")
+		}
+	case 'r':
+		s = fmt.Sprintf("%dr%d", g.r.Intn(200)-100, 1+g.r.Intn(50))
+	default:
+		runes := []string{"a", "B", " ", `"`, `\`, "\n", "\t", "⍳", ";", "(", "]", ":"}
+		var b strings.Builder
+		for n := g.r.Intn(5); n > 0; n-- {
+			b.WriteString(runes[g.r.Intn(len(runes))])
+		}
+		return apl.String(b.String())
+	}
+	n, err := g.a.Tower.Parse(strings.Replace(s, "-", "¯", -1))
+	if err != nil {
+		panic(fmt.Sprintf("%s: %s", s, err))
+	}
+	if nv, ok := n.Number.(apl.Nullable); ok && g.r.Intn(20) == 0 {
+		return nv.Null()
+	}
+	return n.Number
+}
+
+func (g *dataGen) shape() []int {
+	shape := make([]int, 1+g.r.Intn(3))
+	for i := range shape {
+		shape[i] = 1 + g.r.Intn(3)
+	}
+	return shape
+}
+
+func (g *dataGen) array(shape []int, kind func() byte) apl.Value {
+	m := apl.MixedArray{Dims: shape, Values: make([]apl.Value, apl.ArraySize(apl.IntArray{Dims: shape}))}
+	for i := range m.Values {
+		m.Values[i] = g.scalar(kind())
+	}
+	if u, ok := g.a.Unify(m, false); ok {
+		return u
+	}
+	return m
+}
+
+func (g *dataGen) uniform() apl.Value {
+	k := g.kind()
+	return g.array(g.shape(), func() byte { return k })
+}
+
+func (g *dataGen) mixed() apl.Value {
+	return g.array(g.shape(), g.kind)
+}
+
+func (g *dataGen) list(depth int) apl.Value {
+	l := make(apl.List, g.r.Intn(4))
+	for i := range l {
+		l[i] = g.item(depth)
+	}
+	return l
+}
+
+// item returns a value that can be nested in a list, dict or table.
+func (g *dataGen) item(depth int) apl.Value {
+	switch g.r.Intn(6) {
+	case 0:
+		return g.uniform()
+	case 1:
+		return g.mixed()
+	case 2:
+		if depth > 0 {
+			return g.list(depth - 1)
+		}
+	case 3:
+		return apl.EmptyArray{}
+	case 4:
+		if depth > 0 && g.r.Intn(2) == 0 {
+			return g.dict(depth - 1)
+		} else if depth > 0 {
+			return g.table(depth - 1)
+		}
+	}
+	return g.scalar(g.kind())
+}
+
+func (g *dataGen) keys(n int) []apl.Value {
+	keys := make([]apl.Value, n)
+	for i := range keys {
+		keys[i] = apl.String(fmt.Sprintf("K%d", i))
+	}
+	g.r.Shuffle(n, func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+	return keys
+}
+
+func (g *dataGen) dict(depth int) apl.Value {
+	d := apl.Dict{K: g.keys(g.r.Intn(4)), M: make(map[apl.Value]apl.Value)}
+	for _, k := range d.K {
+		d.M[k] = g.item(depth)
+	}
+	return &d
+}
+
+func (g *dataGen) table(depth int) apl.Value {
+	rows := g.r.Intn(4)
+	d := apl.Dict{K: g.keys(1 + g.r.Intn(4)), M: make(map[apl.Value]apl.Value)}
+	for _, k := range d.K {
+		if rows == 0 {
+			d.M[k] = apl.EmptyArray{}
+			continue
+		}
+		switch g.r.Intn(4) {
+		case 0:
+			d.M[k] = g.array([]int{rows}, g.kind)
+		case 1:
+			m := apl.MixedArray{Dims: []int{rows}, Values: make([]apl.Value, rows)}
+			for i := range m.Values {
+				m.Values[i] = g.item(depth)
+			}
+			d.M[k] = m
+		default:
+			kind := g.kind()
+			d.M[k] = g.array([]int{rows}, func() byte { return kind })
+		}
+	}
+	return apl.Table{Dict: &d, Rows: rows}
+}
+
+func (g *dataGen) image() apl.Value {
+	shape := []int{1 + g.r.Intn(3), 1 + g.r.Intn(3)}
+	frames := 1
+	if g.r.Intn(2) == 0 {
+		frames = 1 + g.r.Intn(3)
+		shape = append([]int{frames}, shape...)
+	}
+	im := apl.Image{Im: make([]image.Image, frames), Dims: shape}
+	h, w := shape[len(shape)-2], shape[len(shape)-1]
+	for p := range im.Im {
+		m := image.NewRGBA(image.Rect(0, 0, w, h))
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				m.Set(x, y, color.RGBA{uint8(g.r.Intn(256)), uint8(g.r.Intn(256)), uint8(g.r.Intn(256)), 0xFF})
+			}
+		}
+		im.Im[p] = m
+	}
+	return im
+}
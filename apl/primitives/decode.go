@@ -49,7 +49,7 @@ func decode(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		Dims:   apl.CopyShape(al),
 	}
 	for i := range p.Values {
-		p.Values[i] = al.At(i) // TODO: copy?
+		p.Values[i] = al.At(i)
 	}
 	N := ls[len(ls)-1]
 
@@ -84,7 +84,7 @@ func extendAxis(ar apl.Array, axis, n int) (apl.Array, []int) {
 		copy(idx, ridx)
 		idx[axis] = 0
 		v := ar.At(ic.Index(idx))
-		res.Values[i] = v // TODO copy?
+		res.Values[i] = v
 		apl.IncArrayIndex(ridx, res.Dims)
 	}
 	return res, res.Dims
@@ -258,7 +258,7 @@ func encodeArray(a *apl.Apl, al apl.Array, R apl.Value) (apl.Value, error) {
 			return err
 		}
 		for i := range vec {
-			res.Values[i*n+off] = vec[i] // TODO copy?
+			res.Values[i*n+off] = vec[i]
 		}
 		return nil
 	}
@@ -78,13 +78,13 @@ func dict(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	if ok == false {
 		mr := apl.MixedArray{Dims: []int{ls[0]}, Values: make([]apl.Value, ls[0])}
 		for i := range mr.Values {
-			mr.Values[i] = R // TODO: copy?
+			mr.Values[i] = R
 		}
 		ar = mr
 	}
 	rs := ar.Shape()
 	if len(rs) != 1 || rs[0] != ls[0] {
-		return nil, fmt.Errorf("dict: left and right arguments do not conform")
+		return nil, apl.Errorf(apl.LengthError, "dict: left and right arguments do not conform")
 	}
 
 	k := make([]apl.Value, al.Size())
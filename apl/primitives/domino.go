@@ -33,7 +33,7 @@ func domino(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 	ar := R.(apl.Array)
 	rs := ar.Shape()
 	if len(rs) > 2 {
-		return nil, fmt.Errorf("matrix inverse: rank cannot be > 2: %d", len(rs))
+		return nil, apl.Errorf(apl.RankError, "matrix inverse: rank cannot be > 2: %d", len(rs))
 	} else if len(rs) == 0 {
 		return apl.EmptyArray{}, nil
 	} else if len(rs) == 1 {
@@ -65,7 +65,7 @@ func domino2(a *apl.Apl, RHS, R apl.Value) (apl.Value, error) {
 	}
 
 	if len(rs) != 2 {
-		return nil, fmt.Errorf("matrix divide: right argument matrix must have rank 2")
+		return nil, apl.Errorf(apl.RankError, "matrix divide: right argument matrix must have rank 2")
 	}
 	if rs[0] < rs[1] {
 		return nil, fmt.Errorf("matrix divide: right argument matrix has more columns than rows")
@@ -79,7 +79,7 @@ func domino2(a *apl.Apl, RHS, R apl.Value) (apl.Value, error) {
 		}
 		ls = al.Shape()
 	} else if len(ls) != 2 {
-		return nil, fmt.Errorf("matrix divide: left argument must have rank 2: %d", len(ls))
+		return nil, apl.Errorf(apl.RankError, "matrix divide: left argument must have rank 2: %d", len(ls))
 	}
 	if ls[0] != rs[0] {
 		return nil, fmt.Errorf("matrix divide: left and right matrices must have the same number of rows")
@@ -89,7 +89,7 @@ func domino2(a *apl.Apl, RHS, R apl.Value) (apl.Value, error) {
 	// We have no special QR algorithm for the general case.
 	if rs[0] > rs[1] {
 		conj := array1("+", add)
-		h, err := conj(a, nil, ar) // TODO copy?
+		h, err := conj(a, nil, ar)
 		if err != nil {
 			return nil, err
 		}
@@ -149,7 +149,7 @@ func domino2(a *apl.Apl, RHS, R apl.Value) (apl.Value, error) {
 
 		// Copy x to result array.
 		for i := 0; i < n; i++ {
-			res.Values[i*ls[1]+k] = x[i] // TODO copy
+			res.Values[i*ls[1]+k] = x[i]
 		}
 	}
 	return res, nil
@@ -29,6 +29,7 @@ func init() {
 	}
 
 	for _, e := range tab {
+		e.monadic, e.dyadic = nulls1(e.monadic), nulls2(e.dyadic)
 		register(primitive{
 			symbol: e.symbol,
 			doc:    e.doc,
@@ -92,6 +93,33 @@ func init() {
 	}
 }
 
+// nulls1 propagates a null number: the result is the null value of R.
+func nulls1(fn func(*apl.Apl, apl.Value) (apl.Value, bool)) func(*apl.Apl, apl.Value) (apl.Value, bool) {
+	return func(a *apl.Apl, R apl.Value) (apl.Value, bool) {
+		if n, ok := R.(apl.Nullable); ok && n.IsNull() {
+			if _, ok := R.(apl.Number); ok {
+				return n.Null(), true
+			}
+		}
+		return fn(a, R)
+	}
+}
+
+// nulls2 propagates null numbers for dyadic functions.
+// It is applied after L and R are converted to the same type.
+func nulls2(fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value, bool)) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, bool) {
+	return func(a *apl.Apl, L, R apl.Value) (apl.Value, bool) {
+		if apl.IsNull(L) || apl.IsNull(R) {
+			_, ln := L.(apl.Number)
+			_, rn := R.(apl.Number)
+			if n, ok := L.(apl.Nullable); ok && ln && rn {
+				return n.Null(), true
+			}
+		}
+		return fn(a, L, R)
+	}
+}
+
 // arith1 tries to apply fn to the right argument.
 // If it does not succeed directly, it tests if the argument is a number and uptypes until
 // the function application succeeds.
@@ -150,7 +178,7 @@ func arith2(symbol string, fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value, b
 		var err error
 		ln, rn, err = a.Tower.SameType(ln, rn)
 		if err != nil {
-			return nil, fmt.Errorf("%s: %s", symbol, err)
+			return nil, fmt.Errorf("%s: %w", symbol, err)
 		}
 		num := a.Tower.ToNumeric(ln)
 		if num == nil {
@@ -364,8 +392,14 @@ type floorer interface {
 }
 
 // min returns the largest integer that is less or equal to R
+// The floor is tolerant: if R is within ⎕CT of the next larger integer, that is returned.
 func min(a *apl.Apl, R apl.Value) (apl.Value, bool) {
 	if floor, ok := R.(floorer); ok {
+		if ceil, ok := R.(ceiler); ok {
+			if n, ok := tolerantInteger(a, R, ceil.Ceil); ok {
+				return n, true
+			}
+		}
 		return floor.Floor()
 	}
 	return nil, false
@@ -388,8 +422,14 @@ type ceiler interface {
 }
 
 // max returns the smallest integer that is larger or equal to R
+// The ceiling is tolerant: if R is within ⎕CT of the next smaller integer, that is returned.
 func max(a *apl.Apl, R apl.Value) (apl.Value, bool) {
 	if ceil, ok := R.(ceiler); ok {
+		if floor, ok := R.(floorer); ok {
+			if n, ok := tolerantInteger(a, R, floor.Floor); ok {
+				return n, true
+			}
+		}
 		return ceil.Ceil()
 	}
 	return nil, false
@@ -406,6 +446,26 @@ func max2(a *apl.Apl, L, R apl.Value) (apl.Value, bool) {
 	}
 }
 
+// tolerantInteger returns the integer n computed by fn, if R is an inexact number
+// that is equal to n within the comparison tolerance.
+func tolerantInteger(a *apl.Apl, R apl.Value, fn func() (apl.Value, bool)) (apl.Value, bool) {
+	if a.CT == 0 {
+		return nil, false
+	}
+	eq, ok := R.(tolerantEqualer)
+	if ok == false {
+		return nil, false
+	}
+	n, ok := fn()
+	if ok == false || reflect.TypeOf(n) != reflect.TypeOf(R) {
+		return nil, false
+	}
+	if iseq, ok := eq.TolerantEquals(n, a.CT); ok && bool(iseq) {
+		return n, true
+	}
+	return nil, false
+}
+
 // ! factorial, binomial
 type gammaer interface {
 	Gamma() (apl.Value, bool)
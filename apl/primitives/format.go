@@ -1,10 +1,14 @@
 package primitives
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/csv"
 	"fmt"
+	"io"
 	"reflect"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/ktye/iv/apl"
 	. "github.com/ktye/iv/apl/domain"
@@ -31,6 +35,12 @@ func init() {
 		Domain: Dyadic(Split(IsObject(nil), IsTable(nil))),
 		fn:     formatTable,
 	})
+	register(primitive{
+		symbol: "⍕",
+		doc:    "format, convert to string",
+		Domain: Dyadic(Split(IsObject(nil), IsKeyTable(nil))),
+		fn:     formatTable,
+	})
 
 	register(primitive{
 		symbol: "⍎",
@@ -44,6 +54,18 @@ func init() {
 		Domain: Dyadic(Split(nil, IsString(nil))),
 		fn:     parseData,
 	})
+	register(primitive{
+		symbol: "⍎",
+		doc:    "parse data channel",
+		Domain: Dyadic(Split(IsString(nil), IsChannel(nil))),
+		fn:     parseChannel,
+	})
+	register(primitive{
+		symbol: "⍎",
+		doc:    "parse csv, [options]",
+		Domain: csvAxis{},
+		fn:     parseCsv,
+	})
 }
 
 // Format converts the argument to string.
@@ -81,11 +103,17 @@ func format(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	return apl.String(R.String(a)), nil
 }
 
-// L is an object and R a Table.
+// tableWriter is implemented by Table and KeyTable.
+type tableWriter interface {
+	Csv(*apl.Apl, apl.Object, io.Writer) error
+	WriteFormatted(*apl.Apl, apl.Object, io.Writer) error
+}
+
+// L is an object and R a Table or a KeyTable.
 // Corresponding values of L are used as format arguments to values in R.
 // If L contains the key CSV, formatCSV is used.
 func formatTable(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
-	t := R.(apl.Table)
+	t := R.(tableWriter)
 	d := L.(apl.Object)
 	if d.At(a, apl.String("CSV")) != nil {
 		return formatCsv(a, d, R)
@@ -98,7 +126,7 @@ func formatTable(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 }
 
 // formatCSV formats R in csv format.
-// R must be a rank 2 array or a table.
+// R must be a rank 2 array, a table or a keyed table.
 // If L with corresponding keys.
 func formatCsv(a *apl.Apl, L apl.Object, R apl.Value) (apl.Value, error) {
 	var b bytes.Buffer
@@ -126,7 +154,7 @@ func formatCsv(a *apl.Apl, L apl.Object, R apl.Value) (apl.Value, error) {
 		}
 		w.Flush()
 		return apl.String(b.Bytes()), nil
-	} else if t, ok := R.(apl.Table); ok {
+	} else if t, ok := R.(tableWriter); ok {
 		var b bytes.Buffer
 		if err := t.Csv(a, L, &b); err != nil {
 			return nil, err
@@ -159,8 +187,14 @@ func execute(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 
 // ParseData parses data from strings that has been written with ¯1⍕V.
 // L may be "A", "D" or "T" for array, dict or table.
+// L may also be "json" to parse json data, or "jsontable" which converts
+// arrays of objects with the same keys to tables.
 // If L is a value of type array, dict or table it is used as a prototype with stricter requirements.
+// If L is "csv", R is parsed as a csv table, see parseCsv.
 func parseData(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == apl.String("csv") {
+		return readCsv(a, nil, R, defaultCsv)
+	}
 	var p apl.Value
 	ls, ok := L.(apl.String)
 	if ok == false {
@@ -183,6 +217,131 @@ func parseData(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		return a.ParseDict(p, string(rs))
 	case "T":
 		return a.ParseTable(p, string(rs))
+	case "json":
+		return a.ParseJson(string(rs), false)
+	case "jsontable":
+		return a.ParseJson(string(rs), true)
 	}
 	return nil, fmt.Errorf("parse data: left argument is an unknown type: %s", ls)
 }
+
+// parseChannel parses each line read from channel R as data of type L
+// and sends the values over the returned channel, e.g. "json"⍎C for json-lines.
+// Empty lines, which parse to ⍬, are skipped.
+// For csv data, all lines are read from the channel and a single table is returned,
+// e.g. "csv"⍎<"/data.csv"
+func parseChannel(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == apl.String("csv") {
+		return readCsv(a, nil, R, defaultCsv)
+	}
+	c := R.(apl.Channel)
+	return c.Apply(a, apl.Primitive("⍎"), L, true), nil
+}
+
+// csvAxis accepts "csv" or a prototype dict or table as the left argument
+// and an axis with a string or a channel as the right argument.
+type csvAxis struct{}
+
+func (c csvAxis) To(a *apl.Apl, L, R apl.Value) (apl.Value, apl.Value, bool) {
+	switch l := L.(type) {
+	case apl.String:
+		if l != "csv" {
+			return L, R, false
+		}
+	case *apl.Dict, apl.Table:
+	default:
+		return L, R, false
+	}
+	if ax, ok := R.(apl.Axis); ok == false {
+		return L, R, false
+	} else if _, ok := ax.R.(apl.String); ok {
+		return L, R, true
+	} else if _, ok := ax.R.(apl.Channel); ok {
+		return L, R, true
+	}
+	return L, R, false
+}
+func (c csvAxis) String(a *apl.Apl) string { return "csv|prototype [options] string|channel" }
+
+// parseCsv parses csv data from a string or a channel with options given by the axis, see csvOptions.
+// L is "csv" or a prototype dict or table for the column types, see apl.ParseCsv, e.g.
+//	"csv"⍎[";"]S
+//	O←`delimiter`header#(";";0;)
+//	(`Qty`Px#(0;0.5;))⍎[O]<"/data.csv"
+func parseCsv(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	ax := R.(apl.Axis)
+	c, err := csvOptions(a, ax.A)
+	if err != nil {
+		return nil, err
+	}
+	var p apl.Value
+	if _, ok := L.(apl.String); ok == false {
+		p = L
+	}
+	return readCsv(a, p, ax.R, c)
+}
+
+// readCsv parses csv data from a string or a channel of lines.
+// For a channel, all lines are read and a single table is returned, e.g. "csv"⍎<"/data.csv"
+func readCsv(a *apl.Apl, prototype, R apl.Value, c csvArgs) (apl.Value, error) {
+	var r io.Reader
+	if s, ok := R.(apl.String); ok {
+		r = strings.NewReader(string(s))
+	} else {
+		r = bufio.NewReader(apl.NewChannelReader(a, R.(apl.Channel)))
+	}
+	return a.ParseCsv(prototype, r, c.comma, c.header)
+}
+
+// csvArgs are the options for parsing csv data.
+type csvArgs struct {
+	comma  rune
+	header bool
+}
+
+var defaultCsv = csvArgs{comma: ',', header: true}
+
+// csvOptions returns the options for parsing csv data.
+// O is the delimiter, or a dict with the keys delimiter and header.
+// The delimiter is a single character, the default is a comma.
+// If header is 0, the data has no header line and the keys are taken from the prototype.
+func csvOptions(a *apl.Apl, O apl.Value) (csvArgs, error) {
+	c := defaultCsv
+	delimiter := func(v apl.Value) error {
+		if s, ok := v.(apl.String); ok == false || utf8.RuneCountInString(string(s)) != 1 {
+			return fmt.Errorf("parse csv: delimiter must be a single character: %s", v.String(a))
+		} else {
+			c.comma, _ = utf8.DecodeRuneInString(string(s))
+		}
+		return nil
+	}
+	if _, ok := O.(apl.String); ok {
+		return c, delimiter(O)
+	}
+	d, ok := O.(*apl.Dict)
+	if ok == false {
+		return c, fmt.Errorf("parse csv: options must be a delimiter or a dict: %T", O)
+	}
+	for _, k := range d.K {
+		v := d.M[k]
+		switch k {
+		case apl.String("delimiter"):
+			if err := delimiter(v); err != nil {
+				return c, err
+			}
+		case apl.String("header"):
+			n, ok := v.(apl.Number)
+			if ok == false {
+				return c, fmt.Errorf("parse csv: header must be 0 or 1: %s", v.String(a))
+			}
+			if i, ok := n.ToIndex(); ok == false || (i != 0 && i != 1) {
+				return c, fmt.Errorf("parse csv: header must be 0 or 1: %s", v.String(a))
+			} else {
+				c.header = i == 1
+			}
+		default:
+			return c, fmt.Errorf("parse csv: unknown option: %s", k.String(a))
+		}
+	}
+	return c, nil
+}
@@ -25,15 +25,51 @@ func init() {
 	register(primitive{
 		symbol: "⍋",
 		doc:    "grade up with collating sequence",
-		Domain: Dyadic(Split(IsVector(nil), IsArray(nil))),
+		Domain: Dyadic(Split(IsArray(nil), IsArray(nil))),
 		fn:     grade2(true),
 	})
 	register(primitive{
 		symbol: "⍒",
 		doc:    "grade down with collating sequence",
-		Domain: Dyadic(Split(IsVector(nil), IsArray(nil))),
+		Domain: Dyadic(Split(IsArray(nil), IsArray(nil))),
 		fn:     grade2(false),
 	})
+	register(primitive{
+		symbol: "⍋",
+		doc:    "grade up table",
+		Domain: Monadic(IsTable(nil)),
+		fn:     gradeTable(true),
+	})
+	register(primitive{
+		symbol: "⍒",
+		doc:    "grade down table",
+		Domain: Monadic(IsTable(nil)),
+		fn:     gradeTable(false),
+	})
+	register(primitive{
+		symbol: "⍋",
+		doc:    "grade up table by columns",
+		Domain: Dyadic(Split(nil, IsTable(nil))),
+		fn:     gradeTable(true),
+	})
+	register(primitive{
+		symbol: "⍒",
+		doc:    "grade down table by columns",
+		Domain: Dyadic(Split(nil, IsTable(nil))),
+		fn:     gradeTable(false),
+	})
+	register(primitive{
+		symbol: "⍋",
+		doc:    "sort table",
+		Domain: Monadic(tableAxis{}),
+		fn:     sortTable(true),
+	})
+	register(primitive{
+		symbol: "⍒",
+		doc:    "sort table descending",
+		Domain: Monadic(tableAxis{}),
+		fn:     sortTable(false),
+	})
 }
 
 func grade(up bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
@@ -69,7 +105,7 @@ func gradeSetup(a *apl.Apl, R apl.Value) (sortIndexes, error) {
 	if len(shape) == 1 {
 		// In the vector case, wrap the elements to a single element slice.
 		for i := range b {
-			b[i] = []apl.Value{ar.At(i)} // TODO: copy?
+			b[i] = []apl.Value{ar.At(i)}
 		}
 	} else {
 		subsize := apl.ArraySize(apl.MixedArray{Dims: shape[1:]})
@@ -77,7 +113,7 @@ func gradeSetup(a *apl.Apl, R apl.Value) (sortIndexes, error) {
 		for i := range b {
 			b[i] = make([]apl.Value, subsize)
 			for k := range b[i] {
-				b[i][k] = ar.At(off + k) // TODO: copy?
+				b[i][k] = ar.At(off + k)
 			}
 			off += subsize
 		}
@@ -169,10 +205,13 @@ func gradeSetup(a *apl.Apl, R apl.Value) (sortIndexes, error) {
 }
 
 // grade2 is the dyadic grade up/down.
-// It is only implemented for vector left arguments.
 // If L is a vector: L⍋R ←→ ⍋L⍳R
+// If L has a higher rank, see collate.
 func grade2(up bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
 	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		if al := L.(apl.Array); len(al.Shape()) > 1 {
+			return collate(a, al, R.(apl.Array), up)
+		}
 		LiotaR, err := indexof(a, L, R)
 		if err != nil {
 			return nil, err
@@ -182,6 +221,231 @@ func grade2(up bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
 	}
 }
 
+// collate grades R with a collating array L of rank k > 1.
+// Each element of R is located by it's coordinates in L, elements that are not in L
+// are sorted after all others.
+// The major cells of R are compared by the last coordinate of all their elements first,
+// e.g. the letter position, and on equality by the coordinates of the preceding axes, e.g. the case:
+//	(2 26⍴'ABC…Zabc…z')⍋R
+// sorts alphabetically and ties are resolved with uppercase first.
+func collate(a *apl.Apl, L, R apl.Array, up bool) (apl.Value, error) {
+	ls := L.Shape()
+	pos := make(map[apl.Value]int)
+	for i := L.Size() - 1; i >= 0; i-- {
+		v := L.At(i)
+		if _, ok := v.(apl.Array); ok {
+			return nil, fmt.Errorf("grade: collating array must contain scalars")
+		}
+		pos[v] = i
+	}
+	coords := func(v apl.Value, c []int) {
+		p, ok := pos[v]
+		for d := len(ls) - 1; d >= 0; d-- {
+			if ok == false {
+				c[d] = ls[d]
+			} else {
+				c[d] = p % ls[d]
+				p /= ls[d]
+			}
+		}
+	}
+
+	rs := R.Shape()
+	n, cellsize := 1, 1
+	if len(rs) > 0 {
+		n = rs[0]
+		for _, d := range rs[1:] {
+			cellsize *= d
+		}
+	}
+	k := len(ls)
+	keys := make([][]int, n)
+	c := make([]int, k)
+	for i := range keys {
+		keys[i] = make([]int, k*cellsize)
+		for e := 0; e < cellsize; e++ {
+			v := R.At(i*cellsize + e)
+			if _, ok := v.(apl.Array); ok {
+				return nil, fmt.Errorf("grade: cannot collate nested arrays")
+			}
+			coords(v, c)
+			for d := range c {
+				keys[i][(k-1-d)*cellsize+e] = c[d]
+			}
+		}
+	}
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		x, y := keys[idx[i]], keys[idx[j]]
+		for m := range x {
+			if x[m] != y[m] {
+				return (x[m] < y[m]) == up
+			}
+		}
+		return false
+	})
+	for i := range idx {
+		idx[i] += a.Origin
+	}
+	return apl.IntArray{Dims: []int{n}, Ints: idx}, nil
+}
+
+// gradeTable grades the rows of a table lexicographically.
+// Without a left argument, all columns are compared in order.
+// L may be a column name or a vector of column names, or a dict with
+// the column names as keys and 1 or ¯1 for ascending or descending order, e.g.
+//	(`Sym`Time#1 ¯1)⍋T
+// Grade down reverses all directions.
+// Rows with equal values keep their order.
+func gradeTable(up bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
+	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		idx, _, err := tableOrder(a, L, R.(apl.Table), up)
+		if err != nil {
+			return nil, err
+		}
+		if len(idx) == 0 {
+			return apl.EmptyArray{}, nil
+		}
+		for i := range idx {
+			idx[i] += a.Origin
+		}
+		return apl.IntArray{Dims: []int{len(idx)}, Ints: idx}, nil
+	}
+}
+
+// sortTable sorts a table by the columns given as the axis, which is the same as the left argument
+// of a table grade: ⍋[L]T ←→ T[L⍋T].
+// The leading columns in ascending order are recorded as the sort columns of the result.
+func sortTable(up bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
+	return func(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+		ax := R.(apl.Axis)
+		t := ax.R.(apl.Table)
+		idx, sorted, err := tableOrder(a, ax.A, t, up)
+		if err != nil {
+			return nil, err
+		}
+		s := takeRows(a, t, idx)
+		s.Sorted = sorted
+		return s, nil
+	}
+}
+
+// tableOrder returns the zero based sort index of the table and the leading ascending columns.
+func tableOrder(a *apl.Apl, L apl.Value, t apl.Table, up bool) ([]int, []apl.Value, error) {
+	cols, asc, err := gradeColumns(a, L, t)
+	if err != nil {
+		return nil, nil, err
+	}
+	ranks := make([][]int, len(cols))
+	for i, k := range cols {
+		asc[i] = asc[i] == up
+		if ranks[i], err = columnRanks(a, t.M[k], t.Rows); err != nil {
+			return nil, nil, fmt.Errorf("grade table: column %s: %w", k.String(a), err)
+		}
+	}
+
+	idx := make([]int, t.Rows)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		for n, r := range ranks {
+			x, y := r[idx[i]], r[idx[j]]
+			if x != y {
+				return (x < y) == asc[n]
+			}
+		}
+		return false
+	})
+
+	var sorted []apl.Value
+	for i := range cols {
+		if asc[i] == false {
+			break
+		}
+		sorted = append(sorted, cols[i])
+	}
+	return idx, sorted, nil
+}
+
+// gradeColumns returns the columns and their directions for a table grade.
+func gradeColumns(a *apl.Apl, L apl.Value, t apl.Table) ([]apl.Value, []bool, error) {
+	var cols []apl.Value
+	var asc []bool
+	if d, ok := L.(*apl.Dict); ok {
+		for _, k := range d.K {
+			n, ok := d.M[k].(apl.Number)
+			if ok == false {
+				return nil, nil, fmt.Errorf("grade table: direction must be 1 or ¯1: %s", d.M[k].String(a))
+			}
+			if i, ok := n.ToIndex(); ok && (i == 1 || i == -1) {
+				cols = append(cols, k)
+				asc = append(asc, i == 1)
+			} else {
+				return nil, nil, fmt.Errorf("grade table: direction must be 1 or ¯1: %s", n.String(a))
+			}
+		}
+	} else if L == nil {
+		cols = t.K
+	} else if as, ok := ToStringArray(IsVector(nil)).To(a, L); ok {
+		for _, s := range as.(apl.StringArray).Strings {
+			cols = append(cols, apl.String(s))
+		}
+	} else {
+		return nil, nil, fmt.Errorf("grade table: columns must be a string vector or a dict: %T", L)
+	}
+	if asc == nil {
+		asc = make([]bool, len(cols))
+		for i := range asc {
+			asc[i] = true
+		}
+	}
+	for _, k := range cols {
+		if _, ok := t.M[k]; ok == false {
+			return nil, nil, apl.Errorf(apl.IndexError, "grade table: column does not exist: %s", k.String(a))
+		}
+	}
+	return cols, asc, nil
+}
+
+// columnRanks returns the rank of each value in the column.
+// Equal values have the same rank.
+func columnRanks(a *apl.Apl, col apl.Value, rows int) ([]int, error) {
+	ranks := make([]int, rows)
+	if rows == 0 {
+		return ranks, nil
+	}
+	si, err := gradeSetup(a, col)
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(si)
+	r := 0
+	for i := range si.idx {
+		if i > 0 && si.Less(i-1, i) {
+			r++
+		}
+		ranks[si.idx[i]-a.Origin] = r
+	}
+	return ranks, nil
+}
+
+// tableAxis accepts an axis with a table as the right argument.
+type tableAxis struct{}
+
+func (t tableAxis) To(a *apl.Apl, R apl.Value) (apl.Value, bool) {
+	if ax, ok := R.(apl.Axis); ok {
+		if _, ok := ax.R.(apl.Table); ok {
+			return R, true
+		}
+	}
+	return R, false
+}
+func (t tableAxis) String(a *apl.Apl) string { return "[columns] table" }
+
 type sortIndexes struct {
 	b   [][]apl.Value
 	idx []int
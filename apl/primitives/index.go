@@ -37,7 +37,7 @@ func init() {
 		doc:    "index table, []",
 		Domain: Dyadic(Split(indexSpec{}, IsTable(nil))),
 		fn:     tableIndex,
-		//sel:    tableSelection,
+		sel:    tableSelection,
 	})
 }
 
@@ -84,7 +84,7 @@ func index(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		if err := apl.ArrayBounds(ar, n); err != nil {
 			return nil, err
 		}
-		res.Values[i] = ar.At(n) // TODO copy?
+		res.Values[i] = ar.At(n)
 	}
 	return res, nil
 }
@@ -129,7 +129,7 @@ func objSelection(a *apl.Apl, L, R apl.Value) (apl.IntArray, error) {
 					return apl.IntArray{Dims: []int{1}, Ints: []int{len(keys) + a.Origin}}, nil
 				}
 			} else {
-				return apl.IntArray{}, fmt.Errorf("key does not exist: %s", spec[0].String(a))
+				return apl.IntArray{}, apl.Errorf(apl.IndexError, "key does not exist: %s", spec[0].String(a))
 			}
 		} else {
 			return apl.IntArray{Dims: []int{1}, Ints: []int{idx}}, nil
@@ -149,7 +149,7 @@ func objSelection(a *apl.Apl, L, R apl.Value) (apl.IntArray, error) {
 					keys[key] = k
 				}
 			} else {
-				return apl.IntArray{}, fmt.Errorf("key does not exist: %s", key.String(a))
+				return apl.IntArray{}, apl.Errorf(apl.IndexError, "key does not exist: %s", key.String(a))
 			}
 		}
 		ai.Ints[i] = k
@@ -199,7 +199,7 @@ func indexArray(a *apl.Apl, spec apl.IdxSpec, shape []int) (apl.IntArray, error)
 // spec is origin dependent, the result has always origin 0.
 func spec2ints(a *apl.Apl, spec apl.IdxSpec, shape []int) ([][]int, error) {
 	if len(spec) != len(shape) {
-		return nil, fmt.Errorf("indexing: array and index specification have different rank")
+		return nil, apl.Errorf(apl.RankError, "indexing: array and index specification have different rank")
 	}
 
 	to := ToIndexArray(nil)
@@ -222,7 +222,7 @@ func spec2ints(a *apl.Apl, spec apl.IdxSpec, shape []int) ([][]int, error) {
 		idx[i] = make([]int, len(ia.Ints))
 		for k := range ia.Ints {
 			if n := ia.Ints[k] - a.Origin; n < 0 || n >= shape[i] {
-				return nil, fmt.Errorf("index specification for axis %d is out of range", i+1)
+				return nil, apl.Errorf(apl.IndexError, "index specification for axis %d is out of range", i+1)
 			} else {
 				idx[i][k] = n
 			}
@@ -246,7 +246,7 @@ func objIndex(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	if ok == false {
 		v := obj.At(a, spec[0])
 		if v == nil {
-			return nil, fmt.Errorf("key does not exist")
+			return nil, apl.Errorf(apl.IndexError, "key does not exist")
 		}
 		return v, nil
 	}
@@ -262,10 +262,10 @@ func objIndex(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		key := sv.At(i)
 		v := obj.At(a, key)
 		if v == nil {
-			return nil, fmt.Errorf("key does not exist: %s", key.String(a))
+			return nil, apl.Errorf(apl.IndexError, "key does not exist: %s", key.String(a))
 		}
-		k[i] = key // TODO: copy?
-		m[key] = v // TODO: copy?
+		k[i] = key
+		m[key] = v
 	}
 	return &apl.Dict{K: k, M: m}, nil
 }
@@ -285,12 +285,12 @@ func listIndex(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	for i, k := range idx {
 		v := lst[k]
 		if i == len(idx)-1 {
-			return v, nil // TODO: copy?
+			return v, nil
 		} else {
 			lst = v.(apl.List)
 		}
 	}
-	return lst, nil // TODO: copy?
+	return lst, nil
 }
 
 // listSelection returns the index for selective assignment.
@@ -329,7 +329,7 @@ func listSelection(a *apl.Apl, L, R apl.Value) (apl.IntArray, error) {
 			k = len(lst) + k
 		}
 		if k < 0 || k >= len(lst) {
-			return ai, fmt.Errorf("list index out of range")
+			return ai, apl.Errorf(apl.IndexError, "list index out of range")
 		}
 		idx[i] = k
 		v := lst[k]
@@ -388,7 +388,7 @@ func tableIndex(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 			rows[i] = rows[i] + t.Rows
 		}
 		if rows[i] < 0 || rows[i] >= t.Rows {
-			return nil, fmt.Errorf("table index out of range")
+			return nil, apl.Errorf(apl.IndexError, "table index out of range")
 		}
 	}
 
@@ -407,7 +407,7 @@ func tableIndex(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	if len(spec) == 2 && len(keys) == 1 && len(rows) == 1 {
 		col := t.At(a, keys[0])
 		if col == nil {
-			return nil, fmt.Errorf("table index: column does not exist")
+			return nil, apl.Errorf(apl.IndexError, "table index: column does not exist")
 		}
 		ar := col.(apl.Array)
 		return ar.At(rows[0]), nil
@@ -419,10 +419,10 @@ func tableIndex(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		nrows = t.Rows
 	}
 	for i, k := range keys {
-		d.K[i] = k // TODO: copy?
+		d.K[i] = k
 		col := t.At(a, k)
 		if col == nil {
-			return nil, fmt.Errorf("table index: column does not exist")
+			return nil, apl.Errorf(apl.IndexError, "table index: column does not exist")
 		}
 		ar := col.(apl.Array)
 		as := apl.MakeArray(ar, []int{nrows})
@@ -443,3 +443,84 @@ func tableIndex(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	}
 	return dict2table(a, &d) // table.go
 }
+
+// tableSelection returns the index for selective assignment to a table.
+// The result has the shape rows × columns and contains the flat index
+// row×n+column into a virtual array, where n is the number of columns.
+// The columns are in the order of the table's keys.
+// Assignment to a column that does not exist, creates a new column.
+func tableSelection(a *apl.Apl, L, R apl.Value) (apl.IntArray, error) {
+	t := R.(apl.Table)
+	spec := L.(apl.IdxSpec)
+	if len(spec) < 1 || len(spec) > 2 {
+		return apl.IntArray{}, fmt.Errorf("table index: index spec must have one or two fields")
+	}
+
+	var rows []int
+	if _, ok := spec[0].(apl.EmptyArray); ok {
+		rows = make([]int, t.Rows)
+		for i := range rows {
+			rows[i] = i
+		}
+	} else if ai, ok := ToIndexArray(IsVector(nil)).To(a, spec[0]); ok == false {
+		return apl.IntArray{}, fmt.Errorf("table index: first part must be an index or index vector: %T", spec[0])
+	} else {
+		ints := ai.(apl.IntArray).Ints
+		rows = make([]int, len(ints))
+		for i, n := range ints {
+			n -= a.Origin
+			if n < 0 {
+				n += t.Rows
+			}
+			if n < 0 || n >= t.Rows {
+				return apl.IntArray{}, apl.Errorf(apl.IndexError, "table index out of range")
+			}
+			rows[i] = n
+		}
+	}
+
+	keys := t.Keys()
+	if len(spec) == 2 {
+		if _, ok := spec[1].(apl.EmptyArray); ok == false {
+			as, ok := ToStringArray(IsVector(nil)).To(a, spec[1])
+			if ok == false {
+				return apl.IntArray{}, fmt.Errorf("table index: second part must be a string or string vector: %T", spec[1])
+			}
+			strings := as.(apl.StringArray).Strings
+			keys = make([]apl.Value, len(strings))
+			for i, s := range strings {
+				keys[i] = apl.String(s)
+			}
+		}
+	}
+
+	cols := make(map[apl.Value]int)
+	for i, k := range t.K {
+		cols[k] = i
+	}
+	colidx := make([]int, len(keys))
+	for i, k := range keys {
+		c, ok := cols[k]
+		if ok == false {
+			// Index-assignment into a non-existing column creates a new column.
+			m := apl.MixedArray{Dims: []int{t.Rows}, Values: make([]apl.Value, t.Rows)}
+			for n := range m.Values {
+				m.Values[n] = apl.Int(0)
+			}
+			c = len(t.K)
+			t.K = append(t.K, k)
+			t.M[k] = m
+			cols[k] = c
+		}
+		colidx[i] = c
+	}
+
+	n := len(t.K)
+	ai := apl.IntArray{Dims: []int{len(rows), len(colidx)}, Ints: make([]int, len(rows)*len(colidx))}
+	for i, r := range rows {
+		for j, c := range colidx {
+			ai.Ints[i*len(colidx)+j] = r*n + c
+		}
+	}
+	return ai, nil
+}
@@ -0,0 +1,248 @@
+package primitives
+
+import (
+	"fmt"
+
+	"github.com/ktye/iv/apl"
+)
+
+func init() {
+	inverses = []inverse{
+		{"+", "", invAdd},
+		{"+", "⍨", invAddCommute},
+		{"-", "", invSub},
+		{"-", "⍨", invSubCommute},
+		{"×", "", invMul},
+		{"×", "⍨", invMulCommute},
+		{"÷", "", invDiv},
+		{"÷", "⍨", invDivCommute},
+		{"*", "", invPow},
+		{"*", "⍨", invPowCommute},
+		{"⍟", "", invLog},
+		{"⍟", "⍨", invLogCommute},
+		{"○", "", invCircular},
+		{"⊤", "", invEncode},
+		{"⊥", "", invDecode},
+		{"⍉", "", invTranspose},
+		{"⌽", "", invRotate("⌽")},
+		{"⊖", "", invRotate("⊖")},
+		{",", "", invCatenate},
+		{"⊢", "", invRight},
+	}
+}
+
+var inverses []inverse
+
+// inverse is the inverse of a primitive function, used by the power operator f⍣¯1.
+// It is called with the right argument and an optional bound left argument
+// and returns X, such that f X ←→ R or L f X ←→ R.
+// Inverses of commuted functions (op is ⍨) solve for the left argument:
+// X f L ←→ R.
+type inverse struct {
+	symbol string
+	op     string
+	fn     func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error)
+}
+
+// call applies the primitive function with the given symbol.
+func call(a *apl.Apl, symbol string, L, R apl.Value) (apl.Value, error) {
+	return apl.Primitive(symbol).Call(a, L, R)
+}
+
+func noInverse(symbol string, L apl.Value) error {
+	if L == nil {
+		return fmt.Errorf("domain error: monadic %s has no inverse", symbol)
+	}
+	return fmt.Errorf("domain error: dyadic %s has no inverse", symbol)
+}
+
+// + conjugate is it's own inverse, L+X=R: X←R-L.
+func invAdd(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		return call(a, "+", nil, R)
+	}
+	return call(a, "-", R, L)
+}
+
+// X+X=R: X←R÷2, X+L=R: X←R-L.
+func invAddCommute(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		return call(a, "÷", R, apl.Int(2))
+	}
+	return call(a, "-", R, L)
+}
+
+// -X=R: X←-R, L-X=R: X←L-R.
+func invSub(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		return call(a, "-", nil, R)
+	}
+	return call(a, "-", L, R)
+}
+
+// X-L=R: X←R+L.
+func invSubCommute(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		return nil, noInverse("-⍨", L)
+	}
+	return call(a, "+", R, L)
+}
+
+// Signum has no inverse, L×X=R: X←R÷L.
+func invMul(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		return nil, noInverse("×", L)
+	}
+	return call(a, "÷", R, L)
+}
+
+// X×X=R: X←R*0.5, X×L=R: X←R÷L.
+func invMulCommute(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		h, err := call(a, "÷", apl.Int(1), apl.Int(2))
+		if err != nil {
+			return nil, err
+		}
+		return call(a, "*", R, h)
+	}
+	return call(a, "÷", R, L)
+}
+
+// ÷ reciprocal is it's own inverse, L÷X=R: X←L÷R.
+func invDiv(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		return call(a, "÷", nil, R)
+	}
+	return call(a, "÷", L, R)
+}
+
+// X÷L=R: X←R×L.
+func invDivCommute(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		return nil, noInverse("÷⍨", L)
+	}
+	return call(a, "×", R, L)
+}
+
+// *X=R: X←⍟R, L*X=R: X←L⍟R.
+func invPow(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	return call(a, "⍟", L, R)
+}
+
+// X*L=R: X←R*÷L.
+func invPowCommute(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		return nil, noInverse("*⍨", L)
+	}
+	l, err := call(a, "÷", nil, L)
+	if err != nil {
+		return nil, err
+	}
+	return call(a, "*", R, l)
+}
+
+// ⍟X=R: X←*R, L⍟X=R: X←L*R.
+func invLog(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	return call(a, "*", L, R)
+}
+
+// X⍟L=R: X←L*÷R.
+func invLogCommute(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		return nil, noInverse("⍟⍨", L)
+	}
+	r, err := call(a, "÷", nil, R)
+	if err != nil {
+		return nil, err
+	}
+	return call(a, "*", L, r)
+}
+
+// ○X=R: X←R÷○1, L○X=R: X←(-L)○R.
+func invCircular(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		pi, err := call(a, "○", nil, apl.Int(1))
+		if err != nil {
+			return nil, err
+		}
+		return call(a, "÷", R, pi)
+	}
+	l, err := call(a, "-", nil, L)
+	if err != nil {
+		return nil, err
+	}
+	return call(a, "○", l, R)
+}
+
+// L⊤X=R: X←L⊥R.
+func invEncode(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		return nil, noInverse("⊤", L)
+	}
+	return call(a, "⊥", L, R)
+}
+
+// L⊥X=R: X←L⊤R.
+func invDecode(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		return nil, noInverse("⊥", L)
+	}
+	return call(a, "⊤", L, R)
+}
+
+// ⍉ is it's own inverse, L⍉X=R: X←(⍋L)⍉R.
+func invTranspose(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if L == nil {
+		return call(a, "⍉", nil, R)
+	}
+	g, err := call(a, "⍋", nil, L)
+	if err != nil {
+		return nil, err
+	}
+	return call(a, "⍉", g, R)
+}
+
+// Reverse is it's own inverse, L⌽X=R: X←(-L)⌽R.
+func invRotate(symbol string) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
+	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		if L == nil {
+			return call(a, symbol, nil, R)
+		}
+		l, err := call(a, "-", nil, L)
+		if err != nil {
+			return nil, err
+		}
+		return call(a, symbol, l, R)
+	}
+}
+
+// The inverse of ravel is only known for vectors, where the shape is preserved.
+// L,X=R: X←(0 0 … n)↓R, where n is the length of the last axis of L,
+// or 1 if L has a lower rank than R.
+// The under operator restores the shape after ravel, see operators/under.go.
+func invCatenate(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	ar, ok := R.(apl.Array)
+	if L == nil {
+		if ok && len(ar.Shape()) == 1 {
+			return R, nil
+		}
+		return nil, fmt.Errorf("domain error: inverse of ravel requires a vector")
+	}
+	if ok == false {
+		return nil, noInverse(",", L)
+	}
+	rs := ar.Shape()
+	n := 1
+	if al, ok := L.(apl.Array); ok && len(al.Shape()) == len(rs) {
+		ls := al.Shape()
+		n = ls[len(ls)-1]
+	}
+	d := apl.IntArray{Dims: []int{len(rs)}, Ints: make([]int, len(rs))}
+	d.Ints[len(rs)-1] = n
+	return call(a, "↓", d, R)
+}
+
+// ⊢ is it's own inverse, also with a bound left argument.
+func invRight(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	return R, nil
+}
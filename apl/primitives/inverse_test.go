@@ -0,0 +1,78 @@
+package primitives
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/numbers"
+	"github.com/ktye/iv/apl/operators"
+	"github.com/ktye/iv/apl/xgo"
+)
+
+// scale is a go function value that multiplies by an integer.
+// It is comparable and can be registered with an inverse.
+type scale int
+
+func (s scale) String(a *apl.Apl) string { return "scale" }
+func (s scale) Call(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	return apl.Primitive("×").Call(a, apl.Int(s), R)
+}
+
+// TestRegisterInverse registers inverses for functions that are not primitives:
+// a go Function value and an xgo function.
+// They are used by the power operator with a negative right operand and by under.
+func TestRegisterInverse(t *testing.T) {
+	var buf strings.Builder
+	a := apl.New(&buf)
+	numbers.Register(a)
+	Register(a)
+	operators.Register(a)
+
+	if err := a.RegisterInverse(scale(2), "", apl.ToFunction(func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		return apl.Primitive("÷").Call(a, R, apl.Int(2))
+	})); err != nil {
+		t.Fatal(err)
+	}
+	double := xgo.Function{Name: "double", Fn: reflect.ValueOf(func(i int) int { return 2 * i })}
+	half := xgo.Function{Name: "half", Fn: reflect.ValueOf(func(i int) int { return i / 2 })}
+	if err := a.RegisterInverse(double, "", half); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.RegisterInverse(apl.ToFunction(nil), "", half); err == nil {
+		t.Fatal("expected error: a func is not comparable")
+	}
+	a.Assign("s", scale(2))
+	a.Assign("d", double)
+	a.Assign("h", half)
+
+	testCases := []struct {
+		in, exp string
+	}{
+		{"s⍣¯1⊢8", "4"},
+		{"s⍣¯2⊢8", "2"},
+		{"2+⍢s 3", "5"},
+		{"d 3", "6"},
+		{"d⍣¯1⊢8", "4"},
+		{"2+⍢d 3", "5"},
+		{"-⍢d 3", "¯3"},
+		{"h⍣¯1⊢8", "fail: function has no inverse"},
+	}
+	for _, tc := range testCases {
+		buf.Reset()
+		err := a.ParseAndEval(tc.in)
+		got := strings.TrimSpace(buf.String())
+		if strings.HasPrefix(tc.exp, "fail: ") {
+			if err == nil || strings.Contains(err.Error(), tc.exp[6:]) == false {
+				t.Fatalf("%s: expected %q, got %v", tc.in, tc.exp, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %s", tc.in, err)
+		} else if got != tc.exp {
+			t.Fatalf("%s: expected %s got %s", tc.in, tc.exp, got)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package primitives
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/ktye/iv/apl"
 	. "github.com/ktye/iv/apl/domain"
@@ -14,6 +15,12 @@ func init() {
 		Domain: Monadic(ToScalar(ToIndex(nil))),
 		fn:     interval,
 	})
+	register(primitive{
+		symbol: "⍳",
+		doc:    "interval, strings are not in the domain",
+		Domain: Monadic(IsString(nil)),
+		fn:     intervalString,
+	})
 	register(primitive{
 		symbol: "⍳",
 		doc:    `index of, first occurrence`,
@@ -32,12 +39,29 @@ func init() {
 		Domain: Monadic(ToIndexArray(nil)),
 		fn:     where,
 	})
+	register(primitive{
+		symbol: "⍸",
+		doc:    "where",
+		Domain: Monadic(IsBoolArray(nil)),
+		fn:     whereBits,
+	})
 	register(primitive{
 		symbol: "⍸",
 		doc:    "interval index",
 		Domain: Dyadic(Split(IsVector(nil), IsArray(nil))),
 		fn:     intervalindex,
 	})
+	register(primitive{
+		symbol: "⍸",
+		doc:    "interval index table column",
+		Domain: tableColumnAxis{},
+		fn:     tableIntervalIndex,
+	})
+}
+
+// intervalString reports a domain error instead of falling through to "not implemented".
+func intervalString(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	return nil, apl.Errorf(apl.DomainError, "strings are not in the input domain of ⍳")
 }
 
 // interval: R: integer. index generator.
@@ -168,27 +192,35 @@ func where(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 	return res, nil
 }
 
+// whereBits is where for a boolean vector, that visits only the words that contain 1s.
+func whereBits(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	b := R.(apl.BoolArray)
+	if b.Size() == 0 {
+		return apl.EmptyArray{}, nil
+	} else if len(b.Dims) != 1 {
+		return nil, fmt.Errorf("where: only vectors are supported")
+	}
+	idx := b.Where()
+	if a.Origin != 0 {
+		for i := range idx {
+			idx[i] += a.Origin
+		}
+	}
+	return apl.IntArray{Dims: []int{len(idx)}, Ints: idx}, nil
+}
+
 // Intervalindex, L is a vector and R an array.
 // L must be sorted.
+// The interval is located by binary search for the first element of each major cell of R.
 func intervalindex(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	if _, ok := L.(apl.EmptyArray); ok {
 		return apl.EmptyArray{}, nil
 	}
 
-	// Test if values of L are increasing.
-	gradeup := grade(true)
-	gr, err := gradeup(a, nil, L)
-	if err != nil {
-		return nil, err
-	}
-	ia, ok := gr.(apl.IntArray)
-	if ok == false {
-		return nil, fmt.Errorf("intervalindex: cannot grade left argument")
-	}
-	for i := range ia.Ints {
-		if ia.Ints[i] != i+a.Origin {
-			return nil, fmt.Errorf("intervalindex: values of left argument must be increasing")
-		}
+	al := L.(apl.Array)
+	lt := lessThan(a)
+	if err := increasing(al, lt); err != nil {
+		return nil, fmt.Errorf("intervalindex: %w", err)
 	}
 
 	ar := R.(apl.Array)
@@ -198,39 +230,122 @@ func intervalindex(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		rn = apl.ArraySize(apl.MixedArray{Dims: rs[1:]})
 	}
 
-	al := L.(apl.Array)
-	ls := al.Shape()
-	n := ls[0]
-
-	fless := arith2("<", compare("<"))
-
 	res := apl.IntArray{
 		Dims: []int{rs[0]},
 		Ints: make([]int, rs[0]),
 	}
 	for i := 0; i < rs[0]; i++ {
-		r := ar.At(i * rn)
-		for k := 0; k < n; k++ {
-			ok, err := fless(a, r, al.At(k))
-			if err != nil {
-				return nil, err
-			}
-			if bool(ok.(apl.Bool)) == true {
-				res.Ints[i] = k - 1 + a.Origin
-				break
-			}
-			if k == n-1 {
-				res.Ints[i] = n - 1 + a.Origin
-			}
+		k, err := intervalSearch(al, ar.At(i*rn), lt)
+		if err != nil {
+			return nil, err
 		}
+		res.Ints[i] = k - 1 + a.Origin
 	}
 	return res, nil
 }
 
+// tableIntervalIndex is the interval index on a column of a table, given as the axis: T⍸[`Time]X.
+// It returns the index of the last row with a value that is not greater than each value of X.
+// The column must be sorted. This is tested, unless the table is sorted by the column, see apl.Table.
+func tableIntervalIndex(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	t := L.(apl.Table)
+	ax := R.(apl.Axis)
+	if v, ok := ax.A.(apl.Array); ok {
+		if v.Size() != 1 {
+			return nil, fmt.Errorf("intervalindex: axis must be a single column")
+		}
+		ax.A = v.At(0)
+	}
+	col, ok := t.M[ax.A].(apl.Array)
+	if ok == false {
+		return nil, apl.Errorf(apl.IndexError, "intervalindex: column does not exist: %s", ax.A.String(a))
+	}
+
+	lt := lessThan(a)
+	if t.SortedBy(ax.A) == false {
+		if err := increasing(col, lt); err != nil {
+			return nil, fmt.Errorf("intervalindex: column %s: %w", ax.A.String(a), err)
+		}
+	}
+
+	ar, ok := ax.R.(apl.Array)
+	if ok == false {
+		k, err := intervalSearch(col, ax.R, lt)
+		if err != nil {
+			return nil, err
+		}
+		return apl.Int(k - 1 + a.Origin), nil
+	}
+	res := apl.IntArray{Dims: apl.CopyShape(ar), Ints: make([]int, ar.Size())}
+	for i := range res.Ints {
+		k, err := intervalSearch(col, ar.At(i), lt)
+		if err != nil {
+			return nil, err
+		}
+		res.Ints[i] = k - 1 + a.Origin
+	}
+	return res, nil
+}
+
+// lessThan returns a comparison function for scalars.
+func lessThan(a *apl.Apl) func(x, y apl.Value) (bool, error) {
+	fless := arith2("<", compare("<"))
+	return func(x, y apl.Value) (bool, error) {
+		b, err := fless(a, x, y)
+		if err != nil {
+			return false, err
+		}
+		return bool(b.(apl.Bool)), nil
+	}
+}
+
+// increasing returns an error, if the values of the vector l are not sorted in ascending order.
+func increasing(l apl.Array, lt func(x, y apl.Value) (bool, error)) error {
+	for i := 1; i < l.Size(); i++ {
+		if b, err := lt(l.At(i), l.At(i-1)); err != nil {
+			return err
+		} else if b {
+			return fmt.Errorf("values of left argument must be increasing")
+		}
+	}
+	return nil
+}
+
+// intervalSearch returns the number of values in the sorted vector l, that are not greater than x.
+func intervalSearch(l apl.Array, x apl.Value, lt func(x, y apl.Value) (bool, error)) (int, error) {
+	var err error
+	n := sort.Search(l.Size(), func(i int) bool {
+		if err != nil {
+			return true
+		}
+		var b bool
+		b, err = lt(x, l.At(i))
+		return b
+	})
+	return n, err
+}
+
+// tableColumnAxis accepts a table as the left argument and an axis on the right,
+// that does not contain a table.
+type tableColumnAxis struct{}
+
+func (t tableColumnAxis) To(a *apl.Apl, L, R apl.Value) (apl.Value, apl.Value, bool) {
+	if _, ok := L.(apl.Table); ok == false {
+		return L, R, false
+	}
+	if ax, ok := R.(apl.Axis); ok == false {
+		return L, R, false
+	} else if _, ok := ax.R.(apl.Table); ok {
+		return L, R, false
+	}
+	return L, R, true
+}
+func (t tableColumnAxis) String(a *apl.Apl) string { return "table [column] array" }
+
 // IsEqual compares if the values are equal.
 // If they are numbers of different type, they are converted before comparison.
+// Inexact numbers are compared with the comparison tolerance ⎕CT.
 func isEqual(a *apl.Apl, x, y apl.Value) bool {
-	// TODO: should we use CT (comparison tolerance)?
 	if x == y {
 		return true
 	}
@@ -242,12 +357,8 @@ func isEqual(a *apl.Apl, x, y apl.Value) bool {
 		return false
 	}
 	if xn, yn, err := a.Tower.SameType(xn.(apl.Number), yn.(apl.Number)); err == nil {
-		if eq, ok := xn.(equaler); ok {
-			if iseq, ok := eq.Equals(yn); ok {
-				return bool(iseq)
-			}
-		} else {
-			return xn == yn
+		if iseq, ok := equals(a, xn, yn); ok {
+			return bool(iseq)
 		}
 	}
 	return false
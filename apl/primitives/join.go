@@ -0,0 +1,297 @@
+package primitives
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ktye/iv/apl"
+)
+
+// The union join ∪ is registered in unique.go.
+func init() {
+	register(primitive{
+		symbol: ",",
+		doc:    "left join tables",
+		Domain: tablesWithAxis{},
+		fn:     leftJoin,
+	})
+	register(primitive{
+		symbol: "∩",
+		doc:    "inner join tables",
+		Domain: tablesWithAxis{},
+		fn:     innerJoin,
+	})
+	register(primitive{
+		symbol: "⍸",
+		doc:    "as-of join tables",
+		Domain: tablesWithAxis{},
+		fn:     asofJoin,
+	})
+}
+
+// Joins combine the tables L and R by the key columns given as the axis:
+//	L,[`Sym]R         left join
+//	L∩[`Sym]R         inner join
+//	L∪[`Sym]R         union join
+//	Q⍸[`Sym`Time]T    as-of join
+// R is used as a keyed table: for each key, the first row of R is used.
+// Rows with missing values in the key columns never match.
+//
+// The result contains the columns of L followed by the columns of R that are not in L.
+// Values of matching rows in columns that are not keys are taken from R.
+// If there is no matching row, they keep the value of L, or are set to the null value
+// of the column type, see apl.Nullable.
+//
+// Rows are matched by their ids in an apl.KeyIndex.
+
+// tablesWithAxis accepts a table as the left argument and an axis
+// with a table as the right argument.
+type tablesWithAxis struct{}
+
+func (t tablesWithAxis) To(a *apl.Apl, L, R apl.Value) (apl.Value, apl.Value, bool) {
+	if _, ok := L.(apl.Table); ok == false {
+		return L, R, false
+	}
+	if ax, ok := R.(apl.Axis); ok == false {
+		return L, R, false
+	} else if _, ok := ax.R.(apl.Table); ok == false {
+		return L, R, false
+	}
+	return L, R, true
+}
+func (t tablesWithAxis) String(a *apl.Apl) string { return "table [keys] table" }
+
+// leftJoin returns all rows of L, extended by the matching rows of R.
+func leftJoin(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	l, r, keys, err := joinArgs(a, L, R)
+	if err != nil {
+		return nil, err
+	}
+	ri := matchRows(a, l, r, keys)
+	li := make([]int, l.Rows)
+	for i := range li {
+		li[i] = i
+	}
+	return joinTables(a, l, r, keys, li, ri)
+}
+
+// innerJoin returns the rows of L, that have a matching row in R, extended by the values of R.
+func innerJoin(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	l, r, keys, err := joinArgs(a, L, R)
+	if err != nil {
+		return nil, err
+	}
+	m := matchRows(a, l, r, keys)
+	var li, ri []int
+	for i, k := range m {
+		if k >= 0 {
+			li = append(li, i)
+			ri = append(ri, k)
+		}
+	}
+	return joinTables(a, l, r, keys, li, ri)
+}
+
+// unionJoin returns all rows of L, updated by the matching rows of R,
+// followed by the rows of R with keys that are not in L.
+func unionJoin(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	l, r, keys, err := joinArgs(a, L, R)
+	if err != nil {
+		return nil, err
+	}
+	ri := matchRows(a, l, r, keys)
+	li := make([]int, l.Rows)
+	for i := range li {
+		li[i] = i
+	}
+
+	// Rows of R are appended, if they don't match any row of L.
+	rl := matchRows(a, r, l, keys)
+	for k, i := range rl {
+		if i < 0 {
+			li = append(li, -1)
+			ri = append(ri, k)
+		}
+	}
+	return joinTables(a, l, r, keys, li, ri)
+}
+
+// asofJoin joins each row of R with the last row of L, that has the same keys
+// and a value in the last key column, which is not greater than that of R.
+// The last key is usually a time column and must be sorted within each group of L.
+// This is not tested, if L has been sorted by the keys with ⍋[K]L, see apl.Table.
+// The result contains the rows of R in the same way as a left join R,[K]L.
+// It is the interval index ⍸ for tables, with L as the sorted argument.
+func asofJoin(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	q, t, keys, err := joinArgs(a, L, R)
+	if err != nil {
+		return nil, err
+	}
+	exact, tk := keys[:len(keys)-1], keys[len(keys)-1]
+	tq, tt := q.M[tk].(apl.Array), t.M[tk].(apl.Array)
+
+	// Group the rows of q by the exact keys.
+	qids, tids := make([]int, q.Rows), make([]int, t.Rows)
+	if len(exact) > 0 {
+		x := apl.NewKeyIndex(exact)
+		qids, tids = x.Ids(a, q, true), x.Ids(a, t, false)
+	}
+	groups := make(map[int][]int)
+	for i, k := range qids {
+		if k >= 0 && apl.IsNull(tq.At(i)) == false {
+			groups[k] = append(groups[k], i)
+		}
+	}
+
+	// The order is tested, unless L is known to be sorted by the keys.
+	lt := lessThan(a)
+	for _, g := range groups {
+		if q.SortedBy(keys...) {
+			break
+		}
+		for i := 1; i < len(g); i++ {
+			if b, err := lt(tq.At(g[i]), tq.At(g[i-1])); err != nil {
+				return nil, fmt.Errorf("as-of join: %w", err)
+			} else if b {
+				return nil, fmt.Errorf("as-of join: column %s of L is not sorted", tk.String(a))
+			}
+		}
+	}
+
+	ti := make([]int, t.Rows)
+	qi := make([]int, t.Rows)
+	for i := range ti {
+		ti[i] = i
+		qi[i] = -1
+		k := tids[i]
+		if k < 0 || apl.IsNull(tt.At(i)) {
+			continue
+		}
+		g := groups[k]
+		var err error
+		n := sort.Search(len(g), func(j int) bool {
+			if err != nil {
+				return true
+			}
+			var b bool
+			b, err = lt(tt.At(i), tq.At(g[j]))
+			return b
+		})
+		if err != nil {
+			return nil, fmt.Errorf("as-of join: %w", err)
+		}
+		if n > 0 {
+			qi[i] = g[n-1]
+		}
+	}
+	return joinTables(a, t, q, keys, ti, qi)
+}
+
+// joinArgs returns both tables and the key columns, which must exist in both.
+func joinArgs(a *apl.Apl, L, R apl.Value) (apl.Table, apl.Table, []apl.Value, error) {
+	ax := R.(apl.Axis)
+	l, r := L.(apl.Table), ax.R.(apl.Table)
+	var keys []apl.Value
+	if ar, ok := ax.A.(apl.Array); ok {
+		keys = make([]apl.Value, ar.Size())
+		for i := range keys {
+			keys[i] = ar.At(i)
+		}
+	} else {
+		keys = []apl.Value{ax.A}
+	}
+	if len(keys) == 0 {
+		return l, r, nil, fmt.Errorf("join: no key columns")
+	}
+	for _, k := range keys {
+		if _, ok := l.M[k]; ok == false {
+			return l, r, nil, fmt.Errorf("join: key column is missing in L: %s", k.String(a))
+		}
+		if _, ok := r.M[k]; ok == false {
+			return l, r, nil, fmt.Errorf("join: key column is missing in R: %s", k.String(a))
+		}
+	}
+	return l, r, keys, nil
+}
+
+// matchRows returns for each row of l the index of the first row in r with the same keys, or -1.
+func matchRows(a *apl.Apl, l, r apl.Table, keys []apl.Value) []int {
+	x := apl.NewKeyIndex(keys)
+	rows := make([]int, 0, r.Rows)
+	for i, id := range x.Ids(a, r, true) {
+		if id == len(rows) {
+			rows = append(rows, i)
+		}
+	}
+	idx := x.Ids(a, l, false)
+	for i, id := range idx {
+		if id >= 0 {
+			idx[i] = rows[id]
+		}
+	}
+	return idx
+}
+
+// joinTables builds the result of a join.
+// Each row of the result is given by the row indexes li and ri of l and r, which may be -1.
+func joinTables(a *apl.Apl, l, r apl.Table, keys []apl.Value, li, ri []int) (apl.Value, error) {
+	iskey := make(map[apl.Value]bool)
+	for _, k := range keys {
+		iskey[k] = true
+	}
+	d := apl.Dict{M: make(map[apl.Value]apl.Value)}
+	for _, k := range l.K {
+		d.K = append(d.K, k)
+	}
+	for _, k := range r.K {
+		if _, ok := l.M[k]; ok == false {
+			d.K = append(d.K, k)
+		}
+	}
+
+	rows := len(li)
+	for _, k := range d.K {
+		if rows == 0 {
+			d.M[k] = apl.EmptyArray{}
+			continue
+		}
+		lc, _ := l.M[k].(apl.Array)
+		rc, _ := r.M[k].(apl.Array)
+		var null apl.Value
+		m := apl.MixedArray{Dims: []int{rows}, Values: make([]apl.Value, rows)}
+		for n := range m.Values {
+			var v apl.Value
+			if rc != nil && ri[n] >= 0 && (iskey[k] == false || li[n] < 0) {
+				v = rc.At(ri[n])
+			} else if lc != nil && li[n] >= 0 {
+				v = lc.At(li[n])
+			} else {
+				if null == nil {
+					null = joinNull(a, lc, rc)
+				}
+				v = null
+			}
+			m.Values[n] = v
+		}
+		if u, ok := a.Unify(m, true); ok {
+			d.M[k] = u
+		} else {
+			d.M[k] = m
+		}
+	}
+	return apl.Table{Dict: &d, Rows: rows}, nil
+}
+
+// joinNull returns the value for a missing row in a joined column.
+// It is the null value of the column type, or the fill element if the type has no null value.
+func joinNull(a *apl.Apl, lc, rc apl.Array) apl.Value {
+	c := rc
+	if c == nil {
+		c = lc
+	}
+	v := a.Fill(c)
+	if n, ok := v.(apl.Nullable); ok {
+		return n.Null()
+	}
+	return v
+}
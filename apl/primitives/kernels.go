@@ -0,0 +1,516 @@
+package primitives
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/numbers"
+)
+
+// Kernels are fast paths for the scalar functions on uniform numeric arrays.
+// They work directly on the slices of apl.BoolArray, apl.IntArray, numbers.FloatArray
+// and numbers.ComplexArray and return uniform arrays, instead of calling
+// the elementary function for each element.
+//
+// The result must be the same as that of the generic path, including the types
+// of the elements, null propagation and the comparison tolerance.
+// A kernel returns false if it cannot guarantee this, e.g. for an inexact integer
+// division or a float division with an infinite result.
+// array1 and array2 continue with the generic path in this case.
+
+// kind is the numeric type of a kernel operand, in the order of the tower.
+type kind int
+
+const (
+	boolKind kind = iota
+	intKind
+	floatKind
+	complexKind
+)
+
+// operand is a uniform numeric argument of a kernel.
+// Only the slice of it's kind is set.
+// A scalar has a single element and the index mask 0, an array has the mask -1,
+// such that x[i&mask] addresses the elements of both.
+// Bools are packed into words, see apl.BoolArray. A scalar bool is a word
+// with all bits set to it's value.
+type operand struct {
+	kind kind
+	mask int
+	n    int
+	w    []uint64
+	i    []int
+	f    []float64
+	c    []complex128
+}
+
+func newOperand(v apl.Value) (operand, bool) {
+	switch x := v.(type) {
+	case apl.BoolArray:
+		return operand{kind: boolKind, mask: -1, n: x.Size(), w: x.Bits}, true
+	case apl.IntArray:
+		return operand{kind: intKind, mask: -1, n: len(x.Ints), i: x.Ints}, true
+	case numbers.FloatArray:
+		return operand{kind: floatKind, mask: -1, n: len(x.Floats), f: x.Floats}, true
+	case numbers.ComplexArray:
+		return operand{kind: complexKind, mask: -1, n: len(x.Cmplx), c: x.Cmplx}, true
+	case apl.Bool:
+		w := uint64(0)
+		if x {
+			w = ^w
+		}
+		return operand{kind: boolKind, n: 1, w: []uint64{w}}, true
+	case apl.Int:
+		return operand{kind: intKind, n: 1, i: []int{int(x)}}, true
+	case numbers.Float:
+		return operand{kind: floatKind, n: 1, f: []float64{float64(x)}}, true
+	case numbers.Complex:
+		return operand{kind: complexKind, n: 1, c: []complex128{complex128(x)}}, true
+	}
+	return operand{}, false
+}
+
+// to converts the operand to a higher kind in the same way as the numeric tower.
+// A null Int becomes NaN.
+func (o operand) to(k kind) operand {
+	for o.kind < k {
+		switch o.kind {
+		case boolKind:
+			o.i = make([]int, o.n)
+			for n := range o.i {
+				o.i[n] = int(o.w[n>>6]>>uint(n&63)) & 1
+			}
+		case intKind:
+			o.f = make([]float64, len(o.i))
+			for n, v := range o.i {
+				if v == int(apl.NullInt) {
+					o.f[n] = math.NaN()
+				} else {
+					o.f[n] = float64(v)
+				}
+			}
+		case floatKind:
+			o.c = make([]complex128, len(o.f))
+			for n, v := range o.f {
+				o.c[n] = complex(v, 0)
+			}
+		}
+		o.kind++
+	}
+	return o
+}
+
+// floatTower returns true, if integers are imported as numbers.Float.
+// Otherwise kernels do not mix integers with floats.
+func floatTower(a *apl.Apl) bool {
+	_, ok := a.Tower.Import(apl.Int(0)).(numbers.Float)
+	return ok
+}
+
+// kernel1 applies the monadic scalar function to the uniform array R.
+func kernel1(a *apl.Apl, symbol string, R apl.Value) (apl.Value, bool) {
+	r, ok := newOperand(R)
+	if ok == false || r.mask == 0 {
+		return nil, false
+	}
+	shape := apl.CopyShape(R.(apl.Array))
+	null := int(apl.NullInt)
+
+	if r.kind == boolKind {
+		switch symbol {
+		case "~":
+			z := apl.BoolArray{Dims: shape, Bits: make([]uint64, len(r.w))}
+			for i, x := range r.w {
+				z.Bits[i] = ^x
+			}
+			z.ClearTail()
+			return z, true
+		case "+", "-", "×", "⌊", "⌈":
+			r = r.to(intKind)
+		default:
+			return nil, false
+		}
+	}
+
+	switch r.kind {
+	case intKind:
+		var fn func(int) int
+		switch symbol {
+		case "+", "⌊", "⌈":
+			fn = func(x int) int { return x }
+		case "-":
+			fn = func(x int) int { return -x }
+		case "×":
+			fn = func(x int) int {
+				if x > 0 {
+					return 1
+				} else if x < 0 {
+					return -1
+				}
+				return 0
+			}
+		case "|":
+			fn = func(x int) int {
+				if x < 0 {
+					return -x
+				}
+				return x
+			}
+		case "~":
+			z := apl.NewBoolArray(shape, nil)
+			for i, x := range r.i {
+				if x != 0 && x != 1 {
+					return nil, false
+				} else if x == 0 {
+					z.Bits[i>>6] |= 1 << uint(i&63)
+				}
+			}
+			return z, true
+		default:
+			return nil, false
+		}
+		z := make([]int, len(r.i))
+		for i, x := range r.i {
+			if x == null {
+				z[i] = null
+			} else {
+				z[i] = fn(x)
+			}
+		}
+		return apl.IntArray{Dims: shape, Ints: z}, true
+
+	case floatKind:
+		if symbol == "×" {
+			z := make([]int, len(r.f))
+			for i, x := range r.f {
+				if x > 0 {
+					z[i] = 1
+				} else if x < 0 {
+					z[i] = -1
+				} else if math.IsNaN(x) {
+					return nil, false // The null is a Float.
+				}
+			}
+			return apl.IntArray{Dims: shape, Ints: z}, true
+		}
+		var fn func(float64) float64
+		switch symbol {
+		case "+":
+			fn = func(x float64) float64 { return x }
+		case "-":
+			fn = func(x float64) float64 { return -x }
+		case "|":
+			fn = math.Abs
+		case "⌊":
+			fn = tolerantFloor(a, math.Floor, math.Ceil)
+		case "⌈":
+			fn = tolerantFloor(a, math.Ceil, math.Floor)
+		case "÷":
+			fn = func(x float64) float64 { return 1 / x }
+		default:
+			return nil, false
+		}
+		z := make([]float64, len(r.f))
+		for i, x := range r.f {
+			if math.IsNaN(x) {
+				z[i] = x
+				continue
+			}
+			z[i] = fn(x)
+			if math.IsInf(z[i], 0) || math.IsNaN(z[i]) {
+				return nil, false // The result is an exception.
+			}
+		}
+		return numbers.FloatArray{Dims: shape, Floats: z}, true
+
+	case complexKind:
+		var fn func(complex128) complex128
+		switch symbol {
+		case "+":
+			fn = cmplx.Conj
+		case "-":
+			fn = func(x complex128) complex128 { return -x }
+		case "÷":
+			fn = func(x complex128) complex128 { return 1 / x }
+		default:
+			return nil, false
+		}
+		z := make([]complex128, len(r.c))
+		for i, x := range r.c {
+			z[i] = fn(x)
+			if symbol == "÷" && (cmplx.IsInf(z[i]) || cmplx.IsNaN(z[i])) {
+				return nil, false
+			}
+		}
+		return numbers.ComplexArray{Dims: shape, Cmplx: z}, true
+	}
+	return nil, false
+}
+
+// tolerantFloor returns the floor (or ceil) function that is used by min (max).
+// If x is within the comparison tolerance of the integer in the other direction, that is returned.
+func tolerantFloor(a *apl.Apl, floor, ceil func(float64) float64) func(float64) float64 {
+	ct := a.CT
+	if ct == 0 {
+		return floor
+	}
+	return func(x float64) float64 {
+		if n := ceil(x); n == x || math.Abs(x-n) <= ct*math.Max(math.Abs(x), math.Abs(n)) {
+			return n
+		}
+		return floor(x)
+	}
+}
+
+// kernel2 applies the dyadic scalar function to L and R.
+// Both are uniform arrays of the same shape, or one of them is a scalar.
+func kernel2(a *apl.Apl, symbol string, L, R apl.Value, shape []int) (apl.Value, bool) {
+	l, ok := newOperand(L)
+	if ok == false {
+		return nil, false
+	}
+	r, ok := newOperand(R)
+	if ok == false {
+		return nil, false
+	}
+	n := l.n
+	if l.mask == 0 {
+		n = r.n
+	}
+
+	k := l.kind
+	if r.kind > k {
+		k = r.kind
+	}
+	if k >= floatKind && (l.kind < floatKind || r.kind < floatKind) && floatTower(a) == false {
+		return nil, false
+	}
+
+	switch symbol {
+	case "^", "∧", "∨", "⍲", "⍱":
+		if k != boolKind {
+			return nil, false // Integers are lcm and gcd.
+		}
+		return boolKernel(symbol, l, r, shape), true
+	case "=", "≠", "<", ">", "≤", "≥":
+		if k == boolKind {
+			return boolKernel(symbol, l, r, shape), true
+		}
+		return compareKernel(a, symbol, l.to(k), r.to(k), n, shape)
+	case "⌊", "⌈":
+		if k == boolKind {
+			return boolKernel(symbol, l, r, shape), true
+		}
+	}
+	if k == boolKind {
+		k = intKind
+	}
+	l, r = l.to(k), r.to(k)
+
+	switch k {
+	case intKind:
+		fn := intKernels[symbol]
+		if fn == nil {
+			return nil, false
+		}
+		null := int(apl.NullInt)
+		z := make([]int, n)
+		for i := range z {
+			x, y := l.i[i&l.mask], r.i[i&r.mask]
+			if x == null || y == null {
+				z[i] = null
+			} else if v, ok := fn(x, y); ok {
+				z[i] = v
+			} else {
+				return nil, false
+			}
+		}
+		return apl.IntArray{Dims: shape, Ints: z}, true
+
+	case floatKind:
+		fn := floatKernels[symbol]
+		if fn == nil {
+			return nil, false
+		}
+		z := make([]float64, n)
+		for i := range z {
+			x, y := l.f[i&l.mask], r.f[i&r.mask]
+			if math.IsNaN(x) || math.IsNaN(y) {
+				z[i] = math.NaN()
+				continue
+			}
+			z[i] = fn(x, y)
+			if symbol == "÷" && (math.IsInf(z[i], 0) || math.IsNaN(z[i])) {
+				return nil, false // The result is an exception.
+			}
+		}
+		return numbers.FloatArray{Dims: shape, Floats: z}, true
+
+	case complexKind:
+		var fn func(x, y complex128) complex128
+		switch symbol {
+		case "+":
+			fn = func(x, y complex128) complex128 { return x + y }
+		case "-":
+			fn = func(x, y complex128) complex128 { return x - y }
+		case "×":
+			fn = func(x, y complex128) complex128 { return x * y }
+		case "÷":
+			fn = func(x, y complex128) complex128 { return x / y }
+		default:
+			return nil, false
+		}
+		z := make([]complex128, n)
+		for i := range z {
+			z[i] = fn(l.c[i&l.mask], r.c[i&r.mask])
+			if symbol == "÷" && (cmplx.IsInf(z[i]) || cmplx.IsNaN(z[i])) {
+				return nil, false
+			}
+		}
+		return numbers.ComplexArray{Dims: shape, Cmplx: z}, true
+	}
+	return nil, false
+}
+
+// intKernels are the dyadic functions for integers without nulls.
+// They return false, if the result is not an integer or if it overflows.
+var intKernels = map[string]func(x, y int) (int, bool){
+	"+": apl.AddInt,
+	"-": apl.SubInt,
+	"×": apl.MulInt,
+	"÷": func(x, y int) (int, bool) {
+		if y == 0 || x%y != 0 {
+			return 0, false
+		}
+		return x / y, true
+	},
+	"⌊": func(x, y int) (int, bool) {
+		if x < y {
+			return x, true
+		}
+		return y, true
+	},
+	"⌈": func(x, y int) (int, bool) {
+		if x < y {
+			return y, true
+		}
+		return x, true
+	},
+}
+
+// floatKernels are the dyadic functions for floats without nulls.
+var floatKernels = map[string]func(x, y float64) float64{
+	"+": func(x, y float64) float64 { return x + y },
+	"-": func(x, y float64) float64 { return x - y },
+	"×": func(x, y float64) float64 { return x * y },
+	"÷": func(x, y float64) float64 { return x / y },
+	"⌊": func(x, y float64) float64 {
+		if x < y {
+			return x
+		}
+		return y
+	},
+	"⌈": func(x, y float64) float64 {
+		if x < y {
+			return y
+		}
+		return x
+	},
+}
+
+// boolKernel applies a logical function or a comparison to bools.
+// It works on whole words, 64 elements at a time.
+func boolKernel(symbol string, l, r operand, shape []int) apl.BoolArray {
+	var fn func(x, y uint64) uint64
+	switch symbol {
+	case "^", "∧", "⌊":
+		fn = func(x, y uint64) uint64 { return x & y }
+	case "∨", "⌈":
+		fn = func(x, y uint64) uint64 { return x | y }
+	case "⍲":
+		fn = func(x, y uint64) uint64 { return ^(x & y) }
+	case "⍱":
+		fn = func(x, y uint64) uint64 { return ^(x | y) }
+	case "=":
+		fn = func(x, y uint64) uint64 { return ^(x ^ y) }
+	case "≠":
+		fn = func(x, y uint64) uint64 { return x ^ y }
+	case "<":
+		fn = func(x, y uint64) uint64 { return ^x & y }
+	case ">":
+		fn = func(x, y uint64) uint64 { return x &^ y }
+	case "≤":
+		fn = func(x, y uint64) uint64 { return ^x | y }
+	case "≥":
+		fn = func(x, y uint64) uint64 { return x | ^y }
+	}
+	z := apl.NewBoolArray(shape, nil)
+	for k := range z.Bits {
+		z.Bits[k] = fn(l.w[k&l.mask], r.w[k&r.mask])
+	}
+	z.ClearTail()
+	return z
+}
+
+// compareKernel compares l and r, which have the same kind, see equals and less.
+// Complex numbers can only be tested for equality.
+// Bools are compared by boolKernel.
+func compareKernel(a *apl.Apl, symbol string, l, r operand, n int, shape []int) (apl.Value, bool) {
+	var cmp func(eq, ls bool) bool
+	switch symbol {
+	case "=":
+		cmp = func(eq, ls bool) bool { return eq }
+	case "≠":
+		cmp = func(eq, ls bool) bool { return !eq }
+	case "<":
+		cmp = func(eq, ls bool) bool { return ls && !eq }
+	case ">":
+		cmp = func(eq, ls bool) bool { return !eq && !ls }
+	case "≤":
+		cmp = func(eq, ls bool) bool { return eq || ls }
+	case "≥":
+		cmp = func(eq, ls bool) bool { return eq || !ls }
+	}
+	ct := a.CT
+	z := apl.NewBoolArray(shape, nil)
+	set := func(i int, v bool) {
+		if v {
+			z.Bits[i>>6] |= 1 << uint(i&63)
+		}
+	}
+	switch l.kind {
+	case intKind:
+		// The null integer is the smallest integer and equal only to itself.
+		for i := 0; i < n; i++ {
+			x, y := l.i[i&l.mask], r.i[i&r.mask]
+			set(i, cmp(x == y, x < y))
+		}
+	case floatKind:
+		for i := 0; i < n; i++ {
+			x, y := l.f[i&l.mask], r.f[i&r.mask]
+			if xn, yn := math.IsNaN(x), math.IsNaN(y); xn || yn {
+				set(i, cmp(xn && yn, xn && !yn))
+				continue
+			}
+			eq := x == y
+			if eq == false && ct > 0 {
+				eq = math.Abs(x-y) <= ct*math.Max(math.Abs(x), math.Abs(y))
+			}
+			set(i, cmp(eq, x < y))
+		}
+	case complexKind:
+		if symbol != "=" && symbol != "≠" {
+			return nil, false
+		}
+		for i := 0; i < n; i++ {
+			x, y := l.c[i&l.mask], r.c[i&r.mask]
+			eq := x == y
+			if eq == false && ct > 0 {
+				eq = cmplx.Abs(x-y) <= ct*math.Max(cmplx.Abs(x), cmplx.Abs(y))
+			}
+			set(i, cmp(eq, false))
+		}
+	}
+	return z, true
+}
@@ -0,0 +1,189 @@
+package primitives
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/numbers"
+	"github.com/ktye/iv/apl/operators"
+)
+
+// TestKernels compares the results of the kernels for uniform arrays with the generic path.
+// The generic path is used for the same values in a mixed array.
+func TestKernels(t *testing.T) {
+	var buf strings.Builder
+	a := apl.New(&buf)
+	numbers.Register(a)
+	Register(a)
+	operators.Register(a)
+
+	r := rand.New(rand.NewSource(1))
+	dyadic := []string{"+", "-", "×", "÷", "⌊", "⌈", "=", "≠", "<", ">", "≤", "≥", "∧", "∨", "⍲", "⍱", "/", "⌿"}
+	monadic := []string{"+", "-", "×", "÷", "|", "⌊", "⌈", "~", "⍸", "+/", "×/", "⌊/", "⌈/", "∧/", "∨/", "+⌿", "⌈⌿"}
+	kinds := "bifc"
+	for _, ct := range []string{"1E¯14", "0"} {
+		if err := a.ParseAndEval("⎕CT←" + ct); err != nil {
+			t.Fatal(err)
+		}
+		for n := 0; n < 20; n++ {
+			for _, lk := range kinds {
+				for _, rk := range kinds {
+					shape := []int{1 + r.Intn(5), 1 + r.Intn(100)}
+					if n%2 == 0 {
+						shape = shape[1:]
+					}
+					L, ML := kernelArray(r, byte(lk), shape)
+					R, MR := kernelArray(r, byte(rk), shape)
+					if n%3 == 1 {
+						L = L.(apl.Array).At(0)
+						ML = L
+					}
+					args := map[string]apl.Value{"L": L, "R": R, "ML": ML, "MR": MR}
+					for _, f := range dyadic {
+						testKernel(t, a, args, "L"+f+"R", "ML"+f+"MR")
+					}
+					for _, f := range monadic {
+						testKernel(t, a, args, f+"R", f+"MR")
+					}
+				}
+			}
+		}
+	}
+}
+
+func testKernel(t *testing.T, a *apl.Apl, args map[string]apl.Value, expr, generic string) {
+	for k, v := range args {
+		if err := a.Assign(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err1 := a.ParseAndEval("Z←" + expr)
+	err2 := a.ParseAndEval("Z2←" + generic)
+	if (err1 == nil) != (err2 == nil) {
+		t.Fatalf("%s: error %v, generic: %v", expr, err1, err2)
+	} else if err1 != nil {
+		return
+	}
+	if z, z2 := a.Lookup("Z"), a.Lookup("Z2"); sameValues(z, z2) == false {
+		a.PP = -1
+		defer func() { a.PP = 0 }()
+		L, R := a.Lookup("L"), a.Lookup("R")
+		t.Fatalf("%s: L=%s R=%s\ngot %T %s\nexp %T %s", expr, L.String(a), R.String(a), z, z.String(a), z2, z2.String(a))
+	}
+}
+
+// sameValues compares x and y by their element types and values.
+func sameValues(x, y apl.Value) bool {
+	if xa, ok := x.(apl.Array); ok {
+		ya, ok := y.(apl.Array)
+		if ok == false || reflect.DeepEqual(xa.Shape(), ya.Shape()) == false {
+			return false
+		}
+		for i := 0; i < xa.Size(); i++ {
+			if sameValues(xa.At(i), ya.At(i)) == false {
+				return false
+			}
+		}
+		return true
+	}
+	if reflect.TypeOf(x) != reflect.TypeOf(y) {
+		return false
+	}
+	switch v := x.(type) {
+	case numbers.Float:
+		w := y.(numbers.Float)
+		return v == w || (math.IsNaN(float64(v)) && math.IsNaN(float64(w)))
+	case numbers.Complex:
+		w := y.(numbers.Complex)
+		return v == w || (cmplx.IsNaN(complex128(v)) && cmplx.IsNaN(complex128(w)))
+	}
+	return x == y
+}
+
+// kernelArray returns a random uniform array of the given kind and a mixed array with the same values.
+// The values are small integers, nulls and numbers close to integers.
+func kernelArray(r *rand.Rand, kind byte, shape []int) (apl.Value, apl.Value) {
+	m := apl.MixedArray{Dims: shape, Values: make([]apl.Value, apl.ArraySize(apl.IntArray{Dims: shape}))}
+	for i := range m.Values {
+		n := r.Intn(7) - 3
+		switch kind {
+		case 'b':
+			m.Values[i] = apl.Bool(n > 0)
+		case 'i':
+			m.Values[i] = apl.Int(n)
+			if r.Intn(8) == 0 {
+				m.Values[i] = apl.NullInt
+			}
+		case 'f':
+			f := float64(n) + []float64{0, 0.5, 1e-15, -1e-15}[r.Intn(4)]
+			if r.Intn(8) == 0 {
+				f = math.NaN()
+			}
+			m.Values[i] = numbers.Float(f)
+		case 'c':
+			m.Values[i] = numbers.Complex(complex(float64(n), float64(r.Intn(3)-1)))
+		}
+	}
+	var u apl.Value
+	switch kind {
+	case 'b':
+		u = apl.NewBoolArray(shape, nil)
+	case 'i':
+		u = apl.IntArray{Dims: shape, Ints: make([]int, len(m.Values))}
+	case 'f':
+		u = numbers.FloatArray{Dims: shape, Floats: make([]float64, len(m.Values))}
+	case 'c':
+		u = numbers.ComplexArray{Dims: shape, Cmplx: make([]complex128, len(m.Values))}
+	}
+	for i, v := range m.Values {
+		if err := u.(apl.ArraySetter).Set(i, v); err != nil {
+			panic(err)
+		}
+	}
+	return u, m
+}
+
+// BenchmarkKernels compares the kernels for uniform arrays (uniform)
+// with the generic path for the same values in mixed arrays (mixed).
+func BenchmarkKernels(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	shape := []int{10000}
+	for _, kind := range "bif" {
+		L, ML := kernelArray(r, byte(kind), shape)
+		R, MR := kernelArray(r, byte(kind), shape)
+		for _, f := range []string{"L+R", "L×R", "L⌈R", "L<R", "L∧R", "-R", "⌊R", "~R", "+/R", "⌈/R", "∧/R", "⍸R", "L/R"} {
+			for _, m := range []string{"uniform", "mixed"} {
+				b.Run(fmt.Sprintf("%c/%s/%s", kind, f, m), func(b *testing.B) {
+					a := apl.New(nil)
+					numbers.Register(a)
+					Register(a)
+					operators.Register(a)
+					if m == "uniform" {
+						a.Assign("L", L)
+						a.Assign("R", R)
+					} else {
+						a.Assign("L", ML)
+						a.Assign("R", MR)
+					}
+					p, err := a.Parse("Z←" + f)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if err := a.Eval(p); err != nil {
+						b.Skip(err) // e.g. lcm on floats
+					}
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						a.Eval(p)
+					}
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package primitives
+
+import (
+	"fmt"
+
+	"github.com/ktye/iv/apl"
+	. "github.com/ktye/iv/apl/domain"
+)
+
+func init() {
+	register(primitive{
+		symbol: "#",
+		doc:    "keys of a keyed table",
+		Domain: Monadic(IsKeyTable(nil)),
+		fn: func(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+			return R.(apl.KeyTable).K, nil
+		},
+	})
+	register(primitive{
+		symbol: "#",
+		doc:    "keyed table",
+		Domain: Dyadic(Split(IsTable(nil), IsTable(nil))),
+		fn:     keyTable,
+	})
+	register(primitive{
+		symbol: "⌷",
+		doc:    "index keyed table, []",
+		Domain: Dyadic(Split(indexSpec{}, IsKeyTable(nil))),
+		fn:     keyTableIndex,
+	})
+	register(primitive{
+		symbol: ",",
+		doc:    "upsert keyed table",
+		Domain: Dyadic(Split(IsKeyTable(nil), nil)),
+		fn:     upsert,
+	})
+}
+
+// keyTable creates a keyed table from the key table L and the value table R, see apl.KeyTable.
+func keyTable(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	return a.NewKeyTable(L.(apl.Table), R.(apl.Table))
+}
+
+// keyTableIndex looks up the rows of the value table by key.
+// The index is a table that contains the key columns, or a single key row given as
+// a scalar for a single key column or as a list for multiple key columns.
+// A vector indexes multiple rows of a single key column.
+// The result is a table, or a dict for a single key row.
+func keyTableIndex(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	kt := R.(apl.KeyTable)
+	spec := L.(apl.IdxSpec)
+	if len(spec) != 1 {
+		return nil, fmt.Errorf("keyed table index: index spec must be a single value")
+	}
+	p, single, err := keyRows(a, kt, spec[0])
+	if err != nil {
+		return nil, err
+	}
+	v := takeRows(a, kt.V, kt.Lookup(a, p))
+	if single == false {
+		return v, nil
+	}
+	d := apl.Dict{K: make([]apl.Value, len(v.K)), M: make(map[apl.Value]apl.Value)}
+	for i, k := range v.K {
+		d.K[i] = k
+		d.M[k] = v.M[k].(apl.Array).At(0)
+	}
+	return &d, nil
+}
+
+// keyRows converts the index of a keyed table to a table of key rows.
+// It returns true, if the index is a single key row.
+func keyRows(a *apl.Apl, kt apl.KeyTable, x apl.Value) (apl.Table, bool, error) {
+	keys := kt.K.K
+	d := apl.Dict{K: keys, M: make(map[apl.Value]apl.Value)}
+	if t, ok := x.(apl.Table); ok {
+		for _, k := range keys {
+			c, ok := t.M[k]
+			if ok == false {
+				return apl.Table{}, false, fmt.Errorf("keyed table index: key column is missing: %s", k.String(a))
+			}
+			d.M[k] = c
+		}
+		return apl.Table{Dict: &d, Rows: t.Rows}, false, nil
+	}
+
+	column := func(v ...apl.Value) apl.Value {
+		m := apl.MixedArray{Dims: []int{len(v)}, Values: v}
+		if u, ok := a.Unify(m, true); ok {
+			return u
+		}
+		return m
+	}
+	if l, ok := x.(apl.List); ok && len(keys) > 1 {
+		if len(l) != len(keys) {
+			return apl.Table{}, false, fmt.Errorf("keyed table index: list has %d values for %d key columns", len(l), len(keys))
+		}
+		for i, k := range keys {
+			d.M[k] = column(l[i])
+		}
+		return apl.Table{Dict: &d, Rows: 1}, true, nil
+	} else if len(keys) > 1 {
+		return apl.Table{}, false, fmt.Errorf("keyed table index: multiple key columns require a list or a table")
+	}
+
+	ar, ok := x.(apl.Array)
+	if ok == false {
+		d.M[keys[0]] = column(x)
+		return apl.Table{Dict: &d, Rows: 1}, true, nil
+	} else if s := ar.Shape(); len(s) != 1 {
+		return apl.Table{}, false, fmt.Errorf("keyed table index: keys must be a vector")
+	}
+	d.M[keys[0]] = ar
+	return apl.Table{Dict: &d, Rows: ar.Size()}, false, nil
+}
+
+// takeRows returns the rows of t given by idx.
+// An index of -1 returns a row of null values.
+func takeRows(a *apl.Apl, t apl.Table, idx []int) apl.Table {
+	d := apl.Dict{K: make([]apl.Value, len(t.K)), M: make(map[apl.Value]apl.Value)}
+	for i, k := range t.K {
+		d.K[i] = k
+		if len(idx) == 0 {
+			d.M[k] = apl.EmptyArray{}
+			continue
+		}
+		c := t.M[k].(apl.Array)
+		m := apl.MixedArray{Dims: []int{len(idx)}, Values: make([]apl.Value, len(idx))}
+		for n, j := range idx {
+			if j < 0 {
+				m.Values[n] = joinNull(a, c, nil)
+			} else {
+				m.Values[n] = c.At(j)
+			}
+		}
+		if u, ok := a.Unify(m, true); ok {
+			d.M[k] = u
+		} else {
+			d.M[k] = m
+		}
+	}
+	return apl.Table{Dict: &d, Rows: len(idx)}
+}
+
+// upsert updates the keyed table L with the rows of R, which is a table, a keyed table or a dict.
+// R must contain all key columns and may contain only some of the value columns,
+// see apl.KeyTable.Upsert.
+func upsert(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	kt := L.(apl.KeyTable)
+	var r apl.Table
+	switch v := R.(type) {
+	case apl.KeyTable:
+		r = v.Table()
+	case apl.Table:
+		r = v
+	case apl.Object:
+		t, err := rows2table(a, v)
+		if err != nil {
+			return nil, err
+		}
+		r = t
+	default:
+		return nil, fmt.Errorf("upsert: right argument must be a table, a keyed table or a dict: %T", R)
+	}
+
+	t := kt.Table()
+	for _, k := range r.K {
+		if _, ok := t.M[k]; ok == false {
+			return nil, apl.Errorf(apl.IndexError, "upsert: column does not exist: %s", k.String(a))
+		}
+	}
+	for _, k := range kt.K.K {
+		if _, ok := r.M[k]; ok == false {
+			return nil, fmt.Errorf("upsert: key column is missing: %s", k.String(a))
+		}
+	}
+
+	return kt.Upsert(a, r)
+}
@@ -49,6 +49,8 @@ func depth(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 func tally(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 	if t, ok := R.(apl.Table); ok {
 		return apl.Int(t.Rows), nil
+	} else if t, ok := R.(apl.KeyTable); ok {
+		return apl.Int(t.K.Rows), nil
 	}
 	if o, ok := R.(apl.Object); ok {
 		return apl.Int(len(o.Keys())), nil
@@ -64,7 +66,42 @@ func tally(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 	return apl.Int(shape[0]), nil
 }
 
+// match compares L and R for identity.
+// Arrays match, if they have the same shape and their items match, which is tested
+// recursively for nested arrays and objects.
+// Objects match, if they have the same keys in the same order and their values match.
+// Tables must also have the same number of rows.
+// Keyed tables match, if their key and value tables match.
 func match(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if kl, ok := L.(apl.KeyTable); ok {
+		kr, ok := R.(apl.KeyTable)
+		if ok == false {
+			return apl.Bool(false), nil
+		}
+		if m, err := match(a, kl.K, kr.K); err != nil || m.(apl.Bool) == false {
+			return m, err
+		}
+		return match(a, kl.V, kr.V)
+	} else if _, ok := R.(apl.KeyTable); ok {
+		return apl.Bool(false), nil
+	}
+	if tl, ok := L.(apl.Table); ok {
+		tr, ok := R.(apl.Table)
+		if ok == false || tl.Rows != tr.Rows {
+			return apl.Bool(false), nil
+		}
+		return matchObject(a, tl.Dict, tr.Dict)
+	} else if _, ok := R.(apl.Table); ok {
+		return apl.Bool(false), nil
+	}
+	ol, isol := L.(apl.Object)
+	or, isor := R.(apl.Object)
+	if isol != isor {
+		return apl.Bool(false), nil
+	} else if isol {
+		return matchObject(a, ol, or)
+	}
+
 	al, isal := L.(apl.Array)
 	ar, isar := R.(apl.Array)
 	if isal != isar {
@@ -74,6 +111,9 @@ func match(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		// Compare scalars, convert numbers to the same type.
 		return apl.Bool(isEqual(a, L, R)), nil
 	} else {
+		if e, ok := emptyNumeric(a, al, ar); ok {
+			return apl.Bool(e), nil
+		}
 		sl := al.Shape()
 		sr := ar.Shape()
 		if len(sr) != len(sl) {
@@ -93,6 +133,14 @@ func match(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		}
 		feq := arith2("=", compare("="))
 		for i := 0; i < apl.ArraySize(ar); i++ {
+			if nested(ar.At(i)) || nested(al.At(i)) {
+				if iseq, err := match(a, al.At(i), ar.At(i)); err != nil {
+					return nil, err
+				} else if iseq.(apl.Bool) == false {
+					return apl.Bool(false), nil
+				}
+				continue
+			}
 			if iseq, err := feq(a, ar.At(i), al.At(i)); err != nil {
 				return nil, err
 			} else if iseq.(apl.Bool) == false {
@@ -103,6 +151,54 @@ func match(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	}
 }
 
+// nested returns true, if an item of an array is an array or an object.
+func nested(v apl.Value) bool {
+	switch v.(type) {
+	case apl.Array, apl.Object:
+		return true
+	}
+	return false
+}
+
+// matchObject compares the keys and the values of two objects.
+func matchObject(a *apl.Apl, L, R apl.Object) (apl.Value, error) {
+	kl, kr := L.Keys(), R.Keys()
+	if len(kl) != len(kr) {
+		return apl.Bool(false), nil
+	}
+	for i := range kl {
+		if isEqual(a, kl[i], kr[i]) == false {
+			return apl.Bool(false), nil
+		}
+		if eq, err := match(a, L.At(a, kl[i]), R.At(a, kr[i])); err != nil {
+			return nil, err
+		} else if eq.(apl.Bool) == false {
+			return apl.Bool(false), nil
+		}
+	}
+	return apl.Bool(true), nil
+}
+
+// emptyNumeric compares an EmptyArray with an empty vector.
+// The EmptyArray is the numeric empty vector ⍬, which matches
+// empty vectors with a numeric fill element.
+func emptyNumeric(a *apl.Apl, L, R apl.Array) (bool, bool) {
+	_, el := L.(apl.EmptyArray)
+	_, er := R.(apl.EmptyArray)
+	if el == er {
+		return false, false
+	}
+	v := R
+	if er {
+		v = L
+	}
+	if s := v.Shape(); len(s) != 1 || s[0] != 0 {
+		return false, true
+	}
+	_, isnum := a.Fill(v).(apl.Number)
+	return isnum, true
+}
+
 func notmatch(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	if eq, err := match(a, L, R); err != nil {
 		return nil, err
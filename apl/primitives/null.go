@@ -0,0 +1,148 @@
+package primitives
+
+import (
+	"fmt"
+
+	"github.com/ktye/iv/apl"
+	. "github.com/ktye/iv/apl/domain"
+)
+
+func init() {
+	register(primitive{
+		symbol: "⍰",
+		doc:    "null, test for missing values",
+		Domain: Monadic(nil),
+		fn:     isnull,
+	})
+	register(primitive{
+		symbol: "⍰",
+		doc:    "fill, replace missing values",
+		Domain: Dyadic(nil),
+		fn:     fillnull,
+	})
+}
+
+// isnull returns 1 for each missing value in R, see apl.Nullable.
+// The result has the same shape as R.
+// For a dict or a table, each value or column is tested.
+func isnull(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	switch r := R.(type) {
+	case apl.Table:
+		d, err := eachValue(a, nil, r.Dict, isnull)
+		if err != nil {
+			return nil, err
+		}
+		return apl.Table{Dict: d, Rows: r.Rows}, nil
+	case *apl.Dict:
+		return eachValue(a, nil, r, isnull)
+	case apl.Array:
+		b := apl.NewBoolArray(apl.CopyShape(r), nil)
+		for i := 0; i < r.Size(); i++ {
+			b.SetBool(i, apl.IsNull(r.At(i)))
+		}
+		return b, nil
+	}
+	return apl.Bool(apl.IsNull(R)), nil
+}
+
+// fillnull replaces missing values in R with L.
+// L is a scalar or an array of the same shape as R.
+// For a dict or a table, L may also be a dict which contains the fill value
+// for each key. Values or columns with keys that are not in L are not changed.
+func fillnull(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	switch r := R.(type) {
+	case apl.Table:
+		d, err := eachValue(a, L, r.Dict, fillnull)
+		if err != nil {
+			return nil, err
+		}
+		return apl.Table{Dict: d, Rows: r.Rows}, nil
+	case *apl.Dict:
+		return eachValue(a, L, r, fillnull)
+	case apl.Array:
+		return fillArray(a, L, r)
+	}
+	if _, ok := L.(apl.Array); ok {
+		return nil, fmt.Errorf("fill: left argument must be a scalar")
+	}
+	if apl.IsNull(R) {
+		return L, nil
+	}
+	return R, nil
+}
+
+func fillArray(a *apl.Apl, L apl.Value, R apl.Array) (apl.Value, error) {
+	al, isarray := L.(apl.Array)
+	if isarray {
+		if isEqualShape(al, R) == false {
+			return nil, apl.Errorf(apl.LengthError, "fill: shapes do not conform")
+		}
+	}
+	n := R.Size()
+	values := make([]apl.Value, n)
+	filled := false
+	for i := range values {
+		v := R.At(i)
+		if apl.IsNull(v) {
+			filled = true
+			if isarray {
+				v = al.At(i)
+			} else {
+				v = L
+			}
+		}
+		values[i] = v
+	}
+	if filled == false {
+		return R, nil
+	}
+	if _, ok := R.(apl.List); ok {
+		return apl.List(values), nil
+	}
+	m := apl.MixedArray{Dims: apl.CopyShape(R), Values: values}
+	if u, ok := a.Unify(m, true); ok {
+		return u, nil
+	}
+	return m, nil
+}
+
+// isEqualShape returns true if both arrays have the same shape.
+func isEqualShape(L, R apl.Array) bool {
+	sl, sr := L.Shape(), R.Shape()
+	if len(sl) != len(sr) {
+		return false
+	}
+	for i := range sl {
+		if sl[i] != sr[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// eachValue applies f to each value of the dict D.
+// If L is a dict, it is applied with the value of L at the same key,
+// and values of keys that are not in L are kept.
+func eachValue(a *apl.Apl, L apl.Value, D *apl.Dict, f func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error)) (*apl.Dict, error) {
+	ld, isdict := L.(*apl.Dict)
+	d := apl.Dict{K: make([]apl.Value, len(D.K)), M: make(map[apl.Value]apl.Value)}
+	copy(d.K, D.K)
+	for _, k := range D.K {
+		v := D.M[k]
+		l := L
+		if isdict {
+			lv, ok := ld.M[k]
+			if ok == false {
+				d.M[k] = v
+				continue
+			}
+			l = lv
+		}
+		r, err := f(a, l, v)
+		if err != nil {
+			return nil, err
+		}
+		d.M[k] = r
+	}
+	return &d, nil
+}
@@ -0,0 +1,269 @@
+package primitives
+
+import (
+	"fmt"
+
+	"github.com/ktye/iv/apl"
+	. "github.com/ktye/iv/apl/domain"
+)
+
+func init() {
+	register(primitive{
+		symbol: "⊆",
+		doc:    "partition",
+		Domain: Dyadic(Split(ToIndexArray(nil), nil)),
+		fn:     partition(false),
+	})
+	register(primitive{
+		symbol: "⊆",
+		doc:    "partition channel",
+		Domain: Dyadic(Split(IsChannel(nil), IsChannel(nil))),
+		fn:     partitionChannel(false),
+	})
+	register(primitive{
+		symbol: "⊂",
+		doc:    "partitioned enclose",
+		Domain: Dyadic(Split(ToIndexArray(nil), nil)),
+		fn:     partition(true),
+	})
+	register(primitive{
+		symbol: "⊂",
+		doc:    "partitioned enclose channel",
+		Domain: Dyadic(Split(IsChannel(nil), IsChannel(nil))),
+		fn:     partitionChannel(true),
+	})
+}
+
+// partitioner decides where partitions start.
+// Partition L⊆R starts a new partition, where L is larger than it's predecessor,
+// items for which L is 0 are dropped.
+// Partitioned enclose L⊂R starts L[i] new partitions before item i,
+// items before the first partition are dropped.
+type partitioner struct {
+	enclose bool
+	started bool
+	last    int
+}
+
+// step returns the number of partitions that start at the current item
+// and if the item is dropped.
+func (p *partitioner) step(n int) (int, bool) {
+	if p.enclose {
+		if n > 0 {
+			p.started = true
+		}
+		return n, p.started == false
+	}
+	starts := 0
+	if n > p.last {
+		starts = 1
+	}
+	p.last = n
+	return starts, n == 0
+}
+
+// partition returns the partition or the partitioned enclose function.
+// The right argument is split along the last axis or the given axis.
+// L must be a non-negative index vector with one value for each item along the axis.
+// A scalar is extended.
+// The result is a List of the partitions, which have the same type and rank as R.
+//	1 1 2 2 0 3⊆⍳6
+//	1 0 1 0 0 1⊂⍳6
+//	1 0 1⊂[1]3 2⍴⍳6
+func partition(enclose bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
+	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		name := "partition"
+		if enclose {
+			name = "partitioned enclose"
+		}
+
+		R, x, err := splitAxis(a, R)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		ar, ok := R.(apl.Array)
+		if ok == false {
+			return nil, fmt.Errorf("%s: right argument must be an array: %T", name, R)
+		}
+		shape := ar.Shape()
+		if len(shape) == 0 {
+			return nil, fmt.Errorf("%s: right argument must not be a scalar", name)
+		}
+		k := len(shape) - 1
+		if x != nil {
+			if len(x) != 1 {
+				return nil, fmt.Errorf("%s: axis must be a scalar", name)
+			}
+			k = x[0]
+			if k < 0 || k >= len(shape) {
+				return nil, apl.Errorf(apl.IndexError, "%s: axis out of range", name)
+			}
+		}
+
+		l := L.(apl.IntArray)
+		if len(l.Dims) != 1 {
+			return nil, fmt.Errorf("%s: left argument must be a vector", name)
+		}
+		n := shape[k]
+		mask := l.Ints
+		if len(mask) == 1 && n != 1 {
+			mask = make([]int, n)
+			for i := range mask {
+				mask[i] = l.Ints[0]
+			}
+		} else if len(mask) != n {
+			return nil, apl.Errorf(apl.LengthError, "%s: length error: L has %d items, R has %d along axis", name, len(mask), n)
+		}
+
+		// Collect the partitions as ranges [start, end) along the axis.
+		var ranges [][2]int
+		p := partitioner{enclose: enclose}
+		for i, m := range mask {
+			if m < 0 {
+				return nil, fmt.Errorf("%s: left argument must not be negative", name)
+			}
+			starts, drop := p.step(m)
+			for s := 0; s < starts; s++ {
+				ranges = append(ranges, [2]int{i, i})
+			}
+			if drop == false {
+				ranges[len(ranges)-1][1] = i + 1
+			}
+		}
+
+		res := make(apl.List, len(ranges))
+		for i, r := range ranges {
+			res[i] = subArray(ar, k, r[0], r[1])
+		}
+		return res, nil
+	}
+}
+
+// subArray returns the items from start to end along axis k of ar.
+// A List returns a List, other arrays return an array of their type.
+func subArray(ar apl.Array, k, start, end int) apl.Value {
+	if l, ok := ar.(apl.List); ok {
+		res := make(apl.List, end-start)
+		copy(res, l[start:end])
+		return res
+	}
+	shape := apl.CopyShape(ar)
+	outer, inner := 1, 1
+	for _, n := range shape[:k] {
+		outer *= n
+	}
+	for _, n := range shape[k+1:] {
+		inner *= n
+	}
+	n := shape[k]
+	shape[k] = end - start
+	res := apl.MakeArray(ar, shape)
+	dst := 0
+	for o := 0; o < outer; o++ {
+		src := (o*n + start) * inner
+		for i := 0; i < (end-start)*inner; i++ {
+			res.Set(dst, ar.At(src+i))
+			dst++
+		}
+	}
+	return res
+}
+
+// partitionChannel partitions values read from channel R.
+// For each value in R, a value is read from channel L, which is used as the mask.
+// Each partition is sent as a List over the returned channel, as soon as it is closed.
+// A partition is closed, when the next starts, or for partition if L is 0.
+// The last partition is sent, when R or L are closed.
+func partitionChannel(enclose bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
+	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		l := L.(apl.Channel)
+		r := R.(apl.Channel)
+		c := apl.NewChannel()
+		a = a.Fork()
+		go func() {
+			defer close(c[0])
+			var part apl.List
+			p := partitioner{enclose: enclose}
+
+			// send returns false, if the output channel is closed.
+			send := func(v apl.Value) bool {
+				select {
+				case _, ok := <-c[1]:
+					if ok == false {
+						close(r[1])
+						close(l[1])
+						return false
+					}
+				case c[0] <- v:
+				}
+				return true
+			}
+			for {
+				select {
+				case _, ok := <-c[1]:
+					if ok == false {
+						close(r[1])
+						close(l[1])
+						return
+					}
+				case v, ok := <-r[0]:
+					if ok == false {
+						close(l[1])
+						if part != nil {
+							send(part)
+						}
+						return
+					}
+					lv, ok := <-l[0]
+					if ok == false {
+						close(r[1])
+						if part != nil {
+							send(part)
+						}
+						return
+					}
+					m, err := channelMask(a, lv)
+					if err != nil {
+						if send(apl.Error{E: err}) {
+							close(r[1])
+							close(l[1])
+						}
+						return
+					}
+					starts, drop := p.step(m)
+					for i := 0; i < starts; i++ {
+						if part != nil && send(part) == false {
+							return
+						}
+						part = apl.List{}
+					}
+					if drop {
+						if enclose == false && part != nil {
+							if send(part) == false {
+								return
+							}
+							part = nil
+						}
+					} else {
+						part = append(part, v)
+					}
+				}
+			}
+		}()
+		return c, nil
+	}
+}
+
+// channelMask converts a value read from the mask channel to a non-negative int.
+func channelMask(a *apl.Apl, v apl.Value) (int, error) {
+	to := ToIndexArray(nil)
+	iv, ok := to.To(a, v)
+	if ok == false {
+		return 0, fmt.Errorf("partition channel: mask value is not an index: %T", v)
+	}
+	ints := iv.(apl.IntArray).Ints
+	if len(ints) != 1 || ints[0] < 0 {
+		return 0, fmt.Errorf("partition channel: mask value must be a non-negative scalar")
+	}
+	return ints[0], nil
+}
@@ -0,0 +1,58 @@
+package primitives
+
+import (
+	"fmt"
+
+	"github.com/ktye/iv/apl"
+	. "github.com/ktye/iv/apl/domain"
+)
+
+func init() {
+	register(primitive{
+		symbol: "↓",
+		doc:    "first",
+		Domain: Monadic(nil),
+		fn:     first,
+	})
+	register(primitive{
+		symbol: "⊃",
+		doc:    "pick",
+		Domain: Dyadic(Split(nil, Or(IsArray(nil), IsObject(nil)))),
+		fn:     pick,
+	})
+}
+
+// first returns the first item of R.
+// It is the first element of an array or list, the value of the first key of an object,
+// or the first row of a table as a Dict.
+// The first of an empty uniform array is it's zero value.
+func first(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	switch r := R.(type) {
+	case apl.Table:
+		if r.Rows == 0 {
+			return nil, fmt.Errorf("first: table is empty")
+		}
+		return a.Pick(apl.Int(a.Origin), r)
+	case apl.Object:
+		keys := r.Keys()
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("first: object is empty")
+		}
+		return r.At(a, keys[0]), nil
+	case apl.Array:
+		if r.Size() == 0 {
+			if u, ok := r.(apl.Uniform); ok {
+				return u.Zero(), nil
+			}
+			return apl.EmptyArray{}, nil
+		}
+		return r.At(0), nil
+	}
+	return R, nil
+}
+
+// pick returns the item of R at the path given by L.
+// See apl.Pick.
+func pick(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	return a.Pick(L, R)
+}
@@ -2,6 +2,7 @@ package primitives
 
 import (
 	"fmt"
+	"reflect"
 	"runtime"
 	"strings"
 
@@ -12,6 +13,9 @@ func Register(a *apl.Apl) {
 	for _, p := range primitives {
 		a.RegisterPrimitive(apl.Primitive(p.symbol), p)
 	}
+	for _, inv := range inverses {
+		a.RegisterInverse(apl.Primitive(inv.symbol), inv.op, apl.ToFunction(inv.fn))
+	}
 }
 
 var primitives []primitive
@@ -43,3 +47,7 @@ func (p primitive) Select(a *apl.Apl, L, R apl.Value) (apl.IntArray, error) {
 	return p.sel(a, L, R)
 }
 func (p primitive) Doc() string { return p.doc }
+func (p primitive) Rejects(L, R reflect.Type) bool {
+	t, ok := p.Domain.(apl.TypeDomain)
+	return ok && t.Rejects(L, R)
+}
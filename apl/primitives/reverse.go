@@ -72,7 +72,7 @@ func reverse(a *apl.Apl, R apl.Value, axis int) (apl.Value, error) {
 		axis = len(shape) + axis
 	}
 	if axis < 0 || axis >= len(shape) {
-		return nil, fmt.Errorf("reverse: axis out of range: %d  (rank %d)", axis, len(shape))
+		return nil, apl.Errorf(apl.IndexError, "reverse: axis out of range: %d  (rank %d)", axis, len(shape))
 	}
 
 	res := apl.MixedArray{
@@ -85,7 +85,7 @@ func reverse(a *apl.Apl, R apl.Value, axis int) (apl.Value, error) {
 	for i := range res.Values {
 		copy(src, dst) // sic: copy dst over src
 		src[axis] = shape[axis] - src[axis] - 1
-		res.Values[i] = ar.At(ic.Index(src)) // TODO copy ?
+		res.Values[i] = ar.At(ic.Index(src))
 		apl.IncArrayIndex(dst, shape)
 	}
 	return res, nil
@@ -148,7 +148,7 @@ func rotate(a *apl.Apl, L, R apl.Value, axis int) (apl.Value, error) {
 			Values: make([]apl.Value, size),
 		}
 		for i := range res.Values {
-			res.Values[i] = ar.At(rot(i, n, size)) // TODO: copy?
+			res.Values[i] = ar.At(rot(i, n, size))
 		}
 		return res, nil
 	}
@@ -157,7 +157,7 @@ func rotate(a *apl.Apl, L, R apl.Value, axis int) (apl.Value, error) {
 		axis = len(shape) + axis
 	}
 	if axis < 0 || axis >= len(shape) {
-		return nil, fmt.Errorf("rotate: illeal axis: %d (rank: %d)", axis, len(shape))
+		return nil, apl.Errorf(apl.IndexError, "rotate: illeal axis: %d (rank: %d)", axis, len(shape))
 	}
 
 	// Extend L to conform, if it is a single element array.
@@ -176,7 +176,7 @@ func rotate(a *apl.Apl, L, R apl.Value, axis int) (apl.Value, error) {
 	}
 
 	if len(lshape) != len(shape)-1 {
-		return nil, fmt.Errorf("rotate L: has wrong rank: %d (R: %d)", len(lshape), len(shape))
+		return nil, apl.Errorf(apl.RankError, "rotate L: has wrong rank: %d (R: %d)", len(lshape), len(shape))
 	}
 	for i := range lshape {
 		k := i
@@ -204,7 +204,7 @@ func rotate(a *apl.Apl, L, R apl.Value, axis int) (apl.Value, error) {
 		n := int(al.At(lic.Index(idx)).(apl.Int))
 		copy(src, dst)                        // sic: copy dst over src
 		src[axis] = rot(dst[axis], n, axsize) // replace the axis by it's rotation
-		res.Values[i] = ar.At(ric.Index(src)) // TODO copy ?
+		res.Values[i] = ar.At(ric.Index(src))
 		apl.IncArrayIndex(dst, shape)
 	}
 	return res, nil
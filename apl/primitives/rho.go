@@ -78,6 +78,14 @@ func rho2(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	shape := make([]int, len(l.Ints))
 	copy(shape, l.Ints)
 
+	// An empty result keeps a scalar fill element of R, e.g. 0⍴`a is an empty string array.
+	if prod(shape) == 0 {
+		fill := a.Fill(R)
+		if _, ok := fill.(apl.Array); ok == false {
+			return a.MakeFilled(fill, shape), nil
+		}
+	}
+
 	if rs, ok := R.(apl.Reshaper); ok {
 		return rs.Reshape(shape), nil
 	}
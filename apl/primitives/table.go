@@ -26,7 +26,7 @@ func table1(symbol string, fn func(*apl.Apl, apl.Value) (apl.Value, bool)) func(
 		d := apl.Dict{K: make([]apl.Value, len(keys)), M: make(map[apl.Value]apl.Value)}
 		var err error
 		for i, k := range keys {
-			d.K[i] = k // TODO: copy?
+			d.K[i] = k
 			v := src.At(a, k)
 			if v == nil {
 				return nil, fmt.Errorf("missing value for key %s", k.String(a))
@@ -165,7 +165,7 @@ func tableAny(symbol string, fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value,
 		var err error
 		var v, r, l apl.Value
 		for i, k := range keys {
-			l = o.At(a, k) // TODO: copy?
+			l = o.At(a, k)
 			r = R
 			if leftarray {
 				l, r = L, l
@@ -221,15 +221,25 @@ func catenateTables(a *apl.Apl, L, R apl.Value, first bool) (apl.Value, error) {
 		o = l.Dict
 		if r, ok := R.(apl.Table); ok {
 			return catenateTwoTables(a, l, r, first)
-		} else if _, ok = R.(apl.Object); ok {
-			return nil, fmt.Errorf("catenate: cannot mix object and table")
+		} else if r, ok := R.(apl.Object); ok {
+			// Append rows given as a dict.
+			if rt, err := rows2table(a, r); err != nil {
+				return nil, err
+			} else {
+				return catenateTwoTables(a, l, rt, true)
+			}
 		}
 	} else if l, ok := L.(apl.Object); ok {
 		o = l
-		if r, ok := R.(apl.Object); ok {
+		if r, ok := R.(apl.Table); ok {
+			// Prepend rows given as a dict.
+			if lt, err := rows2table(a, l); err != nil {
+				return nil, err
+			} else {
+				return catenateTwoTables(a, lt, r, true)
+			}
+		} else if r, ok := R.(apl.Object); ok {
 			return catenateTwoTables(a, l, r, first)
-		} else if _, ok = R.(apl.Table); ok {
-			return nil, fmt.Errorf("catenate: cannot mix object and table")
 		}
 	}
 
@@ -256,7 +266,7 @@ func catenateTables(a *apl.Apl, L, R apl.Value, first bool) (apl.Value, error) {
 	d := apl.Dict{K: make([]apl.Value, len(keys)), M: make(map[apl.Value]apl.Value)}
 	var err error
 	for i, k := range keys {
-		d.K[i] = k // TODO: copy?
+		d.K[i] = k
 		v := o.At(a, k)
 		if leftarray {
 			rv = v
@@ -297,7 +307,7 @@ func catenateTwoTables(a *apl.Apl, L, R apl.Value, first bool) (apl.Value, error
 	d := apl.Dict{K: make([]apl.Value, len(keys)), M: make(map[apl.Value]apl.Value)}
 	if first {
 		for i, k := range keys {
-			d.K[i] = k // TODO: Copy?
+			d.K[i] = k
 			d.M[k] = l.At(a, k)
 		}
 		for _, k := range r.Keys() {
@@ -312,7 +322,7 @@ func catenateTwoTables(a *apl.Apl, L, R apl.Value, first bool) (apl.Value, error
 			return nil, fmt.Errorf("catenate table on first axis: tables have different number of columns")
 		}
 		for i, k := range keys {
-			d.K[i] = k // TODO: Copy?
+			d.K[i] = k
 			lv := l.At(a, k)
 			rv := r.At(a, k)
 			if rv == nil {
@@ -345,6 +355,32 @@ func catenateTwoTables(a *apl.Apl, L, R apl.Value, first bool) (apl.Value, error
 	return &d, nil
 }
 
+// rows2table converts an object to a table, that can be catenated to another table.
+// Each value is a scalar for a single row, or a vector with one value per row.
+func rows2table(a *apl.Apl, o apl.Object) (apl.Table, error) {
+	keys := o.Keys()
+	d := apl.Dict{K: make([]apl.Value, len(keys)), M: make(map[apl.Value]apl.Value)}
+	rows := -1
+	for i, k := range keys {
+		d.K[i] = k
+		v := o.At(a, k)
+		if ar, ok := v.(apl.Array); ok {
+			if s := ar.Shape(); len(s) != 1 {
+				return apl.Table{}, fmt.Errorf("catenate: row values must be scalars or vectors")
+			}
+		} else {
+			v = a.MakeFilled(v, []int{1})
+		}
+		if n := v.(apl.Array).Size(); rows == -1 {
+			rows = n
+		} else if n != rows {
+			return apl.Table{}, apl.Errorf(apl.LengthError, "catenate: row values have different length")
+		}
+		d.M[k] = v
+	}
+	return dict2table(a, &d)
+}
+
 func dict2table(a *apl.Apl, d *apl.Dict) (apl.Table, error) {
 	rows := 0
 	if len(d.K) > 0 {
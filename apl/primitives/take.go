@@ -107,7 +107,7 @@ func takedrop(a *apl.Apl, L, R apl.Value, take bool) (apl.Value, error) {
 	// If R is a scalar, set it's shape to (⍴,L)⍴1.
 	ar, ok := R.(apl.Array)
 	if ok == false {
-		r := apl.MixedArray{Values: []apl.Value{R}} // TODO copy?
+		r := apl.MixedArray{Values: []apl.Value{R}}
 		r.Dims = make([]int, len(ai.Ints))
 		for i := range r.Dims {
 			r.Dims[i] = 1
@@ -129,7 +129,7 @@ func takedrop(a *apl.Apl, L, R apl.Value, take bool) (apl.Value, error) {
 	axis := make([]int, len(rs))
 	for i, n := range x {
 		if k, ok := m[n]; ok == false {
-			return nil, fmt.Errorf("axis does not conform")
+			return nil, apl.Errorf(apl.LengthError, "axis does not conform")
 		} else {
 			axis[i] = k
 			delete(m, k)
@@ -147,7 +147,7 @@ func takedrop(a *apl.Apl, L, R apl.Value, take bool) (apl.Value, error) {
 
 	// Missing items in L default to values of ⍴R[x] for take and 0 for drop.
 	if len(ai.Ints) > len(rs) {
-		return nil, fmt.Errorf("take/drop: length of L is too large")
+		return nil, apl.Errorf(apl.LengthError, "take/drop: length of L is too large")
 	} else if len(ai.Ints) < len(rs) {
 		n := make([]int, len(rs))
 		copy(n, ai.Ints)
@@ -250,11 +250,11 @@ func cut(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 			return nil, fmt.Errorf("cut: indexes may not decrease")
 		}
 		if idx[i] < 0 || idx[i] >= len(r) {
-			return nil, fmt.Errorf("cut: indexes out of range")
+			return nil, apl.Errorf(apl.IndexError, "cut: indexes out of range")
 		}
 	}
 	if len(idx) == 1 {
-		return r[idx[0]:], nil // TODO: copy
+		return r[idx[0]:], nil
 	}
 	res := make(apl.List, len(idx))
 	for i := range res {
@@ -262,7 +262,7 @@ func cut(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		if i < len(idx)-1 {
 			stp = idx[i+1]
 		}
-		res[i] = r[idx[i]:stp] // TODO: copy
+		res[i] = r[idx[i]:stp]
 	}
 	return res, nil
 }
@@ -306,7 +306,7 @@ func takeChannel2(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 // sendChannel sends the value R to the channel L and returns R.
 func sendChannel(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	c := L.(apl.Channel)
-	c[1] <- R // TODO: copy?
+	c[1] <- apl.Copy(R)
 	return R, nil
 }
 
@@ -91,7 +91,7 @@ func transposeIndexes(a *apl.Apl, L, R apl.Value) ([]int, []int, error) {
 		return nil, nil, fmt.Errorf("transpose: L must be a vector or a scalar")
 	}
 	if ls[0] != len(rs) {
-		return nil, nil, fmt.Errorf("transpose: length of L must be the rank of R")
+		return nil, nil, apl.Errorf(apl.LengthError, "transpose: length of L must be the rank of R")
 	}
 
 	// Add 1 to L, if Origin is 0.
@@ -107,7 +107,7 @@ func transposeIndexes(a *apl.Apl, L, R apl.Value) ([]int, []int, error) {
 	m := make(map[int]bool)
 	for _, v := range al.Ints {
 		if v < 1 {
-			return nil, nil, fmt.Errorf("transpose: value in L out of range: %d", v)
+			return nil, nil, apl.Errorf(apl.IndexError, "transpose: value in L out of range: %d", v)
 		}
 		if v > max {
 			max = v
@@ -164,7 +164,7 @@ func transposeObject(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 	for i, k := range keys {
 		col := o.At(a, k)
 		if col == nil {
-			return nil, fmt.Errorf("table: column %s does not exist", k.String(a))
+			return nil, apl.Errorf(apl.IndexError, "table: column %s does not exist", k.String(a))
 		}
 		size := 1
 		if ar, ok := col.(apl.Array); ok {
@@ -185,14 +185,14 @@ func transposeObject(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 		} else if size != n {
 			return nil, fmt.Errorf("table: columns have different sizes")
 		}
-		tab.K = append(tab.K, k) // TODO: copy k
+		tab.K = append(tab.K, k)
 		if tab.Dict.M == nil {
 			tab.Dict.M = make(map[apl.Value]apl.Value)
 		}
 		if _, ok := col.(apl.Array); ok == false {
 			col = apl.List{col} // enlist scalars
 		}
-		tab.M[k] = col // TODO: copy
+		tab.M[k] = col
 	}
 	tab.Rows = n
 	return tab, nil
@@ -200,5 +200,5 @@ func transposeObject(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 
 // transposeTable returns a Dict by transposing a table.
 func transposeTable(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
-	return R.(apl.Table).Dict, nil // TODO: copy
+	return R.(apl.Table).Dict, nil
 }
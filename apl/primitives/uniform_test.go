@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/big"
 	"github.com/ktye/iv/apl/numbers"
 )
 
@@ -22,7 +23,7 @@ func TestUnify(t *testing.T) {
 	}{
 		// Known uniform types.
 		{
-			apl.BoolArray{Dims: []int{2}, Bools: []bool{false, true}},
+			apl.NewBoolArray([]int{2}, []bool{false, true}),
 			reflect.TypeOf(apl.BoolArray{}), true,
 			reflect.TypeOf(apl.BoolArray{}), true,
 		},
@@ -140,3 +141,15 @@ func TestUnify(t *testing.T) {
 		}
 	}
 }
+
+// TestPrototype checks that the prototype of a number belongs to the tower,
+// also if the tower has no uniform arrays.
+func TestPrototype(t *testing.T) {
+	a := apl.New(nil)
+	big.SetBigTower(a)
+	zero := a.Tower.Import(apl.Int(0))
+	p := a.Prototype(a.Tower.Import(apl.Int(3)))
+	if reflect.TypeOf(p) != reflect.TypeOf(zero) {
+		t.Fatalf("expected %T got %T", zero, p)
+	}
+}
@@ -18,6 +18,12 @@ func init() {
 		Domain: Dyadic(Split(ToVector(nil), ToVector(nil))),
 		fn:     union,
 	})
+	register(primitive{
+		symbol: "∪",
+		doc:    "union join tables",
+		Domain: tablesWithAxis{}, // Registered after union, which accepts any values.
+		fn:     unionJoin,
+	})
 }
 
 // unique: R is a vector.
@@ -38,7 +44,7 @@ func unique(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 			}
 		}
 		if u {
-			values = append(values, v) // TODO copy?
+			values = append(values, v)
 		}
 	}
 	return apl.MixedArray{Values: values, Dims: []int{len(values)}}, nil
@@ -67,7 +73,7 @@ func union(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 				}
 			}
 			if u {
-				values = append(values, v) // TODO copy?
+				values = append(values, v)
 			}
 		}
 		return nil
@@ -5,6 +5,7 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"unicode/utf8"
 )
 
@@ -12,38 +13,98 @@ import (
 // If the symbol exists already, it is overloaded.
 // When the function is applied, the last registered handle is tested
 // first, if the arguments match to the domain of the handler.
+// Registering invalidates the dispatch caches of compiled programs.
 func (a *Apl) RegisterPrimitive(p Primitive, h PrimitiveHandler) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.primitives[p] = append([]PrimitiveHandler{h}, a.primitives[p]...)
+	atomic.AddInt64(&a.generation, 1)
 	a.registerSymbol(string(p))
 }
 
 // RegisterOperator registers s as the symbol for the operator.
 func (a *Apl) RegisterOperator(s string, op Operator) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.registerOperator(s, op)
+}
+
+// registerOperator is called with the registry locked.
+func (r *registry) registerOperator(s string, op Operator) error {
 	if op == nil {
 		return fmt.Errorf("cannot register a nil operator to %s", s)
 	}
-	if ops, ok := a.operators[s]; ok && ops[0].DyadicOp() != op.DyadicOp() {
+	if ops, ok := r.operators[s]; ok && ops[0].DyadicOp() != op.DyadicOp() {
 		return fmt.Errorf("cannot register operator %s with differing arity", s)
 	}
-	a.operators[s] = append([]Operator{op}, a.operators[s]...)
-	a.registerSymbol(s)
+	r.operators[s] = append([]Operator{op}, r.operators[s]...)
+	r.registerSymbol(s)
 	return nil
 }
 
 // registerSymbol adds single rune symbols for the parser.
-func (a *Apl) registerSymbol(s string) {
-	if r, w := utf8.DecodeRuneInString(s); w == len(s) {
-		a.symbols[r] = s
+func (r *registry) registerSymbol(s string) {
+	if c, w := utf8.DecodeRuneInString(s); w == len(s) {
+		r.symbols[c] = s
 	}
 }
 
 // RegisterPackage adds an external package to apl.
 func (a *Apl) RegisterPackage(name string, m map[string]Value) {
-	a.pkg[name] = &env{parent: nil, vars: m}
+	a.setPkg(name, &env{parent: nil, vars: m})
+}
+
+// The registry is read by concurrent evaluations.
+// Registered slices are not modified, a new registration replaces them.
+
+func (r *registry) setPkg(name string, e *env) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pkg[name] = e
+}
+
+// lookupPkg returns the environment of a package or nil.
+func (r *registry) lookupPkg(name string) *env {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.pkg[name]
+}
+
+// handlers returns the handlers of a primitive function or nil.
+func (r *registry) handlers(p Primitive) []PrimitiveHandler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.primitives[p]
+}
+
+// operator returns the handlers of an operator.
+func (r *registry) operator(s string) ([]Operator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ops, ok := r.operators[s]
+	return ops, ok
+}
+
+// inverse returns the inverse registered for the key or nil.
+func (r *registry) inverse(k inverseKey) Function {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.inverses[k]
 }
 
 // Doc writes the documentation of all registered primitives and operators to the writer.
 func (a *Apl) Doc(w io.Writer) {
+	a.mu.RLock()
+	primitives := make(map[Primitive][]PrimitiveHandler, len(a.primitives))
+	for p, h := range a.primitives {
+		primitives[p] = h
+	}
+	operators := make(map[string][]Operator, len(a.operators))
+	for s, ops := range a.operators {
+		operators[s] = ops
+	}
+	a.mu.RUnlock()
+
 	fmt.Fprintln(w, "## Primitive functions")
 	fmt.Fprintln(w, "```")
 	{
@@ -51,9 +112,9 @@ func (a *Apl) Doc(w io.Writer) {
 		s := make([]struct {
 			symbol Primitive
 			doc    string
-		}, len(a.primitives))
+		}, len(primitives))
 		i := 0
-		for symbol, handlers := range a.primitives {
+		for symbol, handlers := range primitives {
 			h := handlers[0]
 			s[i].symbol = symbol
 			s[i].doc = h.Doc()
@@ -62,7 +123,7 @@ func (a *Apl) Doc(w io.Writer) {
 		sort.Slice(s, func(i, j int) bool { return s[i].doc < s[j].doc })
 		for _, k := range s {
 			symbol := k.symbol
-			handlers := a.primitives[k.symbol]
+			handlers := primitives[k.symbol]
 			fmt.Fprintf(w, "%s\t\t\n", symbol)
 			for _, h := range handlers {
 				dom := h.String(a)
@@ -84,9 +145,9 @@ func (a *Apl) Doc(w io.Writer) {
 		s := make([]struct {
 			symbol string
 			doc    string
-		}, len(a.operators))
+		}, len(operators))
 		i := 0
-		for symbol, ops := range a.operators {
+		for symbol, ops := range operators {
 			h := ops[0]
 			s[i].symbol = symbol
 			s[i].doc = h.Doc()
@@ -95,7 +156,7 @@ func (a *Apl) Doc(w io.Writer) {
 		sort.Slice(s, func(i, j int) bool { return s[i].doc < s[j].doc })
 		for _, k := range s {
 			symbol := k.symbol
-			handlers := a.operators[k.symbol]
+			handlers := operators[k.symbol]
 			fmt.Fprintf(w, "%s\t\t\n", symbol)
 			for _, h := range handlers {
 				dom := h.String(a)
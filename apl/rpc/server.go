@@ -10,7 +10,8 @@ import (
 )
 
 // ListenAndServe puts APL into server mode.
-// It accepts a single connection at a time from anyone.
+// It accepts connections from anyone.
+// Each connection is handled concurrently on it's own fork of the interpreter.
 func ListenAndServe(a *apl.Apl, addr string) {
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
@@ -24,7 +25,7 @@ func ListenAndServe(a *apl.Apl, addr string) {
 		if err != nil {
 			log.Print(err)
 		} else {
-			handle(a, conn)
+			go handle(a.Fork(), conn)
 		}
 	}
 }
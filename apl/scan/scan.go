@@ -262,6 +262,8 @@ func (s *Scanner) nextToken() (Token, error) {
 			return Token{T: Semicolon, S: ";"}, nil
 		case '∇':
 			return Token{T: Self, S: "∇"}, nil
+		case '⍬':
+			return Token{T: Identifier, S: "⍬"}, nil
 		case '⋄':
 			return Token{T: Diamond, S: "⋄"}, nil
 		case ' ', '\r', '\t':
@@ -318,18 +320,39 @@ func ScanNumber(s io.RuneScanner) (string, error) {
 // ScanString returns the next token as charstr or chars depending on the quoteChar.
 // " scans the string as charstr and ' as chars.
 // There is currently no way to escape newlines etc.
+// Within braces, a backtick string also ends at a colon, which separates a guard.
 func (s *Scanner) scanString(quoteChar rune) (Token, error) {
 	s.unreadRune()
 	str, err := ReadString(s)
 	if err != nil {
 		return Token{}, err
 	}
+	if quoteChar == '`' && s.inBraces() {
+		if i := strings.IndexRune(str, ':'); i >= 0 {
+			s.pos -= len(str) - i
+			s.width = 0
+			str = str[:i]
+		}
+	}
 	if quoteChar == '\'' {
 		return Token{T: Chars, S: str}, nil
 	}
 	return Token{T: String, S: str}, nil
 }
 
+// inBraces returns true, if the scanned tokens have more left than right braces.
+func (s *Scanner) inBraces() bool {
+	n := 0
+	for _, t := range s.tokens {
+		if t.T == LeftBrace {
+			n++
+		} else if t.T == RightBrace {
+			n--
+		}
+	}
+	return n > 0
+}
+
 // An identifier may start with _ or a unicode letter.
 // Later characters may also be digits.
 // A → may be present within an identifier.
@@ -341,6 +364,10 @@ func (s *Scanner) scanIdentifier() (Token, error) {
 		r, _ := s.nextRune()
 		if AllowedInVarname(r, first) {
 			buf.WriteRune(r)
+		} else if id := buf.String(); (id == "⍺" || id == "⍵") && string(r) == id {
+			// Operands of user defined operators: ⍺⍺ ⍵⍵.
+			buf.WriteRune(r)
+			return Token{T: Identifier, S: buf.String()}, nil
 		} else if r == '→' && arrow == false {
 			buf.WriteRune(r)
 			arrow = true
@@ -369,6 +396,18 @@ func AllowedInVarname(r rune, first bool) bool {
 	return r == '_' || unicode.IsLetter(r)
 }
 
+// IsOperatorName returns if the identifier names a user defined operator.
+// Operator names start with an underscore.
+// Dyadic operators also end with an underscore:
+//	_name	monadic operator
+//	_name_	dyadic operator
+func IsOperatorName(s string) (ok, dyadic bool) {
+	if len(s) < 2 || s[0] != '_' || s == "__" || strings.ContainsRune(s, '→') {
+		return false, false
+	}
+	return true, s[len(s)-1] == '_'
+}
+
 // applyCmds applyes rewrite rules recursively.
 // In /e/h* first /h is applyd to * then /e on the result.
 func (s *Scanner) applyCmds(t []Token) []Token {
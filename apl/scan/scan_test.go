@@ -66,6 +66,12 @@ func TestScan(t *testing.T) {
 			Token{T: RightBrack, S: "]"},
 			Token{T: RightBrace, S: "}"},
 		}},
+		{`⍺⍺ _op_ f⍺`, []Token{
+			Token{T: Identifier, S: "⍺⍺"},
+			Token{T: Identifier, S: "_op_"},
+			Token{T: Identifier, S: "f"},
+			Token{T: Identifier, S: "⍺"},
+		}},
 		{`{⍵∇1}`, []Token{
 			Token{T: LeftBrace, S: "{"},
 			Token{T: Symbol, S: "⍵"},
@@ -23,6 +23,10 @@ func (s String) String(a *Apl) string {
 	return fmt.Sprintf("%q", string(s))
 }
 
+// The empty string is the null value of a String.
+func (s String) IsNull() bool { return s == "" }
+func (s String) Null() Value  { return String("") }
+
 func (s String) Eval(a *Apl) (Value, error) {
 	return s, nil
 }
@@ -76,7 +80,7 @@ func (s StringArray) Zero() Value {
 
 func (s StringArray) Set(i int, v Value) error {
 	if i < 0 || i > len(s.Strings) {
-		return fmt.Errorf("index out of range")
+		return Errorf(IndexError, "index out of range")
 	}
 	if c, ok := v.(String); ok {
 		s.Strings[i] = string(c)
@@ -92,6 +96,27 @@ func (s StringArray) Make(shape []int) Array {
 	}
 }
 
+func (s StringArray) Copy() Value {
+	r := StringArray{Dims: CopyShape(s), Strings: make([]string, len(s.Strings))}
+	copy(r.Strings, s.Strings)
+	return r
+}
+
+func (s StringArray) Append(v []Value) (Array, bool) {
+	if len(s.Dims) != 1 {
+		return s, false
+	}
+	str := s.Strings
+	for _, e := range v {
+		c, ok := e.(String)
+		if ok == false {
+			return s, false
+		}
+		str = append(str, string(c))
+	}
+	return StringArray{Dims: []int{len(str)}, Strings: str}, true
+}
+
 func makeStringArray(v []Value) StringArray {
 	str := make([]string, len(v))
 	for i, e := range v {
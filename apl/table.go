@@ -13,6 +13,7 @@ import (
 // A Table is a transposed dictionary where each value is a vector
 // with the same number of elements and unique type.
 // Tables are constructed by transposing dictionaries T←⍉D
+// or read from csv data: T←"csv"⍎S, see ParseCsv.
 //
 // Indexing tables selects rows:
 //	T[⍳5]
@@ -23,9 +24,52 @@ import (
 //	T[⍋T→Time]
 // Selecting rows
 //	T[⍸T→Qty>5]
+// Right arrow indexing works for uppercase column names only,
+// lowercase names refer to functions.
+//
+// Indexed assignment updates rows, columns or single values:
+//	T[3]←`Qty`Px#(5;1.5;)
+//	T[;`Px]←Prices
+//	T[⍸T→Qty>5;`Px]×←1.1
+// Assignment to a new column creates it.
+// Rows are appended by catenation with a dict or a table, and deleted by assigning ⍬:
+//	T,←`Qty`Px#(5;1.5;)
+//	T[1 2]←⍬
+//
+// Tables are joined by key columns, given as an axis:
+//	L,[`Sym]R  L∩[`Sym]R  L∪[`Sym]R  Q⍸[`Sym`Time]T
+// for a left, inner, union and as-of join.
+// Rows are grouped by key columns and the other columns are aggregated with the key operator:
+//	`Sym`Date (`Qty`Px#(+/;⌈/;))⌸T
+// A table of keys and a table of values form a keyed table K#V, see KeyTable.
+//
+// Tables are graded by all columns ⍋T, or by some columns with L⍋T, where L is a
+// column name, a vector of names or a dict with the directions 1 or ¯1 for each column.
+// With an axis, the table is sorted and remembers the sort columns:
+//	T←⍋[`Sym`Time]T
+// The interval index T⍸[`Time]X and the as-of join use a binary search
+// on the sorted columns without testing the order first.
 type Table struct {
 	*Dict
 	Rows int
+
+	// Sorted contains the columns by which the rows are sorted in ascending order.
+	// It is nil for an unsorted table or if the order is unknown.
+	Sorted []Value
+}
+
+// SortedBy returns true, if the table is sorted by the given columns,
+// which may be followed by other sort columns.
+func (t Table) SortedBy(keys ...Value) bool {
+	if len(keys) > len(t.Sorted) {
+		return false
+	}
+	for i, k := range keys {
+		if t.Sorted[i] != k {
+			return false
+		}
+	}
+	return true
 }
 
 // String formats a table using a tabwriter.
@@ -41,10 +85,22 @@ func (t Table) String(a *Apl) string {
 	return string(b.Bytes())
 }
 
+// Copy returns a deep copy of the table.
+// It overwrites the method of the embedded Dict, which would return a Dict.
+func (t Table) Copy() Value {
+	var sorted []Value
+	if t.Sorted != nil {
+		sorted = make([]Value, len(t.Sorted))
+		copy(sorted, t.Sorted)
+	}
+	return Table{Dict: t.Dict.Copy().(*Dict), Rows: t.Rows, Sorted: sorted}
+}
+
 // Csv writes a table in csv format.
 // If L is nil, it uses ⍕V on each value.
 // If L is a dict with conforming keys, it uses the values as left arguments to format (L[Key])⍕V
 // for columns of the corresponding keys.
+// Null values are written as empty cells.
 func (t Table) Csv(a *Apl, L Object, w io.Writer) error {
 	f := t.newFormatter(a, L)
 	defer f.Close()
@@ -95,11 +151,19 @@ func (t Table) write(a *Apl, f *tableFormatter, rw rowWriter) error {
 		}
 		a.Fmt[t] = s
 	}
+	iscsv := false
+	switch rw.(type) {
+	case csvTable, *keyHeader:
+		iscsv = true
+	}
 	for n := 0; n < t.Rows; n++ {
 		for i, k := range keys {
 			v := t.At(a, k).(Array).At(n)
 			setnumformat(v, k)
-			if ar, ok := v.(Array); ok {
+			_, isobject := v.(Object)
+			if _, isarray := v.(Array); a.PP == -1 && (isarray || isobject) {
+				r[i] = dataItem(a, v)
+			} else if ar, ok := v.(Array); ok {
 				size := ar.Size()
 				vec := make([]string, size)
 				for j := 0; j < size; j++ {
@@ -111,6 +175,8 @@ func (t Table) write(a *Apl, f *tableFormatter, rw rowWriter) error {
 				if a.PP < 0 {
 					r[i] = "[" + r[i] + "]"
 				}
+			} else if iscsv && IsNull(v) {
+				r[i] = "" // Missing values are empty csv cells.
 			} else {
 				r[i] = v.String(a)
 			}
@@ -175,12 +241,65 @@ func (f *tableFormatter) Close() {
 	}
 }
 
+// ParseTable parses a table in the data format, that is written by ¯1⍕T.
+// The first line contains the keys, each following line a row of values.
+// If the prototype is a table, the result must have the same columns.
 func (a *Apl) ParseTable(prototype Value, s string) (Table, error) {
+	var pt Table
 	if prototype != nil {
-		_, ok := prototype.(Table)
+		t, ok := prototype.(Table)
 		if ok == false {
 			return Table{}, fmt.Errorf("ParseTable: prototype is not a table: %T", prototype)
 		}
+		pt = t
+	}
+
+	var keys []Value
+	p := newDataParser(a, s)
+	if p.emptyLines() {
+		for {
+			p.blanks()
+			if r := p.peek(); r == '\n' || r == -1 {
+				break
+			}
+			k, err := p.key(keys)
+			if err != nil {
+				return Table{}, fmt.Errorf("parse table: %s", err)
+			}
+			keys = append(keys, k)
+		}
+	}
+
+	cols := make([][]Value, len(keys))
+	rows := 0
+	for p.emptyLines() {
+		for i := range cols {
+			p.blanks()
+			if r := p.peek(); r == '\n' || r == -1 {
+				return Table{}, fmt.Errorf("parse table: row %d has %d values instead of %d", rows+1, i, len(cols))
+			}
+			v, err := p.item()
+			if err != nil {
+				return Table{}, fmt.Errorf("parse table: row %d: %s", rows+1, err)
+			}
+			cols[i] = append(cols[i], v)
+		}
+		if err := p.line(); err != nil {
+			return Table{}, fmt.Errorf("parse table: row %d has more than %d values", rows+1, len(cols))
+		}
+		rows++
+	}
+	t := p.columns(keys, cols, rows)
+
+	if pt.Dict != nil {
+		if len(pt.K) != len(t.K) {
+			return Table{}, fmt.Errorf("parse table: table has %d columns, prototype has %d", len(t.K), len(pt.K))
+		}
+		for _, k := range pt.K {
+			if _, ok := t.M[k]; ok == false {
+				return Table{}, fmt.Errorf("parse table: column is missing: %s", k.String(a))
+			}
+		}
 	}
-	return Table{}, fmt.Errorf("TODO ParseTable")
+	return t, nil
 }
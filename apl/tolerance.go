@@ -0,0 +1,49 @@
+package apl
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Floater is implemented by real numbers which can be converted to a float64.
+// It is used to set the comparison tolerance.
+type Floater interface {
+	ToFloat() (float64, bool)
+}
+
+// SetCT sets the comparison tolerance ⎕CT.
+// It is used by inexact number types to test for equality:
+//	|L-R| ≤ ⎕CT × (|L|⌈|R|)
+// Exact types (Int, Rat, Time) ignore it.
+// A value of 0 requests exact comparison for all types.
+func (a *Apl) SetCT(R Value) error {
+	var ct float64
+	if n, ok := R.(Number); ok == false {
+		return fmt.Errorf("illegal type for CT: %T", R)
+	} else if i, ok := n.ToIndex(); ok {
+		ct = float64(i)
+	} else if f, ok := n.(Floater); ok {
+		if ct, ok = f.ToFloat(); ok == false {
+			return fmt.Errorf("cannot convert CT to float: %s", n.String(a))
+		}
+	} else {
+		return fmt.Errorf("illegal type for CT: %T", R)
+	}
+	if ct < 0 || ct >= 1 {
+		return fmt.Errorf("CT is out of range: %v", ct)
+	}
+	a.CT = ct
+	return nil
+}
+
+// getCT returns the comparison tolerance as a number of the current tower.
+func (a *Apl) getCT() Value {
+	if a.CT == 0 {
+		return Int(0)
+	}
+	n, err := a.Tower.Parse(strconv.FormatFloat(a.CT, 'g', -1, 64))
+	if err != nil {
+		return Int(0)
+	}
+	return n.Number
+}
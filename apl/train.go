@@ -23,6 +23,15 @@ func (t train) String(a *Apl) string {
 }
 
 func (t train) Call(a *Apl, L, R Value) (Value, error) {
+	f, err := t.function(a)
+	if err != nil {
+		return nil, err
+	}
+	return f.Call(a, L, R)
+}
+
+// function evaluates the train elements and returns the train as an atop or a fork.
+func (t train) function(a *Apl) (Function, error) {
 	if len(t) < 2 {
 		return nil, fmt.Errorf("cannot call short train, length %d", len(t))
 	} else if len(t)%2 == 0 {
@@ -42,7 +51,7 @@ func (t train) Call(a *Apl, L, R Value) (Value, error) {
 		if len(t) > 3 {
 			f[1] = train(t[1:])
 		}
-		return f.Call(a, L, R)
+		return f, nil
 	} else {
 		// odd number: e f g h i j k → e f(g h(i j k)) ⍝ fork(fork(fork))
 		f := fork{}
@@ -60,7 +69,7 @@ func (t train) Call(a *Apl, L, R Value) (Value, error) {
 		if len(t) > 3 {
 			f[2] = train(t[2:])
 		}
-		return f.Call(a, L, R)
+		return f, nil
 	}
 }
 
@@ -110,13 +119,13 @@ func (fk fork) Call(a *Apl, L, R Value) (Value, error) {
 	var l Value
 	l = fk[0]
 	if fok {
-		if v, err := f.Call(a, L, R); err != nil { // TODO copy?
+		if v, err := f.Call(a, L, R); err != nil {
 			return nil, err
 		} else {
 			l = v
 		}
 	}
-	r, err := h.Call(a, L, R) // TODO copy?
+	r, err := h.Call(a, L, R)
 	if err != nil {
 		return nil, err
 	}
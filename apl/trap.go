@@ -0,0 +1,188 @@
+package apl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error numbers are used by error guards in lambda expressions: {0::⍵ ⋄ …}.
+// They follow the numbers used by Dyalog APL.
+// Error number 0 in an error guard catches all errors.
+const (
+	SyntaxError = 2
+	IndexError  = 3
+	RankError   = 4
+	LengthError = 5
+	ValueError  = 6
+	DomainError = 11
+	SystemError = 999
+)
+
+var errorNames = map[int]string{
+	SyntaxError: "SYNTAX ERROR",
+	IndexError:  "INDEX ERROR",
+	RankError:   "RANK ERROR",
+	LengthError: "LENGTH ERROR",
+	ValueError:  "VALUE ERROR",
+	DomainError: "DOMAIN ERROR",
+	SystemError: "SYSTEM ERROR",
+}
+
+// ClassError is an error with an error number, e.g. LengthError.
+// It is created with Errorf.
+type ClassError struct {
+	Class int
+	Msg   string
+}
+
+func (e ClassError) Error() string {
+	return e.Msg
+}
+
+// Errorf formats an error message like fmt.Errorf and classifies it with an error number.
+// The number is used by error guards and ⎕EN.
+// It is kept, if the error is wrapped with %w.
+// Errors that are not classified are domain errors.
+func Errorf(class int, format string, v ...interface{}) error {
+	return ClassError{Class: class, Msg: fmt.Sprintf(format, v...)}
+}
+
+// trapError is an error that occurred within a lambda expression.
+// It contains the error number and the expression that failed.
+// The most recent trapped error is available as ⎕EN and ⎕DM.
+type trapError struct {
+	Number int
+	Err    error
+	Expr   string
+}
+
+func (e *trapError) Error() string {
+	return e.Err.Error()
+}
+
+// Name returns the name of the error class, e.g. DOMAIN ERROR.
+func (e *trapError) Name() string {
+	if s, ok := errorNames[e.Number]; ok {
+		return s
+	}
+	return fmt.Sprintf("ERROR %d", e.Number)
+}
+
+// wrapError wraps err with the failing expression.
+// If err is already a *trapError, it is returned unchanged,
+// such that the innermost expression is kept.
+func wrapError(a *Apl, err error, e expr) *trapError {
+	if x, ok := err.(*trapError); ok {
+		return x
+	}
+	return &trapError{Number: errorNumber(err), Err: err, Expr: e.String(a)}
+}
+
+// errorNumber returns the error class of err, see Errorf.
+// Errors without a class are domain errors.
+func errorNumber(err error) int {
+	var e ClassError
+	if errors.As(err, &e) {
+		return e.Class
+	}
+	return DomainError
+}
+
+// syntaxError classifies an error of the scanner or the parser as a syntax error.
+func syntaxError(err error) error {
+	var e ClassError
+	if errors.As(err, &e) {
+		return err
+	}
+	return ClassError{Class: SyntaxError, Msg: err.Error()}
+}
+
+// errorTrap is an armed error guard within a lambda expression.
+// It catches errors with the given numbers, or any error, if the list contains 0.
+type errorTrap struct {
+	numbers []int
+	e       expr
+}
+
+// arm evaluates the error numbers of an error guard.
+func (g *guardExpr) arm(a *Apl) (errorTrap, error) {
+	v, err := g.cond.Eval(a)
+	if err != nil {
+		return errorTrap{}, err
+	}
+	t := errorTrap{e: g.e}
+	if n, ok := v.(Number); ok {
+		if i, ok := n.ToIndex(); ok {
+			t.numbers = []int{i}
+			return t, nil
+		}
+	} else if ar, ok := v.(Array); ok {
+		t.numbers = make([]int, ar.Size())
+		for k := range t.numbers {
+			if n, ok := ar.At(k).(Number); ok == false {
+				return errorTrap{}, fmt.Errorf("error guard: error numbers must be integers")
+			} else if i, ok := n.ToIndex(); ok == false {
+				return errorTrap{}, fmt.Errorf("error guard: error numbers must be integers")
+			} else {
+				t.numbers[k] = i
+			}
+		}
+		return t, nil
+	}
+	return errorTrap{}, fmt.Errorf("error guard: error numbers must be integers: %T", v)
+}
+
+// catches returns true, if the trap catches the error.
+func (t errorTrap) catches(e *trapError) bool {
+	for _, n := range t.numbers {
+		if n == 0 || n == e.Number {
+			return true
+		}
+	}
+	return false
+}
+
+// catch tries the armed error guards in reverse order.
+// If one of them catches the error, the error is stored and
+// the guard's expression is evaluated and returned.
+func catch(a *Apl, traps []errorTrap, err *trapError) (Value, bool, error) {
+	for i := len(traps) - 1; i >= 0; i-- {
+		if traps[i].catches(err) {
+			a.lastError = err
+			v, err := traps[i].e.Eval(a)
+			return v, true, err
+		}
+	}
+	return nil, false, nil
+}
+
+// protect evaluates a guarded expression within the scope of an error guard.
+// A panic is converted to a system error, that can be caught.
+func (g *guardExpr) protect(a *Apl) (v Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			v = nil
+			err = &trapError{Number: SystemError, Err: fmt.Errorf("panic: %v", r), Expr: g.String(a)}
+		}
+	}()
+	return g.Eval(a)
+}
+
+// errorNumber returns the error number of the last trapped error as ⎕EN.
+// It is 0, if no error has been trapped.
+func (a *Apl) errorNumber() Value {
+	if a.lastError == nil {
+		return Int(0)
+	}
+	return Int(a.lastError.Number)
+}
+
+// diagnosticMessage returns the last trapped error as ⎕DM.
+// It is a vector of 3 strings: the error name, the message and the failing expression.
+func (a *Apl) diagnosticMessage() Value {
+	if a.lastError == nil {
+		return StringArray{Dims: []int{0}}
+	}
+	e := a.lastError
+	return StringArray{Dims: []int{3}, Strings: []string{e.Name(), e.Error(), e.Expr}}
+}
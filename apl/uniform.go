@@ -94,10 +94,10 @@ func (a *Apl) Unify(A Array, uptype bool) (resultarray Array, resultok bool) {
 			}
 			return ar, true
 		} else if t0 == reflect.TypeOf(Bool(false)) {
-			ar := BoolArray{}.Make(CopyShape(A))
+			ar := NewBoolArray(CopyShape(A), nil)
 			for i := 0; i < A.Size(); i++ {
 				v := A.At(i)
-				ar.(BoolArray).Bools[i] = bool(v.(Bool))
+				ar.SetBool(i, bool(v.(Bool)))
 			}
 			return ar, true
 		} else if t0 == reflect.TypeOf(Int(0)) {
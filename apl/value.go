@@ -21,13 +21,84 @@ import "io"
 type Value interface {
 	String(*Apl) string
 
-	// TODO: should there be a Copy or Clone interface?
-	// All primitives would have to use it if present.
-
 	// TODO: should we require a serialization interface?
 	// or serialize optionally if a Value implements an Encoder?
 }
 
+// Copier is implemented by Values that hold mutable memory,
+// such as arrays, lists, dicts, tables and images.
+// Copy returns a deep copy, that does not share any memory with the original.
+//
+// Ownership:
+// Variables are copied on write.
+// Assignment stores the value without copying, it may be shared with other values.
+// Indexed, selective and reach assignment modify the value of the variable in place.
+// Before that, a shared value is replaced by a copy, that is owned by the variable:
+//	B←A ⋄ B[1]←5   ⍝ copies B once, does not modify A
+//	B[2]←6         ⍝ modifies B in place
+// The value is shared again, as soon as the variable is read, e.g. C←B or B[1]+B[2].
+// Values that are stored inside a variable by these assignments are copied.
+// The arguments ⍺ and ⍵ of a lambda function are shared with the caller's values.
+//
+// Primitive functions and operators never modify their arguments.
+// They may return their arguments or share memory with them in the result.
+// Values that cross a go routine, e.g. send over a channel, are copied.
+//
+// Values that do not implement Copier are considered immutable.
+// This is true for all scalars.
+// Objects from external packages, e.g. xgo, that are not Copiers
+// are shared by reference.
+type Copier interface {
+	Copy() Value
+}
+
+// Copy returns a deep copy of v, if it is a Copier.
+// Otherwise v is returned.
+func Copy(v Value) Value {
+	if c, ok := v.(Copier); ok {
+		return c.Copy()
+	}
+	return v
+}
+
+// Nullable is implemented by scalar types that have a missing value (null).
+// Nulls are used for missing data, e.g. empty csv cells, json null or kdb nulls.
+//	Int             0N    the smallest int
+//	String          ""    the empty string
+//	numbers.Float   0n    NaN
+//	numbers.Time    0Nt
+//	big.Int big.Rat 0N
+//	big.Float       0n
+// Uniform arrays hold nulls of their element type.
+//
+// Scalar functions propagate nulls: if an argument is null, the result is
+// the null of the argument type, e.g. 1+0N is 0N.
+// Comparison treats nulls as equal to each other and less than any other value.
+// Reductions with + × ⌊ ⌈ skip nulls: +/1 0N 2 is 3.
+// If all values are null, +/ and ×/ return their identity 0 and 1, ⌊/ and ⌈/ return null.
+// ⍰R tests for nulls and L⍰R replaces them with L.
+//
+// IsNull reports if the value is null, Null returns the null of the same type.
+type Nullable interface {
+	IsNull() bool
+	Null() Value
+}
+
+// IsNull returns true, if v is a null value.
+func IsNull(v Value) bool {
+	n, ok := v.(Nullable)
+	return ok && n.IsNull()
+}
+
+// NullString is used by number types to format a null value.
+// For json (PP=-2) it returns null, otherwise s.
+func NullString(a *Apl, s string) string {
+	if a != nil && a.PP == -2 {
+		return "null"
+	}
+	return s
+}
+
 // VarReader is implemented by Values that are able to parse from a Reader.
 // The ReadFrom method must return a new value of the same type.
 // The function should be able to parse the format of it's String method.
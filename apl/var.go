@@ -27,7 +27,19 @@ func (a *Apl) Assign(name string, v Value) error {
 }
 
 // AssignEnv assigns a variable in the given environment.
+// The value may be shared with other variables, see Copier.
 func (a *Apl) AssignEnv(name string, v Value, env *env) error {
+	return a.assignEnv(name, v, env, false)
+}
+
+// AssignOwned assigns a value, that is not shared with any other value.
+// This is a value returned by LookupOwned, that has been modified in place,
+// or a new value that was built from it.
+func (a *Apl) AssignOwned(name string, v Value, env *env) error {
+	return a.assignEnv(name, v, env, true)
+}
+
+func (a *Apl) assignEnv(name string, v Value, env *env, owned bool) error {
 	ok, isfunc := isVarname(name)
 	if ok == false {
 		return fmt.Errorf("variable name is not allowed: %s", name)
@@ -54,6 +66,16 @@ func (a *Apl) AssignEnv(name string, v Value, env *env) error {
 		return fmt.Errorf("cannot set index origin: %T", v)
 	} else if name == "⎕PP" {
 		return a.SetPP(v)
+	} else if name == "⎕CT" {
+		return a.SetCT(v)
+	} else if name == "⎕PAR" {
+		return a.SetParallel(v)
+	} else if name == "⍬" {
+		return fmt.Errorf("cannot assign to ⍬")
+	}
+
+	if isop, dyadic := scan.IsOperatorName(name); isop {
+		return a.assignOperator(name, v, dyadic)
 	}
 
 	if _, ok := v.(Function); ok && isfunc != true {
@@ -62,17 +84,23 @@ func (a *Apl) AssignEnv(name string, v Value, env *env) error {
 		return fmt.Errorf("only functions can be assigned to lowercase variables")
 	}
 
+	// Special case: Default left argument in lambda expressions:
+	// Do not overwrite the given argument.
+	// Modified or indexed assignment to ⍺ pass the environment.
 	if env == nil {
 		env = a.env
+		if name == "⍺" {
+			if x, _ := env.get("⍺"); x != nil {
+				return nil
+			}
+		}
 	}
 
-	// Special case: Default left argument in lambda expressions:
-	// Do not overwrite the given argument.
-	if name == "⍺" && env.vars["⍺"] != nil {
-		return nil
+	if owned {
+		env.setOwned(name, v)
+	} else {
+		env.set(name, v)
 	}
-
-	env.vars[name] = v
 	return nil
 }
 
@@ -91,20 +119,33 @@ func (a *Apl) LookupEnv(name string) (Value, *env) {
 		return Int(a.Origin), nil
 	} else if name == "⎕PP" {
 		return Int(a.PP), nil
+	} else if name == "⎕CT" {
+		return a.getCT(), nil
+	} else if name == "⎕PAR" {
+		return Int(a.Parallel), nil
+	} else if name == "⍬" {
+		return EmptyArray{}, nil
+	} else if name == "⎕EN" {
+		return a.errorNumber(), nil
+	} else if name == "⎕DM" {
+		return a.diagnosticMessage(), nil
 	}
 
 	if idx := strings.Index(name, "→"); idx != -1 {
 		prefix := name[:idx]
 		if strings.ToLower(prefix) == prefix {
 			return a.packageVar(name), nil
-		} else {
-			return nil, nil
 		}
+		// A member of an object, e.g. a table column: T→Col.
+		if obj, ok := a.Lookup(prefix).(Object); ok {
+			return obj.At(a, String(name[idx+len("→"):])), nil
+		}
+		return nil, nil
 	}
 
 	e := a.env
 	for {
-		v, ok := e.vars[name]
+		v, ok := e.get(name)
 		if ok {
 			return v, e
 		}
@@ -116,6 +157,21 @@ func (a *Apl) LookupEnv(name string) (Value, *env) {
 	return nil, nil
 }
 
+// LookupOwned returns the value of a variable for modification in place
+// and a pointer to the environment, where it was found.
+// If the value is shared, the variable is set to a copy first, see Copier.
+func (a *Apl) LookupOwned(name string) (Value, *env) {
+	if strings.HasPrefix(name, "⎕") || strings.Contains(name, "→") || name == "⍬" {
+		return a.LookupEnv(name)
+	}
+	for e := a.env; e != nil; e = e.parent {
+		if v, ok := e.own(name); ok {
+			return v, e
+		}
+	}
+	return nil, nil
+}
+
 // Vars returns a list of variable names in a package.
 // If pkg is empty, the variables the root environment are returned,
 // and a list of packages ending with "/".
@@ -123,20 +179,19 @@ func (a *Apl) Vars(pkg string) ([]string, error) {
 	var l []string
 	var e *env
 	if pkg == "" {
+		a.mu.RLock()
 		for n := range a.pkg {
 			l = append(l, n+"/")
 		}
+		a.mu.RUnlock()
 		e = a.env
 	} else {
-		var ok bool
-		e, ok = a.pkg[pkg]
-		if ok == false {
+		e = a.lookupPkg(pkg)
+		if e == nil {
 			return nil, fmt.Errorf("package %s is not registered", pkg)
 		}
 	}
-	for n := range e.vars {
-		l = append(l, n)
-	}
+	l = append(l, e.names()...)
 	sort.Strings(l)
 	return l, nil
 }
@@ -148,11 +203,12 @@ func (a *Apl) packageVar(name string) Value {
 	}
 	pkgname := name[:idx]
 	varname := name[idx+len("→"):]
-	pkg, ok := a.pkg[pkgname]
-	if ok == false {
+	pkg := a.lookupPkg(pkgname)
+	if pkg == nil {
 		return nil
 	}
-	return pkg.vars[varname]
+	v, _ := pkg.get(varname)
+	return v
 }
 
 // NumVar contains the identifier to a value.
@@ -188,18 +244,23 @@ func (f fnVar) Eval(a *Apl) (Value, error) {
 }
 
 func (f fnVar) Call(a *Apl, l, r Value) (Value, error) {
+	v, _, err := f.callShy(a, l, r)
+	return v, err
+}
+
+func (f fnVar) callShy(a *Apl, l, r Value) (Value, bool, error) {
 	x := a.Lookup(string(f))
 	if x == nil {
-		return Identifier(f), nil
+		return Identifier(f), false, nil
 	}
 	fn, ok := x.(Function)
 	if ok == false {
-		return nil, fmt.Errorf("value in function variable is not a function: %T", x)
+		return nil, false, fmt.Errorf("value in function variable is not a function: %T", x)
 	}
 	if fn == nil {
-		return nil, fmt.Errorf("value in function variable %s is nil", string(f))
+		return nil, false, fmt.Errorf("value in function variable %s is nil", string(f))
 	}
-	return fn.Call(a, l, r)
+	return callShy(a, fn, l, r)
 }
 
 // isVarname returns if the string is allowed as a variable name and
@@ -208,6 +269,12 @@ func isVarname(s string) (ok, isfunc bool) {
 	if s == "" {
 		return false, false
 	}
+	if s == "⍺⍺" || s == "⍵⍵" {
+		return true, true
+	}
+	if s == "⍬" {
+		return true, false
+	}
 	if n := strings.Index(s, "→"); n != -1 {
 		s = s[n+len("→"):]
 	}
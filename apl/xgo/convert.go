@@ -43,16 +43,34 @@ func export(a *apl.Apl, v apl.Value, t reflect.Type) (reflect.Value, error) {
 	switch t.Kind() {
 
 	case reflect.Int:
-		return reflect.ValueOf(int(v.(apl.Int))), nil
+		if n, ok := v.(apl.Number); ok {
+			if i, ok := n.ToIndex(); ok {
+				return reflect.ValueOf(i), nil
+			}
+		}
 
 	case reflect.Float64:
-		return reflect.ValueOf(float64(v.(numbers.Float))), nil
+		switch n := v.(type) {
+		case numbers.Float:
+			return reflect.ValueOf(float64(n)), nil
+		case apl.Int:
+			return reflect.ValueOf(float64(n)), nil
+		}
 
 	case reflect.Complex128:
-		return reflect.ValueOf(complex128(v.(numbers.Complex))), nil
+		switch n := v.(type) {
+		case numbers.Complex:
+			return reflect.ValueOf(complex128(n)), nil
+		case numbers.Float:
+			return reflect.ValueOf(complex(float64(n), 0)), nil
+		case apl.Int:
+			return reflect.ValueOf(complex(float64(n), 0)), nil
+		}
 
 	case reflect.String:
-		return reflect.ValueOf(string(v.(apl.String))), nil
+		if s, ok := v.(apl.String); ok {
+			return reflect.ValueOf(string(s)), nil
+		}
 
 	case reflect.Slice:
 		ar, ok := v.(apl.Array)
@@ -71,10 +89,8 @@ func export(a *apl.Apl, v apl.Value, t reflect.Type) (reflect.Value, error) {
 			}
 		}
 		return s, nil
-
-	default:
-		return zero, fmt.Errorf("cannot convert to %v", t)
 	}
+	return zero, fmt.Errorf("cannot convert %T to %v", v, t)
 }
 
 // convert converts a go value to an apl value.
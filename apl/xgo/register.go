@@ -100,6 +100,7 @@ func (_ echo) Call(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 	} else {
 		p = string(s)
 	}
+	a = a.Fork()
 	c := apl.NewChannel()
 	go func(c apl.Channel) {
 		var buf []apl.Value
@@ -2,6 +2,7 @@ package q
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/ktye/iv/apl"
@@ -21,7 +22,7 @@ func FromAPL(a *apl.Apl, v apl.Value) (*kdb.K, error) {
 		if len(x.Dims) != 1 {
 			return encodeArray(a, v)
 		}
-		return &kdb.K{kdb.KB, kdb.NONE, x.Bools}, nil
+		return &kdb.K{kdb.KB, kdb.NONE, x.Bools()}, nil
 	case apl.IntArray:
 		if len(x.Dims) != 1 {
 			return encodeArray(a, v)
@@ -44,12 +45,16 @@ func FromAPL(a *apl.Apl, v apl.Value) (*kdb.K, error) {
 		}
 		return kdb.FloatV(x.Floats), nil
 	case numbers.Time:
-		return &kdb.K{-kdb.KP, kdb.NONE, time.Time(x)}, nil
+		return &kdb.K{-kdb.KP, kdb.NONE, fromTime(x)}, nil
 	case numbers.TimeArray:
 		if len(x.Dims) != 1 {
 			return encodeArray(a, v)
 		}
-		return &kdb.K{kdb.KP, kdb.NONE, x.Times}, nil
+		vec := make([]time.Time, len(x.Times))
+		for i, t := range x.Times {
+			vec[i] = fromTime(numbers.Time(t))
+		}
+		return &kdb.K{kdb.KP, kdb.NONE, vec}, nil
 	case apl.List:
 		return encodeList(a, v)
 	case apl.Table:
@@ -176,9 +181,9 @@ func ToAPL(data *kdb.K) (apl.Value, error) {
 	case -kdb.KB:
 		return apl.Bool(k.(bool)), nil
 	case -kdb.KH:
-		return apl.Int(k.(int16)), nil
+		return toInt(int64(k.(int16)), math.MinInt16), nil
 	case -kdb.KI, -kdb.KD, -kdb.KU, -kdb.KV:
-		return apl.Int(k.(int32)), nil
+		return toInt(int64(k.(int32)), math.MinInt32), nil
 	case -kdb.KJ:
 		return apl.Int(int(k.(int64))), nil
 	case -kdb.KS:
@@ -187,19 +192,19 @@ func ToAPL(data *kdb.K) (apl.Value, error) {
 		return numbers.Float(k.(float64)), nil
 	case kdb.KB:
 		v := k.([]bool)
-		return apl.BoolArray{Dims: []int{len(v)}, Bools: v}, nil
+		return apl.NewBoolArray([]int{len(v)}, v), nil
 	case kdb.KH:
 		vec := k.([]int16)
 		ints := make([]int, len(vec))
 		for i := range vec {
-			ints[i] = int(vec[i])
+			ints[i] = int(toInt(int64(vec[i]), math.MinInt16))
 		}
 		return apl.IntArray{Dims: []int{len(ints)}, Ints: ints}, nil
 	case kdb.KI:
 		vec := k.([]int32)
 		ints := make([]int, len(vec))
 		for i := range vec {
-			ints[i] = int(vec[i])
+			ints[i] = int(toInt(int64(vec[i]), math.MinInt32))
 		}
 		return apl.IntArray{Dims: []int{len(ints)}, Ints: ints}, nil
 	case kdb.KJ:
@@ -216,10 +221,14 @@ func ToAPL(data *kdb.K) (apl.Value, error) {
 		vec := k.([]string)
 		return apl.StringArray{Dims: []int{len(vec)}, Strings: vec}, nil
 	case -kdb.KP:
-		return numbers.Time(k.(time.Time)), nil
+		return toTime(k.(time.Time)), nil
 	case kdb.KP:
 		vec := k.([]time.Time)
-		return numbers.TimeArray{Dims: []int{len(vec)}, Times: vec}, nil
+		times := make([]time.Time, len(vec))
+		for i := range vec {
+			times[i] = time.Time(toTime(vec[i]))
+		}
+		return numbers.TimeArray{Dims: []int{len(times)}, Times: times}, nil
 	case kdb.XD:
 		return decodeDict(k)
 	case kdb.XT:
@@ -231,6 +240,34 @@ func ToAPL(data *kdb.K) (apl.Value, error) {
 	}
 }
 
+// qNullTime is the kdb null timestamp 0Np, the smallest int64 nanoseconds since 2000.01.01.
+var qNullTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(math.MinInt64))
+
+// toInt converts a kdb integer to an apl.Int and maps the kdb null to apl.NullInt.
+// Long nulls are already equal to NullInt.
+func toInt(i, null int64) apl.Int {
+	if i == null {
+		return apl.NullInt
+	}
+	return apl.Int(i)
+}
+
+// toTime maps the kdb null timestamp to the null value of numbers.Time.
+func toTime(t time.Time) numbers.Time {
+	if t.Equal(qNullTime) {
+		return numbers.Time{}.Null().(numbers.Time)
+	}
+	return numbers.Time(t)
+}
+
+// fromTime maps the null value of numbers.Time to the kdb null timestamp.
+func fromTime(t numbers.Time) time.Time {
+	if t.IsNull() {
+		return qNullTime
+	}
+	return time.Time(t)
+}
+
 func decodeDict(k interface{}) (apl.Value, error) {
 	xd := k.(kdb.Dict)
 	kv, ok := xd.Key.Data.([]string)
@@ -29,7 +29,9 @@ func setCallback(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	return nil, fmt.Errorf("u f: argument must be a list containing a single function")
 }
 
+// The callback is called by the ui loop and evaluates on a fork.
 func setcb(a *apl.Apl, w ui.Widget, f apl.Function) (apl.Value, error) {
+	a = a.Fork()
 	switch v := w.(type) {
 	case *ui.Button:
 		fmt.Printf("u: set callback of button %p\n", v)
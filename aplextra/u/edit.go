@@ -23,6 +23,9 @@ import (
 //
 // Other values are converted to strings.
 func sam(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	// Callbacks are called by the ui loop and evaluate on a fork.
+	a = a.Fork()
+
 	// Callback for button-2 exec:
 	// Open files from the apl file system.
 	exec := func(sam *ui.Sam, s string) {
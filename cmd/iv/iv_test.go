@@ -12,7 +12,9 @@ import (
 	"testing"
 )
 
+// TestIv runs the programs in testdata with the interpreter and compiled.
 func TestIv(t *testing.T) {
+	defer func() { compile = false }()
 	d, err := os.Open("testdata")
 	if err != nil {
 		t.Fatal(err)
@@ -29,8 +31,50 @@ func TestIv(t *testing.T) {
 			continue
 		}
 
-		if err := testfile(file); err != nil {
-			t.Fatal(err)
+		for _, c := range []bool{false, true} {
+			compile = c
+			if err := testfile(file); err != nil {
+				t.Fatalf("compile=%v: %s", c, err)
+			}
+		}
+	}
+}
+
+// BenchmarkIv runs the programs in testdata with the input repeated 100 times,
+// compiled and with the interpreter.
+func BenchmarkIv(b *testing.B) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.iv"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer func() { compile = false }()
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			b.Fatal(err)
+		}
+		r := bufio.NewReader(bytes.NewReader(data))
+		prog, err := readline(r)
+		if err != nil {
+			b.Fatal(err)
+		}
+		input, _ := ioutil.ReadAll(r)
+		input = bytes.Repeat(append(bytes.TrimSpace(input), "\n\n"...), 100)
+
+		for _, c := range []bool{false, true} {
+			name := filepath.Base(file) + "/interpreted"
+			if c {
+				name = filepath.Base(file) + "/compiled"
+			}
+			b.Run(name, func(b *testing.B) {
+				compile = c
+				for i := 0; i < b.N; i++ {
+					stdin = ioutil.NopCloser(bytes.NewReader(input))
+					if err := iv(prog[1:], ioutil.Discard); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
 		}
 	}
 }
@@ -1,7 +1,9 @@
 // APL stream processor.
 //
 // Usage
-//	cat data | iv COMMANDS
+//	cat data | iv [-c] COMMANDS
+//
+// The option -c compiles the program before evaluation, see apl.Compile.
 package main
 
 import (
@@ -21,10 +23,15 @@ import (
 var stdin io.ReadCloser = os.Stdin
 
 func main() {
-	if len(os.Args) < 2 {
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "-c" {
+		compile = true
+		args = args[1:]
+	}
+	if len(args) < 1 {
 		fatal(fmt.Errorf("arguments expected"))
 	}
-	fatal(iv(strings.Join(os.Args[1:], " "), os.Stdout))
+	fatal(iv(strings.Join(args, " "), os.Stdout))
 }
 
 func iv(p string, w io.Writer) error {
@@ -44,10 +51,26 @@ func iv(p string, w io.Writer) error {
 		domain.Monadic(domain.ToIndex(nil)),
 		"read fd",
 	))
-	if err := a.ParseAndEval(`r←{<⍤⍵<0}⋄s←{⍵⍴<⍤0<0}`); err != nil {
+	if err := eval(a, `r←{<⍤⍵<0}⋄s←{⍵⍴<⍤0<0}`); err != nil {
 		return err
 	}
-	return a.ParseAndEval(p)
+	return eval(a, p)
+}
+
+// compile enables compiled programs, see apl.Compile.
+// It is set by the option -c.
+// The compiler is not faster for the programs in testdata, see BenchmarkIv.
+var compile = false
+
+func eval(a *apl.Apl, s string) error {
+	p, err := a.Parse(s)
+	if err != nil {
+		return err
+	}
+	if compile {
+		p = a.Compile(p)
+	}
+	return a.Eval(p)
 }
 
 func fatal(err error) {